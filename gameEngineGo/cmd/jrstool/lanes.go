@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/script"
+)
+
+// movieExtension is appended to movie references (matches Qt QScript::action_run)
+const movieExtension = ".WMV"
+
+// Lanes of the scene timeline: what an action occupies while it runs
+const (
+	laneBG     = "bg"
+	laneMovie  = "movie"
+	laneFade   = "fade"
+	laneBGM    = "bgm"
+	laneES     = "es"
+	laneVoice  = "voice"
+	laneText   = "text"
+	laneSelect = "select"
+	laneSom    = "som"
+	laneOther  = "other"
+)
+
+// laneOrder is the display order of the fixed lanes; SE channels follow BGM,
+// before the ambience
+var laneOrder = []string{laneBG, laneMovie, laneFade, laneBGM, laneES, laneVoice, laneText, laneSelect, laneSom, laneOther}
+
+// actionLane returns the layer or channel an action plays on, or "" for the
+// SkipFRAME and Next markers, which take no time
+func actionLane(action *script.Action) string {
+	switch action.Action {
+	case script.ActionSkipFrame, script.ActionNext:
+		return ""
+	case script.ActionCreateBG:
+		return laneBG
+	case script.ActionPlayMovie, script.ActionEndRoll:
+		return laneMovie
+	case script.ActionBlackFade, script.ActionWhiteFade:
+		return laneFade
+	case script.ActionPlayBgm, script.ActionEndBGM:
+		return laneBGM
+	case script.ActionPlaySe:
+		// One mixer channel per script layer (matches engine seChannel)
+		return fmt.Sprintf("se%d", max(action.Layer, 0))
+	case script.ActionPlayES:
+		return laneES
+	case script.ActionPlayVoice:
+		return laneVoice
+	case script.ActionPrintText:
+		return laneText
+	case script.ActionSetSelect:
+		return laneSelect
+	case script.ActionMoveSom:
+		return laneSom
+	default:
+		return laneOther
+	}
+}
+
+// laneRank returns the sort key of a lane: fixed lanes at even ranks, SE
+// channels by number right after BGM
+func laneRank(lane string) (int, int) {
+	var channel int
+	if _, err := fmt.Sscanf(lane, "se%d", &channel); err == nil {
+		for i, name := range laneOrder {
+			if name == laneBGM {
+				return 2*i + 1, channel
+			}
+		}
+	}
+	for i, name := range laneOrder {
+		if name == lane {
+			return 2 * i, 0
+		}
+	}
+	return 2 * len(laneOrder), 0
+}
+
+// sortLanes sorts lane names in display order
+func sortLanes(lanes []string) {
+	sort.Slice(lanes, func(i, j int) bool {
+		ri, ci := laneRank(lanes[i])
+		rj, cj := laneRank(lanes[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ci < cj
+	})
+}
+
+// findOverlaps returns, for each action starting more than tolerance ms
+// before the previous action on its lane ended, the action it cuts short.
+// Actions must be sorted by start time, as parsed.
+func findOverlaps(actions []*script.Action, tolerance int64) map[*script.Action]*script.Action {
+	overlaps := make(map[*script.Action]*script.Action)
+	running := make(map[string]*script.Action) // Lane -> action ending last on it
+	for _, action := range actions {
+		lane := actionLane(action)
+		if lane == "" || lane == laneES {
+			continue // Ambience tracks mix on channels of their own
+		}
+		previous := running[lane]
+		if previous != nil && action.Start < actionEnd(previous)-tolerance {
+			overlaps[action] = previous
+		}
+		if previous == nil || actionEnd(action) >= actionEnd(previous) {
+			running[lane] = action
+		}
+	}
+	return overlaps
+}
+
+// actionEnd returns where an action stops occupying its lane: its end, or
+// its start for actions without one
+func actionEnd(action *script.Action) int64 {
+	if action.HasEnd() && action.End > action.Start {
+		return action.End
+	}
+	return action.Start
+}
+
+// assetName returns the filesystem name of the asset an action references,
+// or "" if it has none
+func assetName(action *script.Action) string {
+	if action.File == "" {
+		return ""
+	}
+	switch action.Action {
+	case script.ActionPlayMovie, script.ActionEndRoll:
+		return filesystem.NormalizeName(action.File) + movieExtension
+	default:
+		return filesystem.NormalizeName(action.File)
+	}
+}
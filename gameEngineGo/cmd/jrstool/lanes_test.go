@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/script"
+)
+
+func TestLanes(t *testing.T) {
+	parsed, err := script.ParseJRS(strings.NewReader(`[
+{"action":"PlayES","start":0,"end":2000,"file":"Se00/AMB01"},
+{"action":"PlaySe","start":0,"end":500,"file":"Se00/SE01"},
+{"action":"PlaySe","start":100,"end":600,"layer":3,"file":"Se00/SE02"},
+{"action":"PlayES","start":500,"end":2000,"file":"Se00/AMB02"},
+{"action":"PlaySe","start":200,"end":400,"file":"Se00/SE03"},
+{"action":"PlayBgm","start":0,"file":"BGM/SD_BGM01"}
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, action := range parsed.Actions {
+		if lane := actionLane(action); !seen[lane] {
+			seen[lane] = true
+			names = append(names, lane)
+		}
+	}
+	sortLanes(names)
+	if got := strings.Join(names, ","); got != "bgm,se0,se3,es" {
+		t.Errorf("lanes %s, want bgm,se0,se3,es", got)
+	}
+
+	// Only the sound cut short on its layer overlaps; ambience tracks mix
+	overlaps := findOverlaps(parsed.Actions, 0)
+	if len(overlaps) != 1 {
+		t.Errorf("%d overlaps, want 1", len(overlaps))
+	}
+	for action, previous := range overlaps {
+		if action.File != "Se00/SE03" || previous.File != "Se00/SE01" {
+			t.Errorf("%s overlaps %s, want Se00/SE03 over Se00/SE01", action.File, previous.File)
+		}
+	}
+}
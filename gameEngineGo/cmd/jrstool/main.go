@@ -0,0 +1,65 @@
+// Command jrstool works with the JRS scripts of the game: it exports the
+// text for translation, imports the translations back, validates the
+// scripts against the game files and draws their timelines.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// commands maps subcommand names to their functions
+var commands = map[string]func(args []string) error{
+	"export":   runExport,
+	"import":   runImport,
+	"status":   runStatus,
+	"timeline": runTimeline,
+	"validate": runValidate,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "jrstool: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "jrstool: %v\n", err)
+		var invalid usageError
+		if errors.As(err, &invalid) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// usageError is an invalid command line; main exits with status 2 for it,
+// like for an unknown command
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// usage prints the available commands
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: jrstool <command> [flags]
+
+Commands:
+  export    write the texts and answers of a JRS tree as a PO file
+  import    patch the translations of a PO file into a copy of a JRS tree
+  status    report untranslated and changed strings of a PO file
+  timeline  draw a script as lanes of actions over time as text, SVG or HTML
+  validate  check the scripts of a game directory for missing assets,
+            bad timings, overlaps, voices without text and route targets
+
+Run jrstool <command> -h for the flags of a command.`)
+}
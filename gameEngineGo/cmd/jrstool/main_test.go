@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+	}{
+		{"export", nil},
+		{"import", []string{"-src", "Script/RUSSIAN", "-po", "en.po"}},
+		{"import", []string{"-po", "en.po", "-o", "Script/ENGLISH"}},
+		{"status", []string{"-src", "Script/RUSSIAN"}},
+		{"status", []string{"-po", "en.po"}},
+		{"validate", []string{"-root", "testdata/game", "-format", "xml"}},
+		{"timeline", []string{"-format", "pdf", timelineFixture}},
+		{"timeline", []string{timelineFixture, timelineFixture}},
+	}
+
+	for _, test := range tests {
+		err := commands[test.command](test.args)
+		var invalid usageError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s %v: got %v, want a usage error", test.command, test.args, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// poEntry is one message of a gettext PO file
+type poEntry struct {
+	Comments  []string // Extracted comments ("#." lines)
+	Reference string   // "#:" line
+	Fuzzy     bool
+	Context   string // msgctxt, the stable key of the string
+	ID        string // msgid, the source text
+	Str       string // msgstr, the translation
+}
+
+// poHeader is the header entry written at the top of PO files
+const poHeader = "Content-Type: text/plain; charset=UTF-8\n"
+
+// writePO writes entries as a PO file
+func writePO(w io.Writer, entries []*poEntry) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "msgid \"\"\nmsgstr %s\n", quotePO(poHeader))
+
+	for _, entry := range entries {
+		out.WriteString("\n")
+		for _, comment := range entry.Comments {
+			fmt.Fprintf(out, "#. %s\n", comment)
+		}
+		if entry.Reference != "" {
+			fmt.Fprintf(out, "#: %s\n", entry.Reference)
+		}
+		if entry.Fuzzy {
+			out.WriteString("#, fuzzy\n")
+		}
+		fmt.Fprintf(out, "msgctxt %s\n", quotePO(entry.Context))
+		fmt.Fprintf(out, "msgid %s\n", quotePO(entry.ID))
+		fmt.Fprintf(out, "msgstr %s\n", quotePO(entry.Str))
+	}
+	return out.Flush()
+}
+
+// readPO reads the entries of a PO file, skipping the header
+func readPO(r io.Reader) ([]*poEntry, error) {
+	var entries []*poEntry
+	entry := &poEntry{}
+	var target *string // Field continued by string lines
+	started := false
+
+	finish := func() {
+		if started && !(entry.ID == "" && entry.Context == "") {
+			entries = append(entries, entry)
+		}
+		entry = &poEntry{}
+		target = nil
+		started = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			finish()
+		case strings.HasPrefix(line, "#."):
+			entry.Comments = append(entry.Comments, strings.TrimSpace(line[2:]))
+		case strings.HasPrefix(line, "#:"):
+			entry.Reference = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "#,"):
+			entry.Fuzzy = entry.Fuzzy || strings.Contains(line, "fuzzy")
+		case strings.HasPrefix(line, "#"):
+			// Translator comment or obsolete entry
+		case strings.HasPrefix(line, "\""):
+			if target == nil {
+				return nil, fmt.Errorf("line %d: string without a keyword", lineNumber)
+			}
+			value, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			*target += value
+		default:
+			keyword, quoted, _ := strings.Cut(line, " ")
+			if keyword == "msgctxt" && started {
+				finish() // Entries without a blank line between them
+			}
+
+			switch keyword {
+			case "msgctxt":
+				target = &entry.Context
+			case "msgid":
+				target = &entry.ID
+			case "msgstr":
+				target = &entry.Str
+			default:
+				return nil, fmt.Errorf("line %d: unknown keyword %q", lineNumber, keyword)
+			}
+
+			value, err := strconv.Unquote(strings.TrimSpace(quoted))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			*target = value
+			started = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	finish()
+
+	return entries, nil
+}
+
+// quotePO quotes a PO string
+func quotePO(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/script"
+)
+
+// timelineBar is an action drawn on its lane
+type timelineBar struct {
+	Action   *script.Action
+	Start    int64
+	End      int64
+	Overlaps *script.Action // Action on the lane this one cuts short, or nil
+}
+
+// timelineSpan is a stretch of a lane in ms
+type timelineSpan struct {
+	Start int64
+	End   int64
+}
+
+// timelineLane is one layer or channel of a scene
+type timelineLane struct {
+	Name string
+	Bars []*timelineBar
+	Gaps []timelineSpan // Silences between actions
+}
+
+// timeline is a scene laid out by lane for display
+type timeline struct {
+	Name    string
+	From    int64 // Shown window in ms
+	To      int64
+	Lanes   []*timelineLane
+	Markers []*script.Action // SkipFRAME and Next
+}
+
+// sceneEnd returns where the last action of a script stops, in ms
+func sceneEnd(parsed *script.Script) int64 {
+	var end int64
+	for _, action := range parsed.Actions {
+		end = max(end, actionEnd(action))
+	}
+	return end
+}
+
+// buildTimeline lays out the actions of a script between from and to ms.
+// Gaps shorter than minGap ms are not shown.
+func buildTimeline(parsed *script.Script, from, to, minGap, tolerance int64) *timeline {
+	tl := &timeline{Name: parsed.Name, From: from, To: to}
+
+	visible := func(start, end int64) bool {
+		return end >= from && start <= to
+	}
+
+	overlaps := findOverlaps(parsed.Actions, tolerance)
+	lanes := make(map[string]*timelineLane)
+	covered := make(map[string]int64) // Lane -> end of the actions so far
+	var names []string
+	for _, action := range parsed.Actions {
+		lane := actionLane(action)
+		if lane == "" {
+			if visible(action.Start, action.Start) {
+				tl.Markers = append(tl.Markers, action)
+			}
+			continue
+		}
+
+		l := lanes[lane]
+		if l == nil {
+			l = &timelineLane{Name: lane}
+			lanes[lane] = l
+			names = append(names, lane)
+		} else if start, end := covered[lane], action.Start; end > start && end-start >= minGap && visible(start, end) {
+			l.Gaps = append(l.Gaps, timelineSpan{max(start, from), min(end, to)})
+		}
+		if end := actionEnd(action); end > covered[lane] {
+			covered[lane] = end
+		}
+
+		if visible(action.Start, actionEnd(action)) {
+			l.Bars = append(l.Bars, &timelineBar{
+				Action:   action,
+				Start:    max(action.Start, from),
+				End:      min(actionEnd(action), to),
+				Overlaps: overlaps[action],
+			})
+		}
+	}
+
+	sortLanes(names)
+	for _, name := range names {
+		if l := lanes[name]; len(l.Bars) > 0 || len(l.Gaps) > 0 {
+			tl.Lanes = append(tl.Lanes, l)
+		}
+	}
+	return tl
+}
+
+// overlapSpan returns the part of a bar that runs over the action it cuts short
+func (b *timelineBar) overlapSpan() timelineSpan {
+	return timelineSpan{b.Start, min(b.End, actionEnd(b.Overlaps))}
+}
+
+// formatMs formats a time in ms as m:ss.mmm
+func formatMs(ms int64) string {
+	sign := ""
+	if ms < 0 {
+		sign, ms = "-", -ms
+	}
+	return fmt.Sprintf("%s%d:%02d.%03d", sign, ms/60000, ms/1000%60, ms%1000)
+}
+
+// actionLabel describes an action for tooltips and listings
+func actionLabel(action *script.Action) string {
+	label := fmt.Sprintf("%s #%d", action.Action, action.Index)
+	switch {
+	case action.Action == script.ActionPrintText:
+		label += ": " + shorten(action.Persona+": "+action.Text, 60)
+	case action.Action == script.ActionSetSelect:
+		label += ": " + shorten(action.Answer1+" / "+action.Answer2, 60)
+	case action.File != "":
+		label += " " + action.File
+	}
+	if action.Direction != "" {
+		label += " " + action.Direction
+	}
+	return label
+}
+
+// shorten cuts a string to at most n runes
+func shorten(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:n-1]) + "…"
+}
+
+// tickStep returns a round axis step giving about count ticks over span ms
+func tickStep(span int64, count int64) int64 {
+	raw := span / count
+	step := int64(1)
+	for {
+		for _, factor := range []int64{1, 2, 5} {
+			if step*factor >= raw {
+				return step * factor
+			}
+		}
+		step *= 10
+	}
+}
+
+// Cells of the text timeline
+const (
+	cellEmpty   = ' '
+	cellBar     = '='
+	cellInstant = '|'
+	cellOverlap = 'X'
+	cellGap     = '.'
+	cellSkip    = 'S'
+	cellNext    = 'N'
+)
+
+// cellColors are the ANSI colors of the cells
+var cellColors = map[byte]string{
+	cellBar:     "32",
+	cellInstant: "36",
+	cellOverlap: "1;31",
+	cellGap:     "33",
+	cellSkip:    "35",
+	cellNext:    "35",
+}
+
+// textLabelWidth is the width of the lane names column
+const textLabelWidth = 8
+
+// renderText writes a timeline as columns of text, one row per lane, with
+// optional ANSI colors
+func renderText(w io.Writer, tl *timeline, width int, color bool) error {
+	var out bytes.Buffer
+	step := (tl.To - tl.From + int64(width) - 1) / int64(width)
+	if step < 1 {
+		step = 1
+	}
+	column := func(ms int64) int {
+		return int(min((ms-tl.From)/step, int64(width-1)))
+	}
+	paint := func(cells []byte, start, end int64, cell byte) {
+		if end <= start {
+			if cells[column(start)] == cellEmpty || cells[column(start)] == cellGap {
+				cells[column(start)] = cellInstant
+			}
+			return
+		}
+		for i := column(start); i <= column(end-1); i++ {
+			cells[i] = cell
+		}
+	}
+	row := func(label string, cells []byte) {
+		fmt.Fprintf(&out, "%-*s ", textLabelWidth, label)
+		for i := 0; i < len(cells); {
+			j := i
+			for j < len(cells) && cells[j] == cells[i] {
+				j++
+			}
+			if code, ok := cellColors[cells[i]]; ok && color {
+				fmt.Fprintf(&out, "\x1b[%sm%s\x1b[0m", code, cells[i:j])
+			} else {
+				out.Write(cells[i:j])
+			}
+			i = j
+		}
+		out.WriteByte('\n')
+	}
+
+	fmt.Fprintf(&out, "%s  %s - %s  (%d ms per column)\n\n", tl.Name, formatMs(tl.From), formatMs(tl.To), step)
+
+	// Time axis with a label every ten columns
+	axis := []byte(strings.Repeat(" ", width+len(formatMs(tl.To))))
+	for i := 0; i < width; i += 10 {
+		copy(axis[i:], formatMs(tl.From+int64(i)*step))
+	}
+	fmt.Fprintf(&out, "%-*s %s\n", textLabelWidth, "ms", strings.TrimRight(string(axis), " "))
+
+	if len(tl.Markers) > 0 {
+		cells := bytes.Repeat([]byte{cellEmpty}, width)
+		for _, marker := range tl.Markers {
+			if marker.Action == script.ActionSkipFrame {
+				cells[column(marker.Start)] = cellSkip
+			} else if cells[column(marker.Start)] != cellSkip {
+				cells[column(marker.Start)] = cellNext // SkipFRAME shows where both fall
+			}
+		}
+		row("markers", cells)
+	}
+
+	for _, lane := range tl.Lanes {
+		cells := bytes.Repeat([]byte{cellEmpty}, width)
+		for _, gap := range lane.Gaps {
+			paint(cells, gap.Start, gap.End, cellGap)
+		}
+		for _, bar := range lane.Bars {
+			paint(cells, bar.Start, bar.End, cellBar)
+		}
+		for _, bar := range lane.Bars {
+			if bar.Overlaps != nil {
+				span := bar.overlapSpan()
+				paint(cells, span.Start, span.End, cellOverlap)
+			}
+		}
+		row(lane.Name, cells)
+	}
+
+	fmt.Fprintf(&out, "\n%c running  %c instant  %c overlap  %c gap  %c SkipFRAME  %c Next\n\n",
+		cellBar, cellInstant, cellOverlap, cellGap, cellSkip, cellNext)
+	writeTimelineNotes(&out, tl)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeTimelineNotes lists the markers, overlaps and gaps of a timeline
+func writeTimelineNotes(w io.Writer, tl *timeline) {
+	if len(tl.Markers) > 0 {
+		fmt.Fprintln(w, "Markers:")
+		for _, marker := range tl.Markers {
+			fmt.Fprintf(w, "  %-9s #%d at %s\n", marker.Action, marker.Index, formatMs(marker.Start))
+		}
+	}
+
+	var overlaps, gaps []string
+	for _, lane := range tl.Lanes {
+		for _, bar := range lane.Bars {
+			if bar.Overlaps != nil {
+				overlaps = append(overlaps, fmt.Sprintf("  %-7s %s #%d at %s cuts short %s #%d (%s-%s)",
+					lane.Name, bar.Action.Action, bar.Action.Index, formatMs(bar.Action.Start),
+					bar.Overlaps.Action, bar.Overlaps.Index, formatMs(bar.Overlaps.Start), formatMs(actionEnd(bar.Overlaps))))
+			}
+		}
+		for _, gap := range lane.Gaps {
+			gaps = append(gaps, fmt.Sprintf("  %-7s %s-%s (%d ms)", lane.Name, formatMs(gap.Start), formatMs(gap.End), gap.End-gap.Start))
+		}
+	}
+	if len(overlaps) > 0 {
+		fmt.Fprintln(w, "Overlaps:")
+		fmt.Fprintln(w, strings.Join(overlaps, "\n"))
+	}
+	if len(gaps) > 0 {
+		fmt.Fprintln(w, "Gaps:")
+		fmt.Fprintln(w, strings.Join(gaps, "\n"))
+	}
+}
+
+// SVG layout of a timeline in pixels
+const (
+	svgLabelWidth = 70
+	svgRowHeight  = 22
+	svgAxisHeight = 34
+	svgMargin     = 10
+)
+
+// svgLaneColors are the bar colors of the lanes; SE channels share one
+var svgLaneColors = map[string]string{
+	laneBG:     "#4a7fd4",
+	laneMovie:  "#7a4ad4",
+	laneFade:   "#555555",
+	laneBGM:    "#2f9e6e",
+	laneVoice:  "#d48a2f",
+	laneText:   "#b8a92c",
+	laneSelect: "#d44a8a",
+}
+
+// renderSVG writes a timeline as an SVG image width pixels wide
+func renderSVG(w io.Writer, tl *timeline, width int) error {
+	var out bytes.Buffer
+	span := max(tl.To-tl.From, 1)
+	plot := float64(width - svgLabelWidth - 2*svgMargin)
+	x := func(ms int64) float64 {
+		return float64(svgLabelWidth+svgMargin) + float64(ms-tl.From)*plot/float64(span)
+	}
+	top := svgAxisHeight
+	height := top + len(tl.Lanes)*svgRowHeight + svgMargin
+
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`+"\n", width, height)
+	fmt.Fprintf(&out, "<title>%s</title>\n", xmlText(tl.Name))
+	fmt.Fprintf(&out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	// Time axis
+	step := tickStep(span, 10)
+	for ms := (tl.From + step - 1) / step * step; ms <= tl.To; ms += step {
+		fmt.Fprintf(&out, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#dddddd"/>`+"\n", x(ms), top-6, x(ms), height-svgMargin)
+		fmt.Fprintf(&out, `<text x="%.1f" y="%d" text-anchor="middle" fill="#444444">%s</text>`+"\n", x(ms), top-10, formatMs(ms))
+	}
+
+	for i, lane := range tl.Lanes {
+		y := top + i*svgRowHeight
+		if i%2 == 0 {
+			fmt.Fprintf(&out, `<rect x="0" y="%d" width="%d" height="%d" fill="#f4f4f4"/>`+"\n", y, width, svgRowHeight)
+		}
+		fmt.Fprintf(&out, `<text x="%d" y="%d" fill="#222222">%s</text>`+"\n", svgMargin, y+15, xmlText(lane.Name))
+
+		for _, gap := range lane.Gaps {
+			fmt.Fprintf(&out, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="#ffe9a8"><title>gap %s-%s (%d ms)</title></rect>`+"\n",
+				x(gap.Start), y+2, x(gap.End)-x(gap.Start), svgRowHeight-4, formatMs(gap.Start), formatMs(gap.End), gap.End-gap.Start)
+		}
+
+		color, ok := svgLaneColors[lane.Name]
+		if !ok {
+			color = "#48a0a8"
+		}
+		for _, bar := range lane.Bars {
+			title := fmt.Sprintf("%s\n%s-%s", actionLabel(bar.Action), formatMs(bar.Action.Start), formatMs(actionEnd(bar.Action)))
+			barWidth := max(x(bar.End)-x(bar.Start), 1.5)
+			fmt.Fprintf(&out, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" fill-opacity="0.8" stroke="#ffffff" stroke-width="0.5"><title>%s</title></rect>`+"\n",
+				x(bar.Start), y+4, barWidth, svgRowHeight-8, color, xmlText(title))
+			if bar.Overlaps != nil {
+				overlap := bar.overlapSpan()
+				fmt.Fprintf(&out, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="#e0393e"><title>%s cuts short %s</title></rect>`+"\n",
+					x(overlap.Start), y+4, max(x(overlap.End)-x(overlap.Start), 1.5), svgRowHeight-8,
+					xmlText(actionLabel(bar.Action)), xmlText(actionLabel(bar.Overlaps)))
+			}
+		}
+	}
+
+	for _, marker := range tl.Markers {
+		fmt.Fprintf(&out, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#b03cb0" stroke-dasharray="4 3"><title>%s at %s</title></line>`+"\n",
+			x(marker.Start), top-24, x(marker.Start), height-svgMargin, marker.Action, formatMs(marker.Start))
+		fmt.Fprintf(&out, `<text x="%.1f" y="%d" fill="#b03cb0">%s</text>`+"\n", x(marker.Start)+3, top-24, marker.Action)
+	}
+
+	out.WriteString("</svg>\n")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// renderHTML writes a timeline as a standalone page with the SVG and notes
+func renderHTML(w io.Writer, tl *timeline, width int) error {
+	var svg, notes bytes.Buffer
+	if err := renderSVG(&svg, tl, width); err != nil {
+		return err
+	}
+	writeTimelineNotes(&notes, tl)
+
+	_, err := fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%[1]s</title>
+<style>body { font-family: sans-serif; margin: 16px; } pre { font-size: 12px; }</style>
+</head>
+<body>
+<h1>%[1]s</h1>
+<p>%[2]s - %[3]s. Hover a bar for its action; red marks overlaps, yellow marks gaps.</p>
+%[4]s<pre>%[5]s</pre>
+</body>
+</html>
+`, xmlText(tl.Name), formatMs(tl.From), formatMs(tl.To), svg.String(), xmlText(notes.String()))
+	return err
+}
+
+// xmlText escapes text for SVG and HTML
+func xmlText(text string) string {
+	var out bytes.Buffer
+	for _, r := range text {
+		switch r {
+		case '&':
+			out.WriteString("&amp;")
+		case '<':
+			out.WriteString("&lt;")
+		case '>':
+			out.WriteString("&gt;")
+		case '"':
+			out.WriteString("&quot;")
+		default:
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// runTimeline renders a JRS file as per-lane bars over time, parsed the same
+// way the engine parses it
+func runTimeline(args []string) error {
+	flags := flag.NewFlagSet("timeline", flag.ExitOnError)
+	root := flags.String("root", "", "game directory to load the script from through the archives (default a file on disk)")
+	format := flags.String("format", "text", "output format: text, ansi, svg or html")
+	out := flags.String("o", "", "file to write (default standard output)")
+	width := flags.Int("width", 0, "columns for text, pixels for svg and html (default 120 and 1400)")
+	from := flags.Int64("from", 0, "start of the shown window in ms")
+	to := flags.Int64("to", 0, "end of the shown window in ms (default the end of the scene)")
+	minGap := flags.Int64("min-gap", 250, "shortest gap in ms to highlight")
+	tolerance := flags.Int64("tolerance", 0, "overlap in ms ignored as timing noise")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: jrstool timeline [flags] <script.JRS>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return usageError("timeline: expected one script")
+	}
+	if *to > 0 && *to <= *from {
+		flags.Usage()
+		return usageError("timeline: -to must be after -from")
+	}
+	switch *format {
+	case "text", "ansi", "svg", "html":
+	default:
+		flags.Usage()
+		return usageError(fmt.Sprintf("timeline: unknown format %q", *format))
+	}
+
+	parsed, err := loadTimelineScript(*root, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *to <= 0 {
+		*to = sceneEnd(parsed)
+		if *to <= *from {
+			flags.Usage()
+			return usageError(fmt.Sprintf("timeline: -from %d ms is not before the end of the scene at %d ms", *from, *to))
+		}
+	}
+	tl := buildTimeline(parsed, *from, *to, *minGap, *tolerance)
+
+	var buf bytes.Buffer
+	switch *format {
+	case "text", "ansi":
+		if *width <= 0 {
+			*width = 120
+		}
+		err = renderText(&buf, tl, *width, *format == "ansi")
+	case "svg":
+		if *width <= 0 {
+			*width = 1400
+		}
+		err = renderSVG(&buf, tl, *width)
+	case "html":
+		if *width <= 0 {
+			*width = 1400
+		}
+		err = renderHTML(&buf, tl, *width)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(*out, buf.Bytes(), 0644)
+}
+
+// loadTimelineScript parses a script from disk, or through the filesystem
+// of a game directory like the engine does
+func loadTimelineScript(root, name string) (*script.Script, error) {
+	if root == "" {
+		parsed, _, err := loadScript(filepath.Dir(name), filepath.Base(name))
+		if err != nil {
+			return nil, err
+		}
+		parsed.Name = name
+		return parsed, nil
+	}
+
+	fs := filesystem.NewManager(root)
+	if err := fs.Init(); err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", root, err)
+	}
+	defer fs.Close()
+	return script.LoadJRS(fs, name)
+}
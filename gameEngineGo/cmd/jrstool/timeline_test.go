@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/script"
+)
+
+// timelineFixture is a script of the fixture game, ending at 2500 ms
+const timelineFixture = "testdata/game/Script/RUSSIAN/00/00-00-A00.jrs"
+
+func TestRunTimelineRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		usage bool
+	}{
+		{"whole scene", nil, false},
+		{"window", []string{"-from", "500", "-to", "1000"}, false},
+		{"from to the end", []string{"-from", "2000"}, false},
+		{"to before from", []string{"-from", "1000", "-to", "500"}, true},
+		{"empty window", []string{"-from", "1000", "-to", "1000"}, true},
+		{"from at the scene end", []string{"-from", "2500"}, true},
+		{"from after the scene end", []string{"-from", "3000"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "timeline.txt")
+			args := append(append([]string{"-o", out}, test.args...), timelineFixture)
+
+			err := runTimeline(args)
+			var invalid usageError
+			if test.usage {
+				if !errors.As(err, &invalid) {
+					t.Errorf("got %v, want a usage error", err)
+				}
+				if _, statErr := os.Stat(out); statErr == nil {
+					t.Error("timeline written for an invalid range")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data, err := os.ReadFile(out); err != nil || len(data) == 0 {
+				t.Errorf("timeline not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunTimelineArguments(t *testing.T) {
+	var invalid usageError
+	if err := runTimeline(nil); !errors.As(err, &invalid) {
+		t.Errorf("no script: got %v, want a usage error", err)
+	}
+	if err := runTimeline([]string{"-format", "pdf", timelineFixture}); !errors.As(err, &invalid) {
+		t.Errorf("unknown format: got %v, want a usage error", err)
+	}
+	if err := runTimeline([]string{"missing.jrs"}); err == nil || errors.As(err, &invalid) {
+		t.Errorf("missing script: got %v, want a load error", err)
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	parsed, err := script.ParseJRS(strings.NewReader(`[
+{"action":"CreateBG","start":0,"end":1000,"file":"Event00/TEST/BG-001"},
+{"action":"CreateBG","start":900,"end":2000,"file":"Event00/TEST/BG-002"},
+{"action":"PrintText","start":0,"end":500,"text":"one"},
+{"action":"PrintText","start":1500,"end":2500,"text":"two"},
+{"action":"SkipFRAME","start":1200},
+{"action":"Next","start":3000}
+]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end := sceneEnd(parsed); end != 3000 {
+		t.Errorf("scene ends at %d ms, want 3000", end)
+	}
+
+	tl := buildTimeline(parsed, 400, 2200, 250, 0)
+
+	var lanes []string
+	for _, lane := range tl.Lanes {
+		lanes = append(lanes, lane.Name)
+	}
+	if strings.Join(lanes, ",") != "bg,text" {
+		t.Fatalf("lanes %v, want bg,text", lanes)
+	}
+
+	bg, text := tl.Lanes[0], tl.Lanes[1]
+	if len(bg.Bars) != 2 || bg.Bars[0].Start != 400 || bg.Bars[1].Overlaps != bg.Bars[0].Action {
+		t.Errorf("bg bars not clamped or overlap missed: %+v", bg.Bars)
+	}
+	if len(text.Gaps) != 1 || text.Gaps[0] != (timelineSpan{500, 1500}) {
+		t.Errorf("text gaps %+v, want 500-1500", text.Gaps)
+	}
+	if len(text.Bars) != 2 || text.Bars[1].End != 2200 {
+		t.Errorf("text bars not clamped to the window: %+v", text.Bars)
+	}
+	if len(tl.Markers) != 1 || tl.Markers[0].Action != script.ActionSkipFrame {
+		t.Errorf("markers %+v, want the SkipFRAME only", tl.Markers)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"school-days-engine/internal/script"
+)
+
+// sourceString is a translatable string found in a JRS tree
+type sourceString struct {
+	Key     string // Stable key: "<file>#<action index>.<field>"
+	File    string // Path relative to the tree, with forward slashes
+	Text    script.TextString
+	Comment string
+}
+
+// stringKey builds the stable key of a string
+func stringKey(file string, index int, field string) string {
+	return fmt.Sprintf("%s#%d.%s", file, index, field)
+}
+
+// findScripts returns the JRS files under a directory, relative with forward slashes
+func findScripts(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".jrs") {
+			return nil
+		}
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relative))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scripts in %s: %w", root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// loadScript parses a JRS file of a tree
+func loadScript(root, file string) (*script.Script, []byte, error) {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, err := script.ParseJRS(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+	parsed.Name = file
+	return parsed, data, nil
+}
+
+// collectStrings returns every translatable string of a JRS tree
+func collectStrings(root string) ([]*sourceString, error) {
+	files, err := findScripts(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []*sourceString
+	for _, file := range files {
+		parsed, _, err := loadScript(root, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, text := range parsed.Strings() {
+			if text.Value == "" {
+				continue
+			}
+			str := &sourceString{Key: stringKey(file, text.Index, text.Field), File: file, Text: text}
+			if text.Persona != "" {
+				str.Comment = "persona: " + text.Persona
+			}
+			texts = append(texts, str)
+		}
+	}
+	return texts, nil
+}
+
+// readPOFile reads a PO file into a map keyed by msgctxt
+func readPOFile(path string) (map[string]*poEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries, err := readPO(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	byKey := make(map[string]*poEntry, len(entries))
+	for _, entry := range entries {
+		byKey[entry.Context] = entry
+	}
+	return byKey, nil
+}
+
+// runExport writes the strings of a JRS tree as a PO file. With -merge the
+// translations of an older PO file are kept; those whose source text
+// changed are marked fuzzy.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	src := flags.String("src", "", "JRS tree to translate from (e.g. Script/RUSSIAN)")
+	out := flags.String("o", "", "PO file to write (default standard output)")
+	merge := flags.String("merge", "", "earlier PO file whose translations are kept")
+	flags.Parse(args)
+	if *src == "" {
+		flags.Usage()
+		return usageError("export: -src is required")
+	}
+
+	texts, err := collectStrings(*src)
+	if err != nil {
+		return err
+	}
+
+	var previous map[string]*poEntry
+	if *merge != "" {
+		if previous, err = readPOFile(*merge); err != nil {
+			return err
+		}
+	}
+
+	entries := make([]*poEntry, len(texts))
+	fuzzy := 0
+	for i, str := range texts {
+		entry := &poEntry{
+			Reference: fmt.Sprintf("%s:%d", str.File, str.Text.Start),
+			Context:   str.Key,
+			ID:        str.Text.Value,
+		}
+		if str.Comment != "" {
+			entry.Comments = []string{str.Comment}
+		}
+		if old, ok := previous[str.Key]; ok && old.Str != "" {
+			entry.Str = old.Str
+			entry.Fuzzy = old.Fuzzy || old.ID != str.Text.Value
+			if entry.Fuzzy {
+				fuzzy++
+			}
+		}
+		entries[i] = entry
+	}
+
+	writer := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	if err := writePO(writer, entries); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d strings", len(entries))
+	if *merge != "" {
+		fmt.Fprintf(os.Stderr, " (%d fuzzy after source changes)", fuzzy)
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+// runImport patches the translations of a PO file into copies of the JRS
+// files, changing only the string values. Fuzzy entries and entries whose
+// source text changed since the export are skipped.
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	src := flags.String("src", "", "JRS tree the PO file was exported from")
+	po := flags.String("po", "", "translated PO file")
+	out := flags.String("o", "", "directory for the translated JRS tree (e.g. Script/ENGLISH)")
+	flags.Parse(args)
+	if *src == "" || *po == "" || *out == "" {
+		flags.Usage()
+		return usageError("import: -src, -po and -o are required")
+	}
+
+	entries, err := readPOFile(*po)
+	if err != nil {
+		return err
+	}
+	files, err := findScripts(*src)
+	if err != nil {
+		return err
+	}
+
+	written, patchedTotal, skipped := 0, 0, 0
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(*src, filepath.FromSlash(file)))
+		if err != nil {
+			return err
+		}
+
+		patched, count, err := script.PatchStrings(data, func(index int, field, value string) (string, bool) {
+			entry, ok := entries[stringKey(file, index, field)]
+			if !ok || entry.Str == "" {
+				return "", false
+			}
+			if entry.Fuzzy || entry.ID != value {
+				fmt.Fprintf(os.Stderr, "Skipping %s: source text changed\n", entry.Context)
+				skipped++
+				return "", false
+			}
+			return entry.Str, true
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if count == 0 {
+			continue
+		}
+
+		target := filepath.Join(*out, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, patched, 0644); err != nil {
+			return err
+		}
+		written++
+		patchedTotal += count
+	}
+
+	fmt.Fprintf(os.Stderr, "Imported %d strings into %d files (%d skipped)\n", patchedTotal, written, skipped)
+	return nil
+}
+
+// runStatus compares a PO file with the current JRS tree and reports
+// untranslated, fuzzy, changed, new and obsolete strings
+func runStatus(args []string) error {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	src := flags.String("src", "", "current JRS tree")
+	po := flags.String("po", "", "PO file to check")
+	verbose := flags.Bool("v", false, "list every string that needs work")
+	flags.Parse(args)
+	if *src == "" || *po == "" {
+		flags.Usage()
+		return usageError("status: -src and -po are required")
+	}
+
+	entries, err := readPOFile(*po)
+	if err != nil {
+		return err
+	}
+	texts, err := collectStrings(*src)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	report := func(kind, key string) {
+		counts[kind]++
+		if *verbose {
+			fmt.Printf("%s\t%s\n", kind, key)
+		}
+	}
+
+	current := make(map[string]bool, len(texts))
+	for _, str := range texts {
+		current[str.Key] = true
+		entry, ok := entries[str.Key]
+		switch {
+		case !ok:
+			report("new", str.Key)
+		case entry.ID != str.Text.Value:
+			report("changed", str.Key)
+		case entry.Str == "":
+			report("untranslated", str.Key)
+		case entry.Fuzzy:
+			report("fuzzy", str.Key)
+		default:
+			counts["translated"]++
+		}
+	}
+
+	var obsolete []string
+	for key := range entries {
+		if !current[key] {
+			obsolete = append(obsolete, key)
+		}
+	}
+	sort.Strings(obsolete)
+	for _, key := range obsolete {
+		report("obsolete", key)
+	}
+
+	fmt.Printf("%d strings: %d translated, %d untranslated, %d fuzzy, %d changed, %d new, %d obsolete\n",
+		len(texts), counts["translated"], counts["untranslated"], counts["fuzzy"], counts["changed"],
+		counts["new"], counts["obsolete"])
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/script"
+)
+
+const translateTestScript = `[
+{"action":"PrintText","start":100,"end":900,"persona":"MAK","text":"Он сказал: \"Привет\"\nи ушёл"},
+{"action":"SetSELECT","start":1000,"answer1":"Да","answer2":"Нет"},
+{"action":"PrintText","start":2000,"end":2500,"persona":"SEK","text":"Пока"}
+]`
+
+// writeTree writes JRS files into a new tree
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestPORoundTrip(t *testing.T) {
+	entries := []*poEntry{
+		{
+			Comments:  []string{"persona: MAK"},
+			Reference: "00/00-00-A00.jrs:100",
+			Fuzzy:     true,
+			Context:   "00/00-00-A00.jrs#0.text",
+			ID:        "Он сказал: \"Привет\"\nи ушёл",
+			Str:       "He said: \"Hi\"\r\n\tand left \\o/",
+		},
+		{Context: "00/00-00-A00.jrs#1.answer1", ID: "Да"},
+	}
+
+	var buf bytes.Buffer
+	if err := writePO(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	read, err := readPO(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != len(entries) {
+		t.Fatalf("read %d entries, want %d", len(read), len(entries))
+	}
+	for i, want := range entries {
+		got := read[i]
+		if strings.Join(got.Comments, "|") != strings.Join(want.Comments, "|") || got.Reference != want.Reference ||
+			got.Fuzzy != want.Fuzzy || got.Context != want.Context || got.ID != want.ID || got.Str != want.Str {
+			t.Errorf("entry %d read as %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestReadPOContinuationLines(t *testing.T) {
+	po := `msgid ""
+msgstr "Content-Type: text/plain; charset=UTF-8\n"
+
+#. persona: MAK
+msgctxt "a.jrs#0.text"
+msgid ""
+"first line\n"
+"second \"line\""
+msgstr "translated"
+msgctxt "a.jrs#1.text"
+msgid "next"
+msgstr ""
+`
+	entries, err := readPO(strings.NewReader(po))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("read %d entries, want 2", len(entries))
+	}
+	if entries[0].ID != "first line\nsecond \"line\"" || entries[0].Str != "translated" {
+		t.Errorf("continued entry read as %+v", entries[0])
+	}
+	if entries[1].Context != "a.jrs#1.text" || entries[1].ID != "next" {
+		t.Errorf("entry without a blank line read as %+v", entries[1])
+	}
+
+	if _, err := readPO(strings.NewReader("msgid \"x\"\nmsgfoo \"y\"\n")); err == nil {
+		t.Error("unknown keyword accepted")
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := writeTree(t, map[string]string{"00/00-00-A00.jrs": translateTestScript})
+	po := filepath.Join(t.TempDir(), "strings.po")
+	if err := runExport([]string{"-src", src, "-o", po}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readPOFile(po)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIDs := map[string]string{
+		"00/00-00-A00.jrs#0.text":    "Он сказал: \"Привет\"\nи ушёл",
+		"00/00-00-A00.jrs#1.answer1": "Да",
+		"00/00-00-A00.jrs#1.answer2": "Нет",
+		"00/00-00-A00.jrs#2.text":    "Пока",
+	}
+	if len(entries) != len(wantIDs) {
+		t.Errorf("exported %d strings, want %d", len(entries), len(wantIDs))
+	}
+	for key, id := range wantIDs {
+		if entry, ok := entries[key]; !ok || entry.ID != id {
+			t.Errorf("%s exported as %+v, want msgid %q", key, entry, id)
+		}
+	}
+	// Personas are context for the translator, not strings to translate
+	if entry := entries["00/00-00-A00.jrs#0.text"]; entry == nil || strings.Join(entry.Comments, "|") != "persona: MAK" {
+		t.Errorf("persona comment = %+v", entry)
+	}
+
+	// Translate everything but the last line, which changes in the source
+	translations := map[string]string{
+		"00/00-00-A00.jrs#0.text":    "He said: \"Hi\"\nand left",
+		"00/00-00-A00.jrs#1.answer1": "Yes",
+		"00/00-00-A00.jrs#1.answer2": "No",
+		"00/00-00-A00.jrs#2.text":    "Bye",
+	}
+	var translated []*poEntry
+	for key, entry := range entries {
+		entry.Str = translations[key]
+		if key == "00/00-00-A00.jrs#2.text" {
+			entry.ID = "Пока-пока"
+		}
+		translated = append(translated, entry)
+	}
+	file, err := os.Create(po)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writePO(file, translated); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	out := t.TempDir()
+	if err := runImport([]string{"-src", src, "-po", po, "-o", out}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "00", "00-00-A00.jrs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		`"Он сказал: \"Привет\"\nи ушёл"`, `"He said: \"Hi\"\nand left"`,
+		`"Да"`, `"Yes"`,
+		`"Нет"`, `"No"`,
+	).Replace(translateTestScript)
+	if string(data) != want {
+		t.Errorf("imported script\n%s\nwant\n%s", data, want)
+	}
+
+	parsed, err := script.ParseJRS(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Actions[0].Persona != "MAK" || parsed.Actions[0].Text != "He said: \"Hi\"\nand left" {
+		t.Errorf("imported line = %+v", parsed.Actions[0])
+	}
+}
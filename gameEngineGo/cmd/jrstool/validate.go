@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/locale"
+	"school-days-engine/internal/script"
+	"school-days-engine/internal/settings"
+)
+
+// Validation checks, each of which can be turned off with -skip
+const (
+	checkParse   = "parse"   // The script is not valid JRS
+	checkAction  = "action"  // Unknown action name
+	checkTime    = "time"    // Negative start or end before start
+	checkAsset   = "asset"   // Referenced file missing from the archives and loose files
+	checkOverlap = "overlap" // Two actions running on the same layer or channel
+	checkVoice   = "voice"   // PlayVoice without a PrintText line
+	checkRoute   = "route"   // Route table naming a script that does not exist
+)
+
+// allChecks lists the checks in the order they are described
+var allChecks = []string{checkParse, checkAction, checkTime, checkAsset, checkOverlap, checkVoice, checkRoute}
+
+// Issue severities; only errors make validate fail
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// overlapLanes are the lanes that show one thing at a time, so an action
+// starting before the previous one ended cuts it short
+var overlapLanes = map[string]bool{
+	laneBG:     true,
+	laneMovie:  true,
+	laneBGM:    true,
+	laneText:   true,
+	laneSelect: true,
+}
+
+// routeTables are the INI files of the original game that name scripts
+// (matches C++ StartScript.ini, STANDERDSCRIPT.INI and FEELINGSCRIPT.INI)
+var routeTables = []string{"Ini/StartScript.ini", "Ini/STANDERDSCRIPT.INI", "Ini/FEELINGSCRIPT.INI"}
+
+// issue is one problem found in a script tree
+type issue struct {
+	File     string `json:"file"`
+	Index    int    `json:"index"` // Action position in the file, -1 for the whole file
+	Start    int64  `json:"start"` // Action start in ms
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// validator checks the scripts of a game directory
+type validator struct {
+	fs        *filesystem.Manager
+	skip      map[string]bool
+	tolerance int64           // Overlap in ms ignored as timing noise
+	assets    map[string]bool // Resolved asset names
+	issues    []*issue
+}
+
+// newValidator creates a validator for a mounted game directory
+func newValidator(fs *filesystem.Manager, skip map[string]bool, tolerance int64) *validator {
+	return &validator{
+		fs:        fs,
+		skip:      skip,
+		tolerance: tolerance,
+		assets:    make(map[string]bool),
+	}
+}
+
+// report records an issue unless its check is skipped
+func (v *validator) report(file string, action *script.Action, check, severity, format string, args ...interface{}) {
+	if v.skip[check] {
+		return
+	}
+	found := &issue{File: file, Index: -1, Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if action != nil {
+		found.Index, found.Start = action.Index, action.Start
+	}
+	v.issues = append(v.issues, found)
+}
+
+// exists reports whether a file resolves through the archives or loose files
+func (v *validator) exists(name string) bool {
+	if found, ok := v.assets[name]; ok {
+		return found
+	}
+	found := v.fs.Exists(name)
+	v.assets[name] = found
+	return found
+}
+
+// treeScripts returns the JRS files of a script tree in the archives and
+// the loose files, sorted
+func (v *validator) treeScripts(tree string) []string {
+	found := make(map[string]string) // Lower-case name -> name as found
+	add := func(name string) {
+		name = strings.ReplaceAll(name, "\\", "/")
+		if strings.EqualFold(path.Ext(name), ".jrs") {
+			found[strings.ToLower(name)] = name
+		}
+	}
+
+	prefix := strings.ToLower(tree) + "/"
+	for _, file := range v.fs.ListFiles() {
+		if strings.HasPrefix(strings.ToLower(strings.ReplaceAll(file.Name, "\\", "/")), prefix) {
+			add(file.Name)
+		}
+	}
+	if files, err := findScripts(v.fs.GetLoosePath(tree)); err == nil {
+		for _, file := range files {
+			add(path.Join(tree, file))
+		}
+	}
+
+	scripts := make([]string, 0, len(found))
+	for _, name := range found {
+		scripts = append(scripts, name)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// validateTree checks every script of a tree and the route tables against it
+func (v *validator) validateTree(tree string) int {
+	scripts := v.treeScripts(tree)
+	names := make(map[string]bool, len(scripts))
+	for _, file := range scripts {
+		names[strings.ToLower(strings.TrimSuffix(file, path.Ext(file)))] = true
+
+		parsed, err := script.LoadJRS(v.fs, file)
+		if err != nil {
+			v.report(file, nil, checkParse, severityError, "%v", err)
+			continue
+		}
+		v.validateScript(file, parsed)
+	}
+
+	v.validateRoutes(tree, names)
+	return len(scripts)
+}
+
+// validateScript checks the actions of one script
+func (v *validator) validateScript(file string, parsed *script.Script) {
+	overlaps := findOverlaps(parsed.Actions, v.tolerance)
+	var texts []*script.Action
+
+	for _, action := range parsed.Actions {
+		if !script.IsKnownAction(action.Action) {
+			v.report(file, action, checkAction, severityError, "unknown action %q", action.Action)
+		}
+
+		if action.Start < 0 {
+			v.report(file, action, checkTime, severityError, "%s starts at %d ms", action.Action, action.Start)
+		}
+		if action.HasEnd() && action.End < action.Start {
+			v.report(file, action, checkTime, severityError, "%s ends at %d ms before its start at %d ms", action.Action, action.End, action.Start)
+		}
+
+		v.validateAsset(file, action)
+
+		if previous := overlaps[action]; previous != nil && overlapLanes[actionLane(action)] {
+			v.report(file, action, checkOverlap, severityWarning, "%s overlaps %s #%d on the %s lane (%d-%d ms)",
+				action.Action, previous.Action, previous.Index, actionLane(action), previous.Start, actionEnd(previous))
+		}
+
+		if action.Action == script.ActionPrintText {
+			texts = append(texts, action)
+		}
+	}
+
+	for _, action := range parsed.Actions {
+		if action.Action == script.ActionPlayVoice && !hasText(texts, action) {
+			v.report(file, action, checkVoice, severityWarning, "PlayVoice %s has no PrintText at %d ms", action.File, action.Start)
+		}
+	}
+}
+
+// validateAsset checks that the file an action plays resolves
+func (v *validator) validateAsset(file string, action *script.Action) {
+	switch action.Action {
+	case script.ActionCreateBG, script.ActionPlayMovie, script.ActionPlaySe, script.ActionPlayES,
+		script.ActionPlayBgm, script.ActionPlayVoice, script.ActionEndBGM, script.ActionEndRoll:
+		if action.File == "" {
+			v.report(file, action, checkAsset, severityError, "%s has no file", action.Action)
+			return
+		}
+	}
+
+	if name := assetName(action); name != "" && !v.exists(name) {
+		v.report(file, action, checkAsset, severityError, "%s file %s not found", action.Action, name)
+	}
+}
+
+// hasText reports whether a PrintText line is shown when a voice starts
+func hasText(texts []*script.Action, voice *script.Action) bool {
+	for _, text := range texts {
+		if text.Start <= voice.Start && voice.Start <= actionEnd(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRoutes checks that the scripts named by the route tables exist in
+// a tree. Tables missing from the game directory are skipped.
+func (v *validator) validateRoutes(tree string, names map[string]bool) {
+	for _, table := range routeTables {
+		if !v.exists(table) {
+			continue
+		}
+		data, err := v.fs.ReadFile(table)
+		if err != nil {
+			v.report(table, nil, checkRoute, severityError, "failed to read route table: %v", err)
+			continue
+		}
+
+		doc := settings.ParseINIDocument(data)
+		for _, key := range doc.Keys() {
+			scene := routeScene(doc, key)
+			if scene != "" && !names[strings.ToLower(path.Join(tree, scene))] {
+				v.report(table, nil, checkRoute, severityError, "[%s] names script %s missing from %s", key, scene, tree)
+			}
+		}
+	}
+}
+
+// routeScene returns the script a route table entry names, relative to a
+// script tree and without extension ("00/00-00-A00"), or "" for other entries
+func routeScene(doc *settings.INIDocument, key string) string {
+	if key == "StartScript" {
+		value, _ := doc.GetString(key)
+		return value
+	}
+	if key == "Number" || strings.HasPrefix(key, "flag") || len(key) < 3 || key[2] != '-' {
+		return ""
+	}
+	// Scene tables are keyed by script name; the prefix is the directory
+	return key[:2] + "/" + key
+}
+
+// runValidate checks the scripts of a game directory: actions, timings,
+// referenced assets, overlaps, voices without text and route targets
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	root := flags.String("root", ".", "game directory with the GPK archives and loose files")
+	language := flags.String("lang", "", "script language to check, e.g. ru (default all)")
+	format := flags.String("format", "text", "output format: text or json (one issue per line)")
+	tolerance := flags.Int64("tolerance", 100, "overlap in ms ignored as timing noise")
+	skipList := flags.String("skip", "", "comma-separated checks to skip: "+strings.Join(allChecks, ", "))
+	flags.Parse(args)
+	if *format != "text" && *format != "json" {
+		flags.Usage()
+		return usageError(fmt.Sprintf("validate: unknown format %q", *format))
+	}
+
+	skip := make(map[string]bool)
+	for _, check := range strings.Split(*skipList, ",") {
+		if check = strings.TrimSpace(check); check != "" {
+			skip[check] = true
+		}
+	}
+
+	fs := filesystem.NewManager(*root)
+	if err := fs.Init(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", *root, err)
+	}
+	defer fs.Close()
+
+	languages := locale.Discover(fs)
+	if *language != "" {
+		languages = []string{locale.DirectoryName(*language)}
+	}
+	if len(languages) == 0 {
+		return fmt.Errorf("validate: no script trees in %s", *root)
+	}
+
+	v := newValidator(fs, skip, *tolerance)
+	count := 0
+	for _, dir := range languages {
+		count += v.validateTree(path.Join(locale.ScriptRoot, dir))
+	}
+
+	if err := writeIssues(os.Stdout, v.issues, *format); err != nil {
+		return err
+	}
+
+	errors, warnings := 0, 0
+	for _, found := range v.issues {
+		if found.Severity == severityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d scripts checked: %d errors, %d warnings\n", count, errors, warnings)
+	if errors > 0 {
+		return fmt.Errorf("validation failed")
+	}
+	return nil
+}
+
+// writeIssues writes issues as text lines or JSON Lines
+func writeIssues(w io.Writer, issues []*issue, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		for _, found := range issues {
+			if err := encoder.Encode(found); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, found := range issues {
+		where := found.File
+		if found.Index >= 0 {
+			where = fmt.Sprintf("%s#%d (%d ms)", found.File, found.Index, found.Start)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s: %s\n", where, found.Severity, found.Check, found.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
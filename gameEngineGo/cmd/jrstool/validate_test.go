@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/filesystem"
+)
+
+// validateFixture validates the scripts of testdata/game and returns the
+// issues as "<file>#<index> <severity> <check>"
+func validateFixture(t *testing.T, skip map[string]bool) []string {
+	t.Helper()
+	fs := filesystem.NewManager("testdata/game")
+	if err := fs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	v := newValidator(fs, skip, 100)
+	if count := v.validateTree("Script/RUSSIAN"); count != 3 {
+		t.Errorf("checked %d scripts, want 3", count)
+	}
+
+	issues := make([]string, len(v.issues))
+	for i, found := range v.issues {
+		issues[i] = fmt.Sprintf("%s#%d %s %s", found.File, found.Index, found.Severity, found.Check)
+	}
+	return issues
+}
+
+func TestValidateFixture(t *testing.T) {
+	// Issues follow the actions in start order
+	want := []string{
+		"Script/RUSSIAN/00/00-00-B00.jrs#1 error time",
+		"Script/RUSSIAN/00/00-00-B00.jrs#0 error action",
+		"Script/RUSSIAN/00/00-00-B00.jrs#2 error time",
+		"Script/RUSSIAN/00/00-00-B00.jrs#3 error asset",
+		"Script/RUSSIAN/00/00-00-B00.jrs#4 warning overlap",
+		"Script/RUSSIAN/00/00-00-B00.jrs#6 error asset",
+		"Script/RUSSIAN/00/00-00-B00.jrs#5 warning voice",
+		"Script/RUSSIAN/00/00-00-C00.jrs#-1 error parse",
+		"Ini/STANDERDSCRIPT.INI#-1 error route",
+	}
+
+	got := validateFixture(t, nil)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateSkip(t *testing.T) {
+	skip := map[string]bool{checkAction: true, checkTime: true, checkAsset: true, checkOverlap: true, checkVoice: true}
+	want := []string{
+		"Script/RUSSIAN/00/00-00-C00.jrs#-1 error parse",
+		"Ini/STANDERDSCRIPT.INI#-1 error route",
+	}
+
+	got := validateFixture(t, skip)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateExists(t *testing.T) {
+	fs := filesystem.NewManager("testdata/game")
+	if err := fs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	v := newValidator(fs, nil, 100)
+	if !v.exists("Event00/TEST/BG-001.PNG") {
+		t.Error("loose asset not found")
+	}
+	if v.exists("Event00/TEST/BG-404.PNG") {
+		t.Error("missing asset found")
+	}
+}
@@ -2,91 +2,191 @@ package audio
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"path/filepath"
-	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+// Default BGM transition times
+const (
+	DefaultBGMCrossfade = 1500 * time.Millisecond
+	DefaultBGMFadeOut   = 3000 * time.Millisecond
 )
 
-// LoadBGM loads background music from a file
+// bgmTrack is a playing BGM with its own fade envelope
+type bgmTrack struct {
+	name      string
+	path      string
+	player    *audio.Player
+	stream    *audioStream
+	fade      fadeEnvelope
+	loopsLeft int // Extra plays for finite loop counts
+}
+
+// fadeEnvelope moves a gain level linearly from one value to another
+type fadeEnvelope struct {
+	from     float64
+	to       float64
+	duration time.Duration
+	elapsed  time.Duration
+}
+
+// newFade creates an envelope from one level to another
+func newFade(from, to float64, duration time.Duration) fadeEnvelope {
+	return fadeEnvelope{from: from, to: to, duration: duration}
+}
+
+// level returns the current gain of the envelope
+func (f *fadeEnvelope) level() float64 {
+	if f.duration <= 0 || f.elapsed >= f.duration {
+		return f.to
+	}
+	progress := float64(f.elapsed) / float64(f.duration)
+	return f.from + (f.to-f.from)*progress
+}
+
+// advance moves the envelope forward in time
+func (f *fadeEnvelope) advance(delta time.Duration) {
+	f.elapsed += delta
+}
+
+// done returns true when the envelope reached its target level
+func (f *fadeEnvelope) done() bool {
+	return f.elapsed >= f.duration
+}
+
+// LoadBGM prepares background music from a file. Loose files and GPK
+// entries are streamed when played, so only preloaded data is kept in
+// memory. The current track
+// keeps playing until PlayBGM replaces it.
 func (m *Manager) LoadBGM(filename string) error {
 	log.Printf("Loading BGM: %s", filename)
 
-	var data []byte
-	var err error
-
-	if strings.Contains(filename, ".gpk") || strings.Contains(filename, "::") {
-		// GPK format: "package.gpk::entry/path.ogg"
-		parts := strings.Split(filename, "::")
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid GPK path format: %s", filename)
-		}
-		data, err = m.loadAudioFromGPK(parts[0], parts[1])
-		if err != nil {
-			return fmt.Errorf("failed to load BGM from GPK: %w", err)
-		}
-	} else {
-		// Regular file
-		data, err = m.loadAudioFromFile(filename)
-		if err != nil {
-			return fmt.Errorf("failed to load BGM from file: %w", err)
-		}
+	stream, err := m.openAudioStream(filename)
+	if err != nil {
+		return fmt.Errorf("failed to load BGM: %w", err)
 	}
+	defer stream.Close()
 
-	// Cache the audio file
-	m.loadedBGM = &AudioFile{
+	file := &AudioFile{
 		Name:      filepath.Base(filename),
 		Path:      filename,
-		Data:      data,
-		IsFromGPK: strings.Contains(filename, ".gpk") || strings.Contains(filename, "::"),
+		IsFromGPK: isGPKOverride(filename),
 	}
-
-	// Close existing BGM player
-	if m.bgmPlayer != nil {
-		m.bgmPlayer.Close()
-		m.bgmPlayer = nil
+	if stream.file == nil {
+		// Preloaded, keep the data for playback
+		file.Data = stream.prefix
 	}
 
-	// Create new BGM player
-	player, err := m.createPlayerFromData(data)
+	head, err := stream.readHead(maxCommentBytes)
 	if err != nil {
-		return fmt.Errorf("failed to create BGM player: %w", err)
+		return fmt.Errorf("failed to read BGM header: %w", err)
+	}
+	if loop, ok := parseLoopPoints(head); ok {
+		file.Loop = &loop
+		log.Printf("BGM loop points: start %d, length %d", loop.Start, loop.Length)
 	}
 
-	m.bgmPlayer = player
-	m.bgmPlayer.SetVolume(m.bgmVolume)
-
+	m.loadedBGM = file
 	log.Printf("BGM loaded successfully: %s", filename)
 	return nil
 }
 
-// PlayBGM starts background music playback
+// PlayBGM starts the loaded background music. loops < 0 loops forever
+// (seamlessly, honouring loop points), otherwise the track plays loops times
+// (at least once). A playing track is crossfaded into the new one.
 func (m *Manager) PlayBGM(loops int) error {
-	if m.muted || m.bgmPlayer == nil {
+	if m.loadedBGM == nil {
 		return nil
 	}
 
-	// Set volume
-	m.bgmPlayer.SetVolume(m.bgmVolume)
+	player, stream, err := m.createBGMPlayer(m.loadedBGM, loops < 0)
+	if err != nil {
+		return fmt.Errorf("failed to create BGM player: %w", err)
+	}
+
+	track := &bgmTrack{
+		name:   m.loadedBGM.Name,
+		path:   m.loadedBGM.Path,
+		player: player,
+		stream: stream,
+		fade:   newFade(1.0, 1.0, 0),
+	}
+	if loops > 1 {
+		track.loopsLeft = loops - 1
+	}
+
+	if m.IsBGMPlaying() || len(m.bgmFadingOut) > 0 {
+		m.fadeOutTrack(m.bgm, m.bgmCrossfade)
+		track.fade = newFade(0.0, 1.0, m.bgmCrossfade)
+	} else {
+		m.closeTrack(m.bgm)
+	}
 
-	// Rewind and play
-	m.bgmPlayer.Rewind()
-	m.bgmPlayer.Play()
+	m.bgm = track
+	m.bgm.player.SetVolume(m.bgmTrackVolume(m.bgm))
+	m.bgm.player.Play()
 
-	log.Printf("Playing BGM (loops: %d)", loops)
+	log.Printf("Playing BGM: %s (loops: %d)", track.name, loops)
 	return nil
 }
 
-// StopBGM stops background music
+// StopBGM stops background music immediately
 func (m *Manager) StopBGM() {
-	if m.bgmPlayer != nil {
-		m.bgmPlayer.Pause()
-		log.Println("BGM stopped")
+	if m.bgm == nil && len(m.bgmFadingOut) == 0 {
+		return
+	}
+
+	m.closeTrack(m.bgm)
+	m.bgm = nil
+	for _, track := range m.bgmFadingOut {
+		m.closeTrack(track)
+	}
+	m.bgmFadingOut = nil
+
+	log.Println("BGM stopped")
+}
+
+// FadeOutBGM fades the current background music out over duration and stops it
+func (m *Manager) FadeOutBGM(duration time.Duration) {
+	if m.bgm == nil {
+		return
+	}
+
+	m.fadeOutTrack(m.bgm, duration)
+	m.bgm = nil
+
+	log.Printf("BGM fading out over %v", duration)
+}
+
+// FadeOutBGMFile fades out the current background music only if it is still filename
+func (m *Manager) FadeOutBGMFile(filename string, duration time.Duration) {
+	if m.bgm != nil && m.bgm.path == filename {
+		m.FadeOutBGM(duration)
+	}
+}
+
+// SetBGMCrossfade sets how long a new BGM takes to replace the current one
+func (m *Manager) SetBGMCrossfade(duration time.Duration) {
+	if duration < 0 {
+		duration = 0
 	}
+	m.bgmCrossfade = duration
 }
 
 // IsBGMPlaying returns true if BGM is currently playing
 func (m *Manager) IsBGMPlaying() bool {
-	return m.bgmPlayer != nil && m.bgmPlayer.IsPlaying()
+	return m.bgm != nil && m.bgm.player.IsPlaying()
+}
+
+// IsBGMFilePlaying returns true if filename is the current background music
+// and has not been faded out
+func (m *Manager) IsBGMFilePlaying(filename string) bool {
+	return m.bgm != nil && m.bgm.path == filename && m.bgm.player.IsPlaying()
 }
 
 // GetCurrentBGMFile returns the currently loaded BGM file name
@@ -96,3 +196,126 @@ func (m *Manager) GetCurrentBGMFile() string {
 	}
 	return ""
 }
+
+// updateBGM advances fades and finite loops (called each frame)
+func (m *Manager) updateBGM(delta time.Duration) {
+	if m.bgm != nil {
+		m.bgm.fade.advance(delta)
+
+		if !m.bgm.player.IsPlaying() {
+			if m.bgm.loopsLeft > 0 {
+				m.bgm.loopsLeft--
+				m.bgm.player.Rewind()
+				m.bgm.player.Play()
+			} else {
+				// Finished playing its loops
+				m.closeTrack(m.bgm)
+				m.bgm = nil
+			}
+		}
+
+		if m.bgm != nil {
+			m.bgm.player.SetVolume(m.bgmTrackVolume(m.bgm))
+		}
+	}
+
+	remaining := m.bgmFadingOut[:0]
+	for _, track := range m.bgmFadingOut {
+		track.fade.advance(delta)
+		if track.fade.done() || !track.player.IsPlaying() {
+			m.closeTrack(track)
+			continue
+		}
+		track.player.SetVolume(m.bgmTrackVolume(track))
+		remaining = append(remaining, track)
+	}
+	m.bgmFadingOut = remaining
+}
+
+// applyBGMVolume updates the volume of all BGM tracks
+func (m *Manager) applyBGMVolume() {
+	if m.bgm != nil {
+		m.bgm.player.SetVolume(m.bgmTrackVolume(m.bgm))
+	}
+	for _, track := range m.bgmFadingOut {
+		track.player.SetVolume(m.bgmTrackVolume(track))
+	}
+}
+
+// bgmTrackVolume returns the playback volume of a track including its fade
+// and voice ducking
+func (m *Manager) bgmTrackVolume(track *bgmTrack) float64 {
+	if m.muted {
+		return 0.0
+	}
+	return m.bgmVolume * track.fade.level() * m.duckLevel
+}
+
+// fadeOutTrack moves a track to the fading list
+func (m *Manager) fadeOutTrack(track *bgmTrack, duration time.Duration) {
+	if track == nil {
+		return
+	}
+	if duration <= 0 {
+		m.closeTrack(track)
+		return
+	}
+
+	track.fade = newFade(track.fade.level(), 0.0, duration)
+	track.loopsLeft = 0
+	m.bgmFadingOut = append(m.bgmFadingOut, track)
+}
+
+// closeTrack releases a track's player and stream
+func (m *Manager) closeTrack(track *bgmTrack) {
+	if track == nil || track.player == nil {
+		return
+	}
+
+	track.player.Close()
+	track.player = nil
+	track.stream.Close()
+	track.stream = nil
+}
+
+// createBGMPlayer opens and decodes a BGM stream, wrapping it in a seamless
+// loop if requested
+func (m *Manager) createBGMPlayer(file *AudioFile, infinite bool) (*audio.Player, *audioStream, error) {
+	stream, err := m.openLoadedStream(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoded, err := vorbis.DecodeWithoutResampling(stream)
+	if err != nil {
+		stream.Close()
+		return nil, nil, fmt.Errorf("failed to decode OGG file: %w", err)
+	}
+
+	var source io.Reader = decoded
+	if infinite {
+		source = newBGMLoop(decoded, file.Loop)
+	}
+
+	player, err := m.context.NewPlayer(source)
+	if err != nil {
+		stream.Close()
+		return nil, nil, fmt.Errorf("failed to create OGG player: %w", err)
+	}
+
+	return player, stream, nil
+}
+
+// newBGMLoop wraps a decoded stream in an infinite loop, using the loop
+// points when they lie inside the track
+func newBGMLoop(stream *vorbis.Stream, loop *LoopPoints) *audio.InfiniteLoop {
+	if loop != nil {
+		intro := loop.Start * bytesPerFrame
+		length := loop.Length * bytesPerFrame
+		if intro+length <= stream.Length() {
+			return audio.NewInfiniteLoopWithIntro(stream, intro, length)
+		}
+		log.Printf("Warning: BGM loop points beyond end of track, looping whole track")
+	}
+	return audio.NewInfiniteLoop(stream, stream.Length())
+}
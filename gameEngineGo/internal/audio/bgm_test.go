@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFadeEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		fade     fadeEnvelope
+		steps    []time.Duration
+		want     []float64 // Level after each step
+		wantDone []bool
+	}{
+		{
+			"fade in",
+			newFade(0, 1, 400*time.Millisecond),
+			[]time.Duration{0, 100 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond, 100 * time.Millisecond},
+			[]float64{0, 0.25, 0.5, 1, 1},
+			[]bool{false, false, false, true, true},
+		},
+		{
+			"fade out",
+			newFade(1, 0, time.Second),
+			[]time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second},
+			[]float64{0.75, 0.25, 0},
+			[]bool{false, false, true},
+		},
+		{
+			"partial crossfade",
+			newFade(0.6, 0.2, 200*time.Millisecond),
+			[]time.Duration{100 * time.Millisecond},
+			[]float64{0.4},
+			[]bool{false},
+		},
+		{
+			"no duration",
+			newFade(1, 0, 0),
+			[]time.Duration{0},
+			[]float64{0},
+			[]bool{true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fade := test.fade
+			for i, step := range test.steps {
+				fade.advance(step)
+				if got := fade.level(); math.Abs(got-test.want[i]) > 1e-9 {
+					t.Errorf("step %d: level %g, want %g", i, got, test.want[i])
+				}
+				if fade.done() != test.wantDone[i] {
+					t.Errorf("step %d: done %v, want %v", i, fade.done(), test.wantDone[i])
+				}
+			}
+		})
+	}
+}
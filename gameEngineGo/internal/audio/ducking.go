@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"log"
+	"time"
+)
+
+// Default ducking settings
+const (
+	DefaultDuckAmount  = 0.5
+	DefaultDuckAttack  = 200 * time.Millisecond
+	DefaultDuckRelease = 600 * time.Millisecond
+)
+
+// SetDucking configures lowering the BGM while a voice line plays. amount is
+// the fraction of BGM volume removed (0.0 to 1.0), attack and release are the
+// times to reach full attenuation and to recover from it.
+func (m *Manager) SetDucking(enabled bool, amount float64, attack, release time.Duration) {
+	if amount < 0.0 {
+		amount = 0.0
+	} else if amount > 1.0 {
+		amount = 1.0
+	}
+	if attack < 0 {
+		attack = 0
+	}
+	if release < 0 {
+		release = 0
+	}
+
+	m.duckEnabled = enabled
+	m.duckAmount = amount
+	m.duckAttack = attack
+	m.duckRelease = release
+
+	log.Printf("BGM ducking: enabled %v, amount %.2f, attack %v, release %v", enabled, amount, attack, release)
+}
+
+// IsDuckingEnabled returns whether BGM ducking is enabled
+func (m *Manager) IsDuckingEnabled() bool {
+	return m.duckEnabled
+}
+
+// GetDuckLevel returns the current BGM gain from ducking (1.0 = not ducked)
+func (m *Manager) GetDuckLevel() float64 {
+	return m.duckLevel
+}
+
+// updateDucking moves the duck level towards its target (called each frame)
+func (m *Manager) updateDucking(delta time.Duration) {
+	target := 1.0
+	if m.duckEnabled && m.IsVoicePlaying() {
+		target = 1.0 - m.duckAmount
+	}
+
+	if m.duckLevel == target {
+		return
+	}
+
+	// Attack when going down, release when coming back up; both move at a
+	// rate that covers the full duck amount in the configured time
+	duration := m.duckRelease
+	if target < m.duckLevel {
+		duration = m.duckAttack
+	}
+	if duration <= 0 || m.duckAmount <= 0 {
+		m.duckLevel = target
+		return
+	}
+
+	step := m.duckAmount * float64(delta) / float64(duration)
+	if target < m.duckLevel {
+		m.duckLevel = max(target, m.duckLevel-step)
+	} else {
+		m.duckLevel = min(target, m.duckLevel+step)
+	}
+}
@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// playTestVoice plays the test OGG as a voice line without a stream behind it
+func playTestVoice(t *testing.T, m *Manager) *audio.Player {
+	t.Helper()
+	_, pcm := decodeTestOgg(t)
+	voice, err := m.context.NewPlayer(bytes.NewReader(pcm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	voice.Play()
+	m.voicePlayer = voice
+	t.Cleanup(func() {
+		m.voicePlayer = nil
+		voice.Close()
+	})
+	return voice
+}
+
+func TestDucking(t *testing.T) {
+	m := newMixerTestManager(t)
+	m.SetDucking(true, 0.5, 200*time.Millisecond, 400*time.Millisecond)
+
+	voice := playTestVoice(t, m)
+
+	// Attack: 0.5 over 200 ms while the voice plays
+	steps := []struct {
+		voice bool
+		delta time.Duration
+		want  float64
+	}{
+		{true, 100 * time.Millisecond, 0.75},
+		{true, 100 * time.Millisecond, 0.5},
+		{true, 100 * time.Millisecond, 0.5},
+		{false, 100 * time.Millisecond, 0.625},
+		{false, 200 * time.Millisecond, 0.875},
+		{false, 200 * time.Millisecond, 1},
+		// A voice starting mid-release ducks again from the current level
+		{true, 50 * time.Millisecond, 0.875},
+	}
+	for i, step := range steps {
+		if step.voice {
+			m.voicePlayer = voice
+		} else {
+			m.voicePlayer = nil
+		}
+		m.updateDucking(step.delta)
+		if got := m.GetDuckLevel(); math.Abs(got-step.want) > 1e-9 {
+			t.Errorf("step %d: duck level %g, want %g", i, got, step.want)
+		}
+	}
+}
+
+func TestDuckingSettings(t *testing.T) {
+	m := newMixerTestManager(t)
+	playTestVoice(t, m)
+
+	// Disabled ducking keeps the BGM at full level
+	m.SetDucking(false, 0.5, 0, 0)
+	m.updateDucking(time.Second)
+	if got := m.GetDuckLevel(); got != 1 {
+		t.Errorf("disabled ducking level %g, want 1", got)
+	}
+
+	// Out-of-range settings are clamped; a zero attack ducks at once
+	m.SetDucking(true, 2, -time.Second, -time.Second)
+	if m.duckAmount != 1 || m.duckAttack != 0 || m.duckRelease != 0 {
+		t.Errorf("settings not clamped: amount %g, attack %v, release %v", m.duckAmount, m.duckAttack, m.duckRelease)
+	}
+	m.updateDucking(time.Millisecond)
+	if got := m.GetDuckLevel(); got != 0 {
+		t.Errorf("instant duck level %g, want 0", got)
+	}
+
+	m.voicePlayer = nil
+	m.updateDucking(time.Millisecond)
+	if got := m.GetDuckLevel(); got != 1 {
+		t.Errorf("instant release level %g, want 1", got)
+	}
+}
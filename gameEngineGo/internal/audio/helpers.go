@@ -21,6 +21,12 @@ func ParseGPKPath(path string) (gpkFile, entryPath string, isGPK bool) {
 	return "", path, false
 }
 
+// isGPKOverride returns true for paths that name their GPK package explicitly
+func isGPKOverride(path string) bool {
+	_, _, isGPK := ParseGPKPath(path)
+	return isGPK
+}
+
 // BuildGPKPath constructs a GPK path from components
 func BuildGPKPath(gpkFile, entryPath string) string {
 	return fmt.Sprintf("%s::%s", gpkFile, entryPath)
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -13,15 +12,10 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 )
 
-// loadAudioFromFile loads audio data from a file path
+// loadAudioFromFile loads audio data through the filesystem (mounted GPK
+// archives first, then loose files)
 func (m *Manager) loadAudioFromFile(filePath string) ([]byte, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
-	}
-	defer file.Close()
-
-	data, err := io.ReadAll(file)
+	data, err := m.filesystem.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
@@ -29,11 +23,9 @@ func (m *Manager) loadAudioFromFile(filePath string) ([]byte, error) {
 	return data, nil
 }
 
-// loadAudioFromGPK loads audio data from a GPK package
+// loadAudioFromGPK loads audio data from an explicitly named GPK package
 func (m *Manager) loadAudioFromGPK(gpkFile, entryPath string) ([]byte, error) {
-	// TODO: Connect with GPK filesystem manager
-	// For now, return an error
-	return nil, fmt.Errorf("GPK loading not yet implemented - need to connect with filesystem manager")
+	return m.filesystem.ReadArchiveFile(gpkFile, entryPath)
 }
 
 // createPlayerFromData creates an audio player from raw data
@@ -45,7 +37,12 @@ func (m *Manager) createPlayerFromData(data []byte) (*audio.Player, error) {
 		fixedData = data
 	}
 
-	stream, err := vorbis.DecodeWithoutResampling(bytes.NewReader(fixedData))
+	return m.createPlayerFromStream(bytes.NewReader(fixedData))
+}
+
+// createPlayerFromStream creates an audio player decoding from a seekable source
+func (m *Manager) createPlayerFromStream(source io.ReadSeeker) (*audio.Player, error) {
+	stream, err := vorbis.DecodeWithoutResampling(source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode OGG file: %w", err)
 	}
@@ -80,7 +77,7 @@ func (m *Manager) PreloadAudioFiles(audioDir string) error {
 	// Try to load system sounds
 	for id, filename := range systemSounds {
 		fullPath := filepath.Join(audioDir, filename)
-		if _, err := os.Stat(fullPath); err == nil {
+		if m.filesystem.Exists(fullPath) {
 			if err := m.LoadSoundEffect(fullPath, id); err != nil {
 				log.Printf("Warning: Failed to preload %s: %v", filename, err)
 			}
@@ -91,7 +88,9 @@ func (m *Manager) PreloadAudioFiles(audioDir string) error {
 	return nil
 }
 
-// GetMemoryUsage returns the current memory usage of loaded audio files
+// GetMemoryUsage returns the current memory usage of loaded audio files.
+// Streams count what they buffer: all of in-memory data, only the header of
+// files read on demand.
 func (m *Manager) GetMemoryUsage() int {
 	var totalBytes int
 
@@ -104,6 +103,25 @@ func (m *Manager) GetMemoryUsage() int {
 			totalBytes += len(sound.Data)
 		}
 	}
+	totalBytes += m.soundCacheBytes
+
+	if m.bgm != nil && m.bgm.stream != nil {
+		totalBytes += m.bgm.stream.Buffered()
+	}
+	for _, track := range m.bgmFadingOut {
+		if track.stream != nil {
+			totalBytes += track.stream.Buffered()
+		}
+	}
+	if m.voiceStream != nil {
+		totalBytes += m.voiceStream.Buffered()
+	}
+
+	m.preloadMutex.Lock()
+	for _, data := range m.preloaded {
+		totalBytes += len(data)
+	}
+	m.preloadMutex.Unlock()
 
 	return totalBytes
 }
@@ -115,11 +133,12 @@ func (m *Manager) GetAudioInfo() map[string]interface{} {
 	// BGM info
 	if m.loadedBGM != nil {
 		info["bgm"] = map[string]interface{}{
-			"name":    m.loadedBGM.Name,
-			"path":    m.loadedBGM.Path,
-			"size":    len(m.loadedBGM.Data),
-			"fromGPK": m.loadedBGM.IsFromGPK,
-			"playing": m.IsBGMPlaying(),
+			"name":     m.loadedBGM.Name,
+			"path":     m.loadedBGM.Path,
+			"size":     len(m.loadedBGM.Data),
+			"fromGPK":  m.loadedBGM.IsFromGPK,
+			"streamed": m.loadedBGM.Data == nil,
+			"playing":  m.IsBGMPlaying(),
 		}
 	}
 
@@ -138,12 +157,22 @@ func (m *Manager) GetAudioInfo() map[string]interface{} {
 	}
 	info["soundEffects"] = soundEffects
 
+	// Voice info
+	if m.voicePlayer != nil {
+		info["voice"] = map[string]interface{}{
+			"name":    filepath.Base(m.voiceFile),
+			"persona": m.voicePersona,
+			"playing": m.IsVoicePlaying(),
+		}
+	}
+
 	// Volume info
 	info["volumes"] = map[string]interface{}{
 		"bgm":   m.bgmVolume,
 		"se":    m.seVolume,
 		"voice": m.voiceVolume,
 		"muted": m.muted,
+		"duck":  m.duckLevel,
 	}
 
 	// Memory usage
@@ -228,9 +257,10 @@ func (m *Manager) LoadFromScript(command, filename string, options map[string]in
 		}
 
 	case "voice", "play_voice":
-		// TODO: Implement voice playback (similar to SE but with voice volume)
-		log.Printf("Voice playback not yet implemented: %s", filename)
-		return fmt.Errorf("voice playback not yet implemented")
+		persona, _ := options["persona"].(string)
+		if err := m.PlayVoice(filename, persona); err != nil {
+			return fmt.Errorf("failed to play voice from script: %w", err)
+		}
 
 	default:
 		return fmt.Errorf("unknown audio command: %s", command)
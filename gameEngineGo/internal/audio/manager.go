@@ -1,16 +1,50 @@
 package audio
 
 import (
+	"io"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
 )
 
+// FileSystemInterface defines the filesystem operations needed to load audio
+// (implemented by filesystem.Manager, which resolves GPK mounts and loose files)
+type FileSystemInterface interface {
+	Open(filename string) (io.ReadCloser, error)
+	ReadFile(filename string) ([]byte, error)
+	Exists(filename string) bool
+	ReadArchiveFile(archive, entry string) ([]byte, error)
+	OpenArchiveFile(archive, entry string) (io.ReadCloser, error)
+}
+
 // Manager handles all audio playback - supports the same interface as C++ version
 type Manager struct {
 	context      *audio.Context
-	bgmPlayer    *audio.Player
 	soundPlayers [SndSize]*audio.Player
+	seChannels   [SeChannels]seChannel
+
+	// BGM channel - the current track plus tracks still fading out
+	bgm          *bgmTrack
+	bgmFadingOut []*bgmTrack
+	bgmCrossfade time.Duration
+	lastUpdate   time.Time
+
+	// BGM ducking while a voice line plays
+	duckEnabled bool
+	duckAmount  float64
+	duckAttack  time.Duration
+	duckRelease time.Duration
+	duckLevel   float64
+
+	// Voice channel - one line at a time
+	voicePlayer          *audio.Player
+	voiceStream          *audioStream
+	voiceFile            string
+	voicePersona         string
+	voiceFinishedHandler func(filename string)
+	personaVoices        map[string]PersonaVoice
 
 	// Volume controls (0.0 to 1.0)
 	bgmVolume   float64
@@ -22,17 +56,42 @@ type Manager struct {
 	loadedBGM    *AudioFile
 	loadedSounds [SndSize]*AudioFile
 
-	// GPK integration - will be set by the filesystem manager
-	gpkManager interface{} // Will be set to filesystem.Manager
+	// Decoded-ready data of one-shot sound effects, oldest first
+	soundCache      map[string][]byte
+	soundCacheOrder []string
+	soundCacheBytes int
+
+	// Asset source for all audio files
+	filesystem FileSystemInterface
+
+	// Audio data read ahead of time by the script preloader, and sound
+	// effects it decoded to PCM (oldest first)
+	preloaded    map[string][]byte
+	decoded      map[string][]byte
+	decodedOrder []string
+	decodedBytes int
+	preloadMutex sync.Mutex
 }
 
-// NewManager creates a new audio manager
-func NewManager() *Manager {
+// NewManager creates a new audio manager loading files through filesystem
+func NewManager(filesystem FileSystemInterface) *Manager {
 	return &Manager{
+		filesystem:  filesystem,
 		bgmVolume:   1.0,
 		seVolume:    1.0,
 		voiceVolume: 1.0,
 		muted:       false,
+		preloaded:   make(map[string][]byte),
+		decoded:     make(map[string][]byte),
+		soundCache:  make(map[string][]byte),
+
+		bgmCrossfade: DefaultBGMCrossfade,
+		duckAmount:   DefaultDuckAmount,
+		duckAttack:   DefaultDuckAttack,
+		duckRelease:  DefaultDuckRelease,
+		duckLevel:    1.0,
+
+		personaVoices: make(map[string]PersonaVoice),
 	}
 }
 
@@ -40,35 +99,38 @@ func NewManager() *Manager {
 func (m *Manager) Init() error {
 	var err error
 
-	// Create audio context with standard sample rate
-	m.context = audio.NewContext(SampleRate)
+	// Create audio context with standard sample rate. Ebiten allows one
+	// context per process, shared by every manager.
+	if m.context = audio.CurrentContext(); m.context == nil {
+		m.context = audio.NewContext(SampleRate)
+	}
 
 	log.Println("Audio manager initialized")
 	return err
 }
 
-// SetGPKManager sets the GPK manager for loading audio from packages
-func (m *Manager) SetGPKManager(gpkManager interface{}) {
-	m.gpkManager = gpkManager
-}
-
 // Update updates the audio system (called each frame)
 func (m *Manager) Update() error {
-	// Handle BGM looping logic here
-	if m.bgmPlayer != nil && !m.bgmPlayer.IsPlaying() {
-		// BGM finished, implement looping logic if needed
-		// This will be expanded in the future for proper looping control
+	now := time.Now()
+	if m.lastUpdate.IsZero() {
+		m.lastUpdate = now
 	}
+	delta := now.Sub(m.lastUpdate)
+	m.lastUpdate = now
+
+	m.updateDucking(delta)
+	m.updateBGM(delta)
+	m.updateSeChannels()
+	m.updateVoice()
 
 	return nil
 }
 
 // Cleanup properly closes all audio resources
 func (m *Manager) Cleanup() {
-	if m.bgmPlayer != nil {
-		m.bgmPlayer.Close()
-		m.bgmPlayer = nil
-	}
+	m.StopBGM()
+	m.StopVoice()
+	m.StopAllSeChannels()
 
 	for i := range m.soundPlayers {
 		if m.soundPlayers[i] != nil {
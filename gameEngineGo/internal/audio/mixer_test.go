@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"os"
+	"testing"
+)
+
+// newMixerTestManager creates an initialised manager with the test OGG
+// cached under each of names
+func newMixerTestManager(t *testing.T, names ...string) *Manager {
+	t.Helper()
+	data, err := os.ReadFile("testdata/test.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(nil)
+	if err := m.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Cleanup)
+	for _, name := range names {
+		m.soundCache[name] = data
+	}
+	return m
+}
+
+func TestSeChannelReplace(t *testing.T) {
+	m := newMixerTestManager(t, "Se00/A.ogg", "Se00/B.ogg")
+
+	if err := m.PlaySeChannel(3, "Se00/A.ogg", false); err != nil {
+		t.Fatal(err)
+	}
+	first := m.seChannels[3].player
+	if err := m.PlaySeChannel(3, "Se00/B.ogg", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.IsPlaying() {
+		t.Error("replaced sound still playing")
+	}
+	if file := m.GetSeChannelFile(3); file != "Se00/B.ogg" || !m.IsSeChannelPlaying(3) || !m.seChannels[3].loop {
+		t.Errorf("channel 3 plays %q (playing %v, loop %v), want looping Se00/B.ogg", file, m.IsSeChannelPlaying(3), m.seChannels[3].loop)
+	}
+	if m.IsSeChannelPlaying(2) || m.IsSeChannelPlaying(4) {
+		t.Error("neighbouring channels playing")
+	}
+
+	for _, channel := range []int{-1, SeChannels} {
+		if err := m.PlaySeChannel(channel, "Se00/A.ogg", false); err == nil {
+			t.Errorf("channel %d accepted", channel)
+		}
+	}
+}
+
+func TestStopSeChannelFile(t *testing.T) {
+	m := newMixerTestManager(t, "Se00/A.ogg", "Se00/B.ogg")
+
+	if err := m.PlaySeChannel(1, "Se00/B.ogg", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A sound ending after another replaced it leaves the channel alone
+	m.StopSeChannelFile(1, "Se00/A.ogg")
+	m.StopSeChannelFile(2, "Se00/B.ogg")
+	if !m.IsSeChannelPlaying(1) {
+		t.Fatal("channel stopped for another file")
+	}
+
+	m.StopSeChannelFile(1, "Se00/B.ogg")
+	if m.IsSeChannelPlaying(1) || m.GetSeChannelFile(1) != "" {
+		t.Error("channel still playing its file")
+	}
+}
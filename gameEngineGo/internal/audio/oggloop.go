@@ -0,0 +1,124 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+)
+
+// LoopPoints marks the looped section of a track in sample frames, read from
+// the LOOPSTART/LOOPLENGTH Vorbis comments (the RPG Maker convention)
+type LoopPoints struct {
+	Start  int64
+	Length int64
+}
+
+// bytesPerFrame is the size of one 16-bit stereo sample frame of a decoded stream
+const bytesPerFrame = 4
+
+// Limits on how far into the file the comment header is searched
+const (
+	maxCommentPages = 4
+	maxCommentBytes = 64 * 1024
+)
+
+// parseLoopPoints reads loop points from the Vorbis comment header of Ogg data
+func parseLoopPoints(data []byte) (LoopPoints, bool) {
+	comments := parseVorbisComments(oggHeaderPayload(data))
+
+	start, startErr := strconv.ParseInt(comments["LOOPSTART"], 10, 64)
+	length, lengthErr := strconv.ParseInt(comments["LOOPLENGTH"], 10, 64)
+	if startErr != nil || lengthErr != nil || start < 0 || length <= 0 {
+		return LoopPoints{}, false
+	}
+
+	return LoopPoints{Start: start, Length: length}, true
+}
+
+// oggHeaderPayload joins the packet data of the first Ogg pages, so a comment
+// header split across pages can be read in one piece
+func oggHeaderPayload(data []byte) []byte {
+	var payload []byte
+	offset := 0
+
+	for page := 0; page < maxCommentPages; page++ {
+		if offset+27 > len(data) || string(data[offset:offset+4]) != "OggS" {
+			break
+		}
+
+		segments := int(data[offset+26])
+		tableStart := offset + 27
+		if tableStart+segments > len(data) {
+			break
+		}
+
+		size := 0
+		for _, lacing := range data[tableStart : tableStart+segments] {
+			size += int(lacing)
+		}
+
+		bodyStart := tableStart + segments
+		if bodyStart+size > len(data) {
+			payload = append(payload, data[bodyStart:]...)
+			break
+		}
+		payload = append(payload, data[bodyStart:bodyStart+size]...)
+		offset = bodyStart + size
+	}
+
+	// Page headers damaged (e.g. GPK entries), search the raw bytes instead
+	if len(payload) == 0 {
+		return data[:min(len(data), maxCommentBytes)]
+	}
+	return payload
+}
+
+// parseVorbisComments extracts KEY=value pairs (keys upper-cased) from the
+// first Vorbis comment header found in payload
+func parseVorbisComments(payload []byte) map[string]string {
+	comments := make(map[string]string)
+
+	idx := bytes.Index(payload, []byte("\x03vorbis"))
+	if idx < 0 {
+		return comments
+	}
+	reader := payload[idx+7:]
+
+	readString := func() (string, bool) {
+		if len(reader) < 4 {
+			return "", false
+		}
+		length := binary.LittleEndian.Uint32(reader)
+		reader = reader[4:]
+		if uint64(length) > uint64(len(reader)) {
+			return "", false
+		}
+		value := string(reader[:length])
+		reader = reader[length:]
+		return value, true
+	}
+
+	// Vendor string
+	if _, ok := readString(); !ok {
+		return comments
+	}
+
+	if len(reader) < 4 {
+		return comments
+	}
+	count := binary.LittleEndian.Uint32(reader)
+	reader = reader[4:]
+
+	for i := uint32(0); i < count; i++ {
+		comment, ok := readString()
+		if !ok {
+			break
+		}
+		if key, value, found := strings.Cut(comment, "="); found {
+			comments[strings.ToUpper(key)] = value
+		}
+	}
+
+	return comments
+}
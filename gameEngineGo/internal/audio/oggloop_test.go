@@ -0,0 +1,122 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+// commentHeader builds a Vorbis comment header packet
+func commentHeader(comments ...string) []byte {
+	var packet bytes.Buffer
+	packet.WriteString("\x03vorbis")
+	binary.Write(&packet, binary.LittleEndian, uint32(4))
+	packet.WriteString("test")
+	binary.Write(&packet, binary.LittleEndian, uint32(len(comments)))
+	for _, comment := range comments {
+		binary.Write(&packet, binary.LittleEndian, uint32(len(comment)))
+		packet.WriteString(comment)
+	}
+	return packet.Bytes()
+}
+
+// oggPage wraps a packet in a single Ogg page
+func oggPage(packet []byte) []byte {
+	page := append([]byte("OggS"), make([]byte, 22)...)
+	var lacing []byte
+	for size := len(packet); ; size -= 255 {
+		if size < 255 {
+			lacing = append(lacing, byte(size))
+			break
+		}
+		lacing = append(lacing, 255)
+	}
+	page = append(page, byte(len(lacing)))
+	page = append(page, lacing...)
+	return append(page, packet...)
+}
+
+func TestParseLoopPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want LoopPoints
+		ok   bool
+	}{
+		{"loop points", oggPage(commentHeader("TITLE=x", "LOOPSTART=44100", "LOOPLENGTH=88200")), LoopPoints{44100, 88200}, true},
+		{"lower-case keys", oggPage(commentHeader("loopstart=0", "looplength=100")), LoopPoints{0, 100}, true},
+		{"damaged page header", append([]byte("XXXX"), commentHeader("LOOPSTART=10", "LOOPLENGTH=20")...), LoopPoints{10, 20}, true},
+		{"no length", oggPage(commentHeader("LOOPSTART=10")), LoopPoints{}, false},
+		{"zero length", oggPage(commentHeader("LOOPSTART=10", "LOOPLENGTH=0")), LoopPoints{}, false},
+		{"negative start", oggPage(commentHeader("LOOPSTART=-1", "LOOPLENGTH=20")), LoopPoints{}, false},
+		{"not a number", oggPage(commentHeader("LOOPSTART=ten", "LOOPLENGTH=20")), LoopPoints{}, false},
+		{"no comments", oggPage([]byte("\x01vorbis")), LoopPoints{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := parseLoopPoints(test.data)
+			if got != test.want || ok != test.ok {
+				t.Errorf("got %+v (ok %v), want %+v (ok %v)", got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+// decodeTestOgg decodes the test OGG, returning a fresh stream and its PCM
+func decodeTestOgg(t *testing.T) (*vorbis.Stream, []byte) {
+	t.Helper()
+	data, err := os.ReadFile("testdata/test.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := decodePCM(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := vorbis.DecodeWithoutResampling(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stream, pcm
+}
+
+func TestBGMLoopWrap(t *testing.T) {
+	stream, pcm := decodeTestOgg(t)
+	frames := int64(len(pcm)) / bytesPerFrame
+	loop := &LoopPoints{Start: frames / 4, Length: frames / 2}
+	intro, length := loop.Start*bytesPerFrame, loop.Length*bytesPerFrame
+
+	// Intro, loop body, then the loop body again from the loop start
+	got := make([]byte, intro+2*length)
+	if _, err := io.ReadFull(newBGMLoop(stream, loop), got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got[:intro+length], pcm[:intro+length]) {
+		t.Error("intro and first pass differ from the track")
+	}
+	// Ebiten blends the first 256 frames after the wrap with the audio past
+	// the loop end to avoid a click
+	blend := int64(256 * bytesPerFrame)
+	if !bytes.Equal(got[intro+length+blend:], pcm[intro+blend:intro+length]) {
+		t.Error("second pass does not restart at the loop start")
+	}
+}
+
+func TestBGMLoopBeyondEnd(t *testing.T) {
+	stream, pcm := decodeTestOgg(t)
+	frames := int64(len(pcm)) / bytesPerFrame
+
+	// Loop points past the end fall back to looping the whole track
+	got := make([]byte, 2*len(pcm))
+	if _, err := io.ReadFull(newBGMLoop(stream, &LoopPoints{Start: frames / 2, Length: frames}), got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got[:len(pcm)], pcm) || !bytes.Equal(got[len(pcm):], pcm) {
+		t.Error("track not looped from the start")
+	}
+}
@@ -0,0 +1,187 @@
+package audio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+// maxDecodedBytes limits the PCM kept for preloaded sound effects, about
+// three minutes of 44.1 kHz stereo
+const maxDecodedBytes = 32 * 1024 * 1024
+
+// PreloadAudio reads an audio file into memory so that a later LoadBGM or
+// PlaySoundEffectByName for the same file does not block on I/O.
+// Safe to call from a background goroutine.
+func (m *Manager) PreloadAudio(filename string) error {
+	m.preloadMutex.Lock()
+	_, exists := m.preloaded[filename]
+	m.preloadMutex.Unlock()
+	if exists {
+		return nil
+	}
+
+	data, err := m.readAudioFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// Fix the header now so playback only has to decode
+	if fixedData, err := m.fixOggHeader(data); err == nil {
+		data = fixedData
+	}
+
+	m.preloadMutex.Lock()
+	m.preloaded[filename] = data
+	m.preloadMutex.Unlock()
+	return nil
+}
+
+// PreloadSound reads and decodes a sound effect to PCM so that a later
+// PlaySeChannel for the same file does not block on I/O or Vorbis decoding.
+// Decoded sounds stay cached until ClearPreloaded, since scripts replay them.
+// Safe to call from a background goroutine.
+func (m *Manager) PreloadSound(filename string) error {
+	if m.IsPreloaded(filename) {
+		return nil
+	}
+
+	data, err := m.readAudioFile(filename)
+	if err != nil {
+		return err
+	}
+	if fixedData, err := m.fixOggHeader(data); err == nil {
+		data = fixedData
+	}
+
+	pcm, err := decodePCM(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", filename, err)
+	}
+
+	m.preloadMutex.Lock()
+	defer m.preloadMutex.Unlock()
+
+	// Drop the oldest sounds until the new one fits
+	for m.decodedBytes+len(pcm) > maxDecodedBytes && len(m.decodedOrder) > 0 {
+		oldest := m.decodedOrder[0]
+		m.decodedOrder = m.decodedOrder[1:]
+		m.decodedBytes -= len(m.decoded[oldest])
+		delete(m.decoded, oldest)
+	}
+
+	m.decoded[filename] = pcm
+	m.decodedOrder = append(m.decodedOrder, filename)
+	m.decodedBytes += len(pcm)
+	return nil
+}
+
+// decodePCM decodes OGG Vorbis data to 16-bit stereo PCM
+func decodePCM(data []byte) ([]byte, error) {
+	stream, err := vorbis.DecodeWithoutResampling(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	pcm := make([]byte, stream.Length())
+	if _, err := io.ReadFull(stream, pcm); err != nil {
+		return nil, err
+	}
+	return pcm, nil
+}
+
+// decodedSound returns the PCM of a sound decoded by PreloadSound
+func (m *Manager) decodedSound(filename string) ([]byte, bool) {
+	m.preloadMutex.Lock()
+	defer m.preloadMutex.Unlock()
+
+	pcm, exists := m.decoded[filename]
+	return pcm, exists
+}
+
+// IsPreloaded returns true if the file is waiting in the preload cache or
+// was decoded by PreloadSound
+func (m *Manager) IsPreloaded(filename string) bool {
+	m.preloadMutex.Lock()
+	defer m.preloadMutex.Unlock()
+
+	_, exists := m.preloaded[filename]
+	_, decoded := m.decoded[filename]
+	return exists || decoded
+}
+
+// ClearPreloaded drops all preloaded audio data (e.g. after a scene change)
+func (m *Manager) ClearPreloaded() {
+	m.preloadMutex.Lock()
+	defer m.preloadMutex.Unlock()
+
+	for name := range m.preloaded {
+		delete(m.preloaded, name)
+	}
+	for name := range m.decoded {
+		delete(m.decoded, name)
+	}
+	m.decodedOrder = nil
+	m.decodedBytes = 0
+}
+
+// DiscardPreloaded drops preloaded data for a single file
+func (m *Manager) DiscardPreloaded(filename string) {
+	m.takePreloaded(filename)
+
+	m.preloadMutex.Lock()
+	defer m.preloadMutex.Unlock()
+
+	if pcm, exists := m.decoded[filename]; exists {
+		delete(m.decoded, filename)
+		m.decodedBytes -= len(pcm)
+		for i, name := range m.decodedOrder {
+			if name == filename {
+				m.decodedOrder = append(m.decodedOrder[:i], m.decodedOrder[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// takePreloaded returns and removes preloaded data for a file
+func (m *Manager) takePreloaded(filename string) ([]byte, bool) {
+	m.preloadMutex.Lock()
+	defer m.preloadMutex.Unlock()
+
+	data, exists := m.preloaded[filename]
+	if exists {
+		delete(m.preloaded, filename)
+	}
+	return data, exists
+}
+
+// readAudioData returns audio data from the preload cache, a GPK package or disk
+func (m *Manager) readAudioData(filename string) ([]byte, error) {
+	if data, ok := m.takePreloaded(filename); ok {
+		log.Printf("Using preloaded audio: %s", filename)
+		return data, nil
+	}
+	return m.readAudioFile(filename)
+}
+
+// readAudioFile reads audio data through the filesystem, or from an explicit
+// "package.gpk::entry/path.ogg" override
+func (m *Manager) readAudioFile(filename string) ([]byte, error) {
+	if gpkFile, entryPath, isGPK := ParseGPKPath(filename); isGPK {
+		data, err := m.loadAudioFromGPK(gpkFile, entryPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audio from GPK: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := m.loadAudioFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audio from file: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"os"
+	"testing"
+)
+
+// countingFS serves the test OGG for every file and counts the reads
+type countingFS struct {
+	FileSystemInterface
+	data  []byte
+	reads int
+}
+
+func (f *countingFS) ReadFile(filename string) ([]byte, error) {
+	f.reads++
+	return f.data, nil
+}
+
+func TestPreloadSound(t *testing.T) {
+	data, err := os.ReadFile("testdata/test.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := &countingFS{data: data}
+	m := NewManager(fs)
+	if err := m.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Cleanup)
+
+	if err := m.PreloadSound("Se00/A.ogg"); err != nil {
+		t.Fatal(err)
+	}
+	pcm, ok := m.decodedSound("Se00/A.ogg")
+	if !ok || len(pcm) == 0 || len(pcm)%4 != 0 {
+		t.Fatalf("decoded %d bytes (ok %v), want whole 16-bit stereo frames", len(pcm), ok)
+	}
+	if !m.IsPreloaded("Se00/A.ogg") {
+		t.Error("decoded sound not reported as preloaded")
+	}
+
+	// Playback uses the PCM, repeatedly, without reading the file again
+	for i := 0; i < 2; i++ {
+		if err := m.PlaySeChannel(0, "Se00/A.ogg", i == 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fs.reads != 1 {
+		t.Errorf("file read %d times, want once by the preload", fs.reads)
+	}
+
+	m.DiscardPreloaded("Se00/A.ogg")
+	if m.IsPreloaded("Se00/A.ogg") || m.decodedBytes != 0 || len(m.decodedOrder) != 0 {
+		t.Error("discarded sound still cached")
+	}
+
+	if err := m.PreloadSound("Se00/B.ogg"); err != nil {
+		t.Fatal(err)
+	}
+	m.ClearPreloaded()
+	if m.IsPreloaded("Se00/B.ogg") || m.decodedBytes != 0 {
+		t.Error("cleared sound still cached")
+	}
+}
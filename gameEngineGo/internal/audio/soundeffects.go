@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
-	"strings"
 )
 
 // LoadSoundEffect loads a sound effect with the given ID
@@ -15,25 +14,9 @@ func (m *Manager) LoadSoundEffect(filename string, id int) error {
 
 	log.Printf("Loading sound effect: %s (ID: %d)", filename, id)
 
-	var data []byte
-	var err error
-
-	if strings.Contains(filename, ".gpk") || strings.Contains(filename, "::") {
-		// GPK format
-		parts := strings.Split(filename, "::")
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid GPK path format: %s", filename)
-		}
-		data, err = m.loadAudioFromGPK(parts[0], parts[1])
-		if err != nil {
-			return fmt.Errorf("failed to load sound effect from GPK: %w", err)
-		}
-	} else {
-		// Regular file
-		data, err = m.loadAudioFromFile(filename)
-		if err != nil {
-			return fmt.Errorf("failed to load sound effect from file: %w", err)
-		}
+	data, err := m.readAudioData(filename)
+	if err != nil {
+		return fmt.Errorf("failed to load sound effect: %w", err)
 	}
 
 	// Cache the audio file
@@ -41,7 +24,7 @@ func (m *Manager) LoadSoundEffect(filename string, id int) error {
 		Name:      filepath.Base(filename),
 		Path:      filename,
 		Data:      data,
-		IsFromGPK: strings.Contains(filename, ".gpk") || strings.Contains(filename, "::"),
+		IsFromGPK: isGPKOverride(filename),
 	}
 
 	// Create and cache the player
@@ -82,28 +65,15 @@ func (m *Manager) PlaySoundEffect(id int) {
 	}
 }
 
+// maxSoundCacheBytes limits the memory kept for one-shot sound effects
+const maxSoundCacheBytes = 16 * 1024 * 1024
+
 // PlaySoundEffectByName loads and plays a sound effect by filename
 func (m *Manager) PlaySoundEffectByName(filename string) error {
 	// Create a temporary player for one-shot sounds
-	var data []byte
-	var err error
-
-	if strings.Contains(filename, ".gpk") || strings.Contains(filename, "::") {
-		// GPK format
-		parts := strings.Split(filename, "::")
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid GPK path format: %s", filename)
-		}
-		data, err = m.loadAudioFromGPK(parts[0], parts[1])
-		if err != nil {
-			return fmt.Errorf("failed to load sound effect from GPK: %w", err)
-		}
-	} else {
-		// Regular file
-		data, err = m.loadAudioFromFile(filename)
-		if err != nil {
-			return fmt.Errorf("failed to load sound effect from file: %w", err)
-		}
+	data, err := m.cachedSoundData(filename)
+	if err != nil {
+		return fmt.Errorf("failed to load sound effect: %w", err)
 	}
 
 	if m.muted {
@@ -122,6 +92,35 @@ func (m *Manager) PlaySoundEffectByName(filename string) error {
 	return nil
 }
 
+// cachedSoundData returns sound effect data, keeping recently used effects
+// in memory since they are short and replayed often
+func (m *Manager) cachedSoundData(filename string) ([]byte, error) {
+	if data, exists := m.soundCache[filename]; exists {
+		return data, nil
+	}
+
+	data, err := m.readAudioData(filename)
+	if err != nil {
+		return nil, err
+	}
+	if fixedData, err := m.fixOggHeader(data); err == nil {
+		data = fixedData
+	}
+
+	// Drop the oldest effects until the new one fits
+	for m.soundCacheBytes+len(data) > maxSoundCacheBytes && len(m.soundCacheOrder) > 0 {
+		oldest := m.soundCacheOrder[0]
+		m.soundCacheOrder = m.soundCacheOrder[1:]
+		m.soundCacheBytes -= len(m.soundCache[oldest])
+		delete(m.soundCache, oldest)
+	}
+
+	m.soundCache[filename] = data
+	m.soundCacheOrder = append(m.soundCacheOrder, filename)
+	m.soundCacheBytes += len(data)
+	return data, nil
+}
+
 // PlaySystemSound plays a system sound by name
 func (m *Manager) PlaySystemSound(soundName string) {
 	if id, found := GetSystemSoundID(soundName); found {
@@ -0,0 +1,206 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// oggHeaderProbeSize is how much of a file is read to repair its Ogg header
+const oggHeaderProbeSize = 64
+
+// audioStream is a seekable Ogg source for the decoder. Loose files and GPK
+// entries are read through the filesystem on demand, with a repaired header
+// served from memory in front of the file body; preloaded data is served
+// from memory.
+type audioStream struct {
+	prefix []byte
+	body   *io.SectionReader
+	offset int64
+	file   seekableFile // nil for in-memory data
+	shared bool         // prefix is the Data of a loaded AudioFile, counted there
+}
+
+// newMemoryStream creates a stream over audio data already in memory
+func (m *Manager) newMemoryStream(data []byte) *audioStream {
+	if fixedData, err := m.fixOggHeader(data); err == nil {
+		data = fixedData
+	}
+	return &audioStream{
+		prefix: data,
+		body:   io.NewSectionReader(bytes.NewReader(nil), 0, 0),
+	}
+}
+
+// seekableFile is a file handle that can be streamed without reading it
+// whole (an *os.File or a filesystem.ArchiveFile)
+type seekableFile interface {
+	io.ReaderAt
+	io.Closer
+	Stat() (fs.FileInfo, error)
+}
+
+// bufferedFile is implemented by files whose data is held in memory, such as
+// GPK entries that had to be unpacked
+type bufferedFile interface {
+	Buffered() bool
+}
+
+// openFileStream opens an audio file through the filesystem for streaming
+func (m *Manager) openFileStream(filePath string) (*audioStream, error) {
+	reader, err := m.filesystem.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+	return m.newFileStream(reader, filePath)
+}
+
+// newFileStream creates a stream over an open file, taking ownership of it.
+// Readers without ReadAt are read into memory.
+func (m *Manager) newFileStream(reader io.ReadCloser, filePath string) (*audioStream, error) {
+	file, ok := reader.(seekableFile)
+	if !ok {
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+		}
+		return m.newMemoryStream(data), nil
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat file %s: %w", filePath, err)
+	}
+
+	head := make([]byte, oggHeaderProbeSize)
+	n, err := file.ReadAt(head, 0)
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		file.Close()
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	head = head[:n]
+
+	// Only the first bytes can be damaged, so the repaired head replaces
+	// them and the rest of the file is streamed as is
+	if fixedHead, err := m.fixOggHeader(head); err == nil {
+		head = fixedHead
+	}
+
+	return &audioStream{
+		prefix: head,
+		body:   io.NewSectionReader(file, int64(n), info.Size()-int64(n)),
+		file:   file,
+	}, nil
+}
+
+// openAudioStream opens an audio file for streaming from the preload cache,
+// an explicit "package.gpk::entry" override or the filesystem
+func (m *Manager) openAudioStream(filename string) (*audioStream, error) {
+	if data, ok := m.takePreloaded(filename); ok {
+		return m.newMemoryStream(data), nil
+	}
+
+	if gpkFile, entryPath, isGPK := ParseGPKPath(filename); isGPK {
+		reader, err := m.filesystem.OpenArchiveFile(gpkFile, entryPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audio from GPK: %w", err)
+		}
+		return m.newFileStream(reader, filename)
+	}
+
+	return m.openFileStream(filename)
+}
+
+// openLoadedStream opens a stream for a loaded audio file
+func (m *Manager) openLoadedStream(file *AudioFile) (*audioStream, error) {
+	if file.Data != nil {
+		stream := m.newMemoryStream(file.Data)
+		stream.shared = true
+		return stream, nil
+	}
+	return m.openFileStream(file.Path)
+}
+
+// Read implements io.Reader
+func (s *audioStream) Read(p []byte) (int, error) {
+	prefixLen := int64(len(s.prefix))
+	if s.offset < prefixLen {
+		n := copy(p, s.prefix[s.offset:])
+		s.offset += int64(n)
+		return n, nil
+	}
+
+	n, err := s.body.ReadAt(p, s.offset-prefixLen)
+	s.offset += int64(n)
+	if n > 0 && errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker
+func (s *audioStream) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = s.offset + offset
+	case io.SeekEnd:
+		target = s.Size() + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+
+	if target < 0 {
+		return 0, fmt.Errorf("negative seek position: %d", target)
+	}
+	s.offset = target
+	return target, nil
+}
+
+// Size returns the total size of the stream in bytes
+func (s *audioStream) Size() int64 {
+	return int64(len(s.prefix)) + s.body.Size()
+}
+
+// Buffered returns how many bytes the stream holds in memory: in-memory
+// data, the repaired header of a file, and file bodies unpacked into memory
+func (s *audioStream) Buffered() int {
+	if s.shared {
+		return 0
+	}
+	size := len(s.prefix)
+	if file, ok := s.file.(bufferedFile); ok && file.Buffered() {
+		size += int(s.body.Size())
+	}
+	return size
+}
+
+// Close closes the underlying file
+func (s *audioStream) Close() error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// readHead reads up to n bytes from the start of the stream and rewinds it
+func (s *audioStream) readHead(n int) ([]byte, error) {
+	head := make([]byte, min(int64(n), s.Size()))
+	read, err := io.ReadFull(s, head)
+	if _, seekErr := s.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, seekErr
+	}
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return head[:read], nil
+}
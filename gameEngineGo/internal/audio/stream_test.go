@@ -0,0 +1,130 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// testFile is an in-memory seekableFile, optionally reporting itself as
+// buffered like an unpacked GPK entry
+type testFile struct {
+	*bytes.Reader
+	buffered bool
+	closed   bool
+}
+
+func (f *testFile) Close() error               { f.closed = true; return nil }
+func (f *testFile) Stat() (fs.FileInfo, error) { return testFileInfo(f.Size()), nil }
+func (f *testFile) Buffered() bool             { return f.buffered }
+
+type testFileInfo int64
+
+func (i testFileInfo) Name() string       { return "test.ogg" }
+func (i testFileInfo) Size() int64        { return int64(i) }
+func (i testFileInfo) Mode() fs.FileMode  { return 0444 }
+func (i testFileInfo) ModTime() time.Time { return time.Time{} }
+func (i testFileInfo) IsDir() bool        { return false }
+func (i testFileInfo) Sys() interface{}   { return nil }
+
+// testOgg returns data with a valid Ogg header and a recognisable body
+func testOgg(size int) []byte {
+	data := append([]byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"), make([]byte, size-16)...)
+	for i := 16; i < size; i++ {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func TestFileStreamReadsOnDemand(t *testing.T) {
+	m := &Manager{}
+	data := testOgg(4096)
+	file := &testFile{Reader: bytes.NewReader(data)}
+
+	stream, err := m.newFileStream(file, "Voice00/test.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stream.Buffered(); got != oggHeaderProbeSize {
+		t.Errorf("streamed file buffers %d bytes, want only the %d-byte header", got, oggHeaderProbeSize)
+	}
+
+	if _, err := stream.Seek(1000, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 100)
+	if _, err := io.ReadFull(stream, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data[1000:1100]) {
+		t.Error("seek and read returned the wrong bytes")
+	}
+
+	stream.Close()
+	if !file.closed {
+		t.Error("closing the stream left the file open")
+	}
+}
+
+func TestStreamBuffered(t *testing.T) {
+	m := &Manager{}
+	data := testOgg(4096)
+
+	unpacked, err := m.newFileStream(&testFile{Reader: bytes.NewReader(data), buffered: true}, "Voice00/packed.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := unpacked.Buffered(); got != len(data) {
+		t.Errorf("unpacked entry buffers %d bytes, want %d", got, len(data))
+	}
+
+	if got := m.newMemoryStream(data).Buffered(); got != len(data) {
+		t.Errorf("memory stream buffers %d bytes, want %d", got, len(data))
+	}
+
+	// The data of a loaded file is counted once, by the file
+	shared, err := m.openLoadedStream(&AudioFile{Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := shared.Buffered(); got != 0 {
+		t.Errorf("stream over loaded data buffers %d bytes, want 0", got)
+	}
+}
+
+func TestMemoryUsageCountsStreams(t *testing.T) {
+	m := &Manager{}
+	data := testOgg(4096)
+	m.voiceStream = m.newMemoryStream(data)
+	m.bgm = &bgmTrack{stream: m.newMemoryStream(data)}
+
+	if got := m.GetMemoryUsage(); got != 2*len(data) {
+		t.Errorf("memory usage %d, want %d for two in-memory streams", got, 2*len(data))
+	}
+}
+
+// overrideFS serves explicit "package.gpk::entry" paths from one file
+type overrideFS struct {
+	FileSystemInterface
+	file *testFile
+}
+
+func (f overrideFS) OpenArchiveFile(archive, entry string) (io.ReadCloser, error) {
+	return f.file, nil
+}
+
+func TestGPKOverrideStreams(t *testing.T) {
+	data := testOgg(4096)
+	m := &Manager{filesystem: overrideFS{file: &testFile{Reader: bytes.NewReader(data)}}}
+
+	stream, err := m.openAudioStream("Voice00.GPK::Voice00/line.ogg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	if stream.file == nil || stream.Buffered() != oggHeaderProbeSize {
+		t.Errorf("override entry buffers %d bytes, want it streamed", stream.Buffered())
+	}
+}
@@ -26,6 +26,8 @@ type AudioFile struct {
 	Data      []byte // Cached audio data
 	IsFromGPK bool   // Whether this file is from a GPK package
 	GPKEntry  string // Entry name within GPK (if IsFromGPK is true)
+
+	Loop *LoopPoints // Loop section from the Ogg comments, nil loops the whole file
 }
 
 // AudioLoadConfig represents configuration for loading audio files
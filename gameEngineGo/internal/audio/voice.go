@@ -0,0 +1,171 @@
+package audio
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// PersonaVoice holds per-character voice settings (matches the original
+// game's character voice sliders)
+type PersonaVoice struct {
+	Volume float64 `json:"volume"` // 0.0 to 1.0, multiplied with the voice volume
+	Muted  bool    `json:"muted"`
+}
+
+// PlayVoice plays a voice line on the voice channel, stopping the previous line
+func (m *Manager) PlayVoice(filename, persona string) error {
+	m.StopVoice()
+
+	stream, err := m.openAudioStream(filename)
+	if err != nil {
+		return fmt.Errorf("failed to load voice: %w", err)
+	}
+
+	player, err := m.createPlayerFromStream(stream)
+	if err != nil {
+		stream.Close()
+		return fmt.Errorf("failed to create voice player: %w", err)
+	}
+
+	m.voicePlayer = player
+	m.voiceStream = stream
+	m.voiceFile = filename
+	m.voicePersona = normalizePersona(persona)
+	m.voicePlayer.SetVolume(m.effectiveVoiceVolume(m.voicePersona))
+	m.voicePlayer.Play()
+
+	log.Printf("Playing voice: %s (persona: %s)", filepath.Base(filename), m.voicePersona)
+	return nil
+}
+
+// StopVoice stops the current voice line without reporting it as finished
+func (m *Manager) StopVoice() {
+	if m.voicePlayer == nil {
+		return
+	}
+
+	m.voicePlayer.Close()
+	m.voicePlayer = nil
+	m.voiceStream.Close()
+	m.voiceStream = nil
+	m.voiceFile = ""
+	m.voicePersona = ""
+}
+
+// StopVoiceFile stops the voice channel only if it is still playing filename
+func (m *Manager) StopVoiceFile(filename string) {
+	if m.voicePlayer != nil && m.voiceFile == filename {
+		m.StopVoice()
+	}
+}
+
+// IsVoicePlaying returns true while a voice line is playing
+func (m *Manager) IsVoicePlaying() bool {
+	return m.voicePlayer != nil && m.voicePlayer.IsPlaying()
+}
+
+// GetCurrentVoiceFile returns the file on the voice channel, or ""
+func (m *Manager) GetCurrentVoiceFile() string {
+	return m.voiceFile
+}
+
+// SetVoiceFinishedHandler sets a callback run when a voice line plays to its end
+func (m *Manager) SetVoiceFinishedHandler(handler func(filename string)) {
+	m.voiceFinishedHandler = handler
+}
+
+// updateVoice releases a finished voice line and reports it
+func (m *Manager) updateVoice() {
+	if m.voicePlayer == nil || m.voicePlayer.IsPlaying() {
+		return
+	}
+
+	filename := m.voiceFile
+	m.StopVoice()
+
+	if m.voiceFinishedHandler != nil {
+		m.voiceFinishedHandler(filename)
+	}
+}
+
+// SetPersonaVolume sets the voice volume of a single character (0.0 to 1.0)
+func (m *Manager) SetPersonaVolume(persona string, volume float64) {
+	if volume < 0.0 {
+		volume = 0.0
+	} else if volume > 1.0 {
+		volume = 1.0
+	}
+
+	persona = normalizePersona(persona)
+	settings := m.personaVoices[persona]
+	settings.Volume = volume
+	m.personaVoices[persona] = settings
+	m.applyVoiceVolume()
+
+	log.Printf("Voice volume for %s set to: %.2f", persona, volume)
+}
+
+// SetPersonaMuted mutes or unmutes the voice of a single character
+func (m *Manager) SetPersonaMuted(persona string, muted bool) {
+	persona = normalizePersona(persona)
+	settings, exists := m.personaVoices[persona]
+	if !exists {
+		settings.Volume = 1.0
+	}
+	settings.Muted = muted
+	m.personaVoices[persona] = settings
+	m.applyVoiceVolume()
+}
+
+// GetPersonaVoice returns the settings of a character (full volume if unset)
+func (m *Manager) GetPersonaVoice(persona string) PersonaVoice {
+	if settings, exists := m.personaVoices[normalizePersona(persona)]; exists {
+		return settings
+	}
+	return PersonaVoice{Volume: 1.0}
+}
+
+// GetPersonaVoices returns a copy of all per-character voice settings
+func (m *Manager) GetPersonaVoices() map[string]PersonaVoice {
+	result := make(map[string]PersonaVoice, len(m.personaVoices))
+	for persona, settings := range m.personaVoices {
+		result[persona] = settings
+	}
+	return result
+}
+
+// LoadPersonaVoices replaces all per-character voice settings
+func (m *Manager) LoadPersonaVoices(voices map[string]PersonaVoice) {
+	m.personaVoices = make(map[string]PersonaVoice, len(voices))
+	for persona, settings := range voices {
+		m.personaVoices[normalizePersona(persona)] = settings
+	}
+	m.applyVoiceVolume()
+}
+
+// effectiveVoiceVolume returns the playback volume for a character
+func (m *Manager) effectiveVoiceVolume(persona string) float64 {
+	if m.muted {
+		return 0.0
+	}
+
+	settings := m.GetPersonaVoice(persona)
+	if settings.Muted {
+		return 0.0
+	}
+	return m.voiceVolume * settings.Volume
+}
+
+// applyVoiceVolume updates the volume of the playing voice line
+func (m *Manager) applyVoiceVolume() {
+	if m.voicePlayer != nil {
+		m.voicePlayer.SetVolume(m.effectiveVoiceVolume(m.voicePersona))
+	}
+}
+
+// normalizePersona makes persona keys case-insensitive ("MAK" and "mak" are the same character)
+func normalizePersona(persona string) string {
+	return strings.ToLower(strings.TrimSpace(persona))
+}
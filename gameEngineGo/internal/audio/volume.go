@@ -13,9 +13,7 @@ func (m *Manager) SetBGMVolume(volume float64) {
 	}
 
 	m.bgmVolume = volume
-	if m.bgmPlayer != nil && !m.muted {
-		m.bgmPlayer.SetVolume(volume)
-	}
+	m.applyBGMVolume()
 
 	log.Printf("BGM volume set to: %.2f", volume)
 }
@@ -36,6 +34,7 @@ func (m *Manager) SetSEVolume(volume float64) {
 			}
 		}
 	}
+	m.applySeChannelVolume()
 
 	log.Printf("SE volume set to: %.2f", volume)
 }
@@ -49,7 +48,7 @@ func (m *Manager) SetVoiceVolume(volume float64) {
 	}
 
 	m.voiceVolume = volume
-	// TODO: Apply to voice players when implemented
+	m.applyVoiceVolume()
 
 	log.Printf("Voice volume set to: %.2f", volume)
 }
@@ -60,25 +59,25 @@ func (m *Manager) SetMuted(muted bool) {
 
 	if muted {
 		// Mute all players
-		if m.bgmPlayer != nil {
-			m.bgmPlayer.SetVolume(0.0)
-		}
+		m.applyBGMVolume()
 		for i := range m.soundPlayers {
 			if m.soundPlayers[i] != nil {
 				m.soundPlayers[i].SetVolume(0.0)
 			}
 		}
+		m.applySeChannelVolume()
+		m.applyVoiceVolume()
 		log.Println("Audio muted")
 	} else {
 		// Restore volumes
-		if m.bgmPlayer != nil {
-			m.bgmPlayer.SetVolume(m.bgmVolume)
-		}
+		m.applyBGMVolume()
 		for i := range m.soundPlayers {
 			if m.soundPlayers[i] != nil {
 				m.soundPlayers[i].SetVolume(m.seVolume)
 			}
 		}
+		m.applySeChannelVolume()
+		m.applyVoiceVolume()
 		log.Println("Audio unmuted")
 	}
 }
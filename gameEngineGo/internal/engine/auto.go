@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"school-days-engine/internal/input"
+	"school-days-engine/internal/script"
+	"school-days-engine/internal/settings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Auto mode timing at the default speed
+const (
+	autoBaseDelay    = 1000 * time.Millisecond // Pause after every line
+	autoCharDelay    = 50 * time.Millisecond   // Extra pause per character of text
+	autoDefaultSpeed = 3                       // settings.Config.AutoSpeed of the delays above
+	autoMaxSpeed     = 5
+)
+
+// Typewriter reveal time per character, indexed by settings.Config.TextSpeed
+var textRevealDelays = [...]time.Duration{
+	40 * time.Millisecond, 30 * time.Millisecond, 20 * time.Millisecond,
+	15 * time.Millisecond, 10 * time.Millisecond, 0,
+}
+
+// textWait tracks the line the timeline is held at
+type textWait struct {
+	action   *script.Action
+	reveal   time.Duration // Typewriter time still running when the wait began
+	revealed bool
+	elapsed  time.Duration // Time waited since the text was fully shown and the voice finished
+}
+
+// newTextWait starts waiting at a line whose typewriter reveal still runs
+// for reveal
+func newTextWait(action *script.Action, reveal time.Duration) *textWait {
+	return &textWait{action: action, reveal: reveal, revealed: reveal <= 0}
+}
+
+// advanceReveal runs the typewriter for one tick
+func (w *textWait) advanceReveal(tick time.Duration) {
+	if !w.revealed {
+		w.reveal -= tick
+		w.revealed = w.reveal <= 0
+	}
+}
+
+// autoAdvance adds one tick to the wait and reports whether auto mode moves
+// on: the text is fully shown, the line's voice is no longer playing and
+// delay has passed since
+func (w *textWait) autoAdvance(voicePlaying bool, tick, delay time.Duration) bool {
+	if !w.revealed || voicePlaying {
+		w.elapsed = 0
+		return false
+	}
+	w.elapsed += tick
+	return w.elapsed >= delay
+}
+
+// SetAutoMode turns auto-advance on or off and saves it
+func (g *Game) SetAutoMode(enabled bool) {
+	err := g.settings.Update(func(config *settings.Config) {
+		config.AutoMode = enabled
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save auto mode: %v", err)
+	}
+	log.Printf("Auto mode: %v", enabled)
+}
+
+// ToggleAutoMode switches auto-advance on or off
+func (g *Game) ToggleAutoMode() {
+	g.SetAutoMode(!g.IsAutoMode())
+}
+
+// IsAutoMode returns whether lines advance by themselves
+func (g *Game) IsAutoMode() bool {
+	return g.settings.GetConfig().AutoMode
+}
+
+// waitsAtText is the script wait filter: text holds the timeline at its end
+// for the player, except while skipping
+func (g *Game) waitsAtText(action *script.Action) bool {
+	return action.Action == script.ActionPrintText && !g.IsSkipping()
+}
+
+// updateTextWait advances a held line on the advance action, or by itself
+// in auto mode once the text is shown, the voice finished and the auto delay
+// passed (called after the script update). While the typewriter still runs,
+// the advance action shows the whole line first.
+func (g *Game) updateTextWait() {
+	if g.input.IsActionJustPressed(input.ActionAuto) {
+		g.ToggleAutoMode()
+	}
+
+	action := g.script.GetWaitingAction()
+	if action == nil {
+		g.wait = nil
+		return
+	}
+	if g.wait == nil || g.wait.action != action {
+		g.wait = newTextWait(action, revealTime(action, g.settings.GetConfig().TextSpeed))
+	}
+
+	// Skipping starts from the held line
+	if g.IsSkipping() {
+		g.advanceText()
+		return
+	}
+
+	tick := time.Second / time.Duration(max(1, ebiten.TPS()))
+	g.wait.advanceReveal(tick)
+
+	if g.input.IsActionJustPressed(input.ActionAdvance) {
+		if !g.wait.revealed {
+			g.wait.revealed = true // First press shows the whole line
+			return
+		}
+		g.advanceText()
+		return
+	}
+
+	// Voices finish on the audio clock, so a replay takes auto mode's
+	// decisions from the recording
+	if g.input.IsPlayingBack() {
+		if g.input.IsAutoAdvanceReplayed() {
+			g.advanceText()
+		}
+		return
+	}
+
+	// Auto mode never answers a choice for the player
+	if !g.IsAutoMode() || g.isChoicePending() {
+		return
+	}
+
+	if g.wait.autoAdvance(g.voiceLine != "", tick, autoDelay(action.Text, g.settings.GetConfig().AutoSpeed)) {
+		g.input.MarkAutoAdvance()
+		g.advanceText()
+	}
+}
+
+// advanceText releases the timeline from the held line
+func (g *Game) advanceText() {
+	g.wait = nil
+	g.script.Resume()
+}
+
+// voiceStarted records the voice line auto mode waits for
+func (g *Game) voiceStarted(filename string) {
+	g.voiceLine = filename
+}
+
+// voiceStopped clears the voice line auto mode waits for once it played to
+// its end (the audio manager's finished handler) or its action stopped it
+func (g *Game) voiceStopped(filename string) {
+	if g.voiceLine == filename {
+		g.voiceLine = ""
+	}
+}
+
+// autoDelay returns how long auto mode waits after a line, scaled by its
+// length and the auto speed setting
+func autoDelay(text string, speed int) time.Duration {
+	if speed < 1 || speed > autoMaxSpeed {
+		speed = autoDefaultSpeed
+	}
+
+	delay := autoBaseDelay + time.Duration(utf8.RuneCountInString(text))*autoCharDelay
+	return delay * autoDefaultSpeed / time.Duration(speed)
+}
+
+// revealTime returns how much of a line's typewriter reveal is still running
+// at its end time (long text on a short action), at a text speed
+func revealTime(action *script.Action, speed int) time.Duration {
+	if speed < 0 || speed >= len(textRevealDelays) {
+		speed = len(textRevealDelays) - 1
+	}
+
+	reveal := time.Duration(utf8.RuneCountInString(action.Text)) * textRevealDelays[speed]
+	shown := time.Duration(action.Duration()) * time.Millisecond
+	return max(0, reveal-shown)
+}
+
+// isChoicePending returns whether a SetSELECT choice is on screen
+func (g *Game) isChoicePending() bool {
+	for _, action := range g.script.RunningActions() {
+		if action.Action == script.ActionSetSelect {
+			return true
+		}
+	}
+	return false
+}
+
+// drawModeIndicator shows AUTO or SKIP in the corner of the window
+func (g *Game) drawModeIndicator(screen *ebiten.Image) {
+	label := ""
+	switch {
+	case g.IsSkipping():
+		label = "SKIP"
+	case g.IsAutoMode():
+		label = "AUTO"
+	default:
+		return
+	}
+	if g.script.IsWaiting() {
+		label += " >"
+	}
+	ebitenutil.DebugPrintAt(screen, label, screen.Bounds().Dx()-60, 0)
+}
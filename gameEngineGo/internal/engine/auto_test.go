@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"school-days-engine/internal/input"
+	"school-days-engine/internal/script"
+	"school-days-engine/internal/settings"
+)
+
+func TestTextWaitAutoAdvance(t *testing.T) {
+	const tick = time.Second / 60
+	wait := newTextWait(&script.Action{Action: script.ActionPrintText}, 0)
+
+	// The delay only counts once the voice finished
+	for i := 0; i < 120; i++ {
+		if wait.autoAdvance(true, tick, 10*tick) {
+			t.Fatal("advanced while the voice was playing")
+		}
+	}
+	for i := 1; i < 10; i++ {
+		if wait.autoAdvance(false, tick, 10*tick) {
+			t.Fatalf("advanced after %d ticks, want 10", i)
+		}
+	}
+	if !wait.autoAdvance(false, tick, 10*tick) {
+		t.Fatal("did not advance after the delay")
+	}
+}
+
+func TestTextWaitVoiceRestartsDelay(t *testing.T) {
+	const tick = time.Second / 60
+	wait := newTextWait(&script.Action{Action: script.ActionPrintText}, 0)
+
+	for i := 0; i < 9; i++ {
+		wait.autoAdvance(false, tick, 10*tick)
+	}
+	wait.autoAdvance(true, tick, 10*tick)
+	if wait.autoAdvance(false, tick, 10*tick) {
+		t.Fatal("a voice starting during the delay did not restart it")
+	}
+}
+
+func TestTextWaitWaitsForReveal(t *testing.T) {
+	const tick = time.Second / 60
+	wait := newTextWait(&script.Action{Action: script.ActionPrintText}, 20*tick)
+
+	// The reveal ends on tick 20, which counts as the first of the delay
+	ticks := 0
+	for advanced := false; !advanced; {
+		ticks++
+		if ticks > 100 {
+			t.Fatal("never advanced")
+		}
+		wait.advanceReveal(tick)
+		advanced = wait.autoAdvance(false, tick, 5*tick)
+	}
+	if ticks != 24 {
+		t.Errorf("advanced after %d ticks, want 24", ticks)
+	}
+}
+
+func TestRevealTime(t *testing.T) {
+	line := func(text string, duration int64) *script.Action {
+		return &script.Action{Action: script.ActionPrintText, Text: text, Start: 0, End: duration}
+	}
+	tests := []struct {
+		action *script.Action
+		speed  int
+		want   time.Duration
+	}{
+		{line("Hello", 500), 0, 0}, // Shown before the line ends
+		{line(strings.Repeat("a", 100), 1000), 0, 3000 * time.Millisecond}, // 4 s of text on a 1 s line
+		{line(strings.Repeat("я", 100), 1000), 2, 1000 * time.Millisecond}, // Runes, not bytes
+		{line(strings.Repeat("a", 100), 1000), 5, 0},                       // Instant
+		{line(strings.Repeat("a", 100), 1000), 99, 0},                      // Out of range is instant
+		{line(strings.Repeat("a", 100), -1), 4, 1000 * time.Millisecond},   // No end: all of it
+	}
+	for _, test := range tests {
+		if got := revealTime(test.action, test.speed); got != test.want {
+			t.Errorf("revealTime(%d runes in %d ms, %d) = %v, want %v",
+				len([]rune(test.action.Text)), test.action.Duration(), test.speed, got, test.want)
+		}
+	}
+}
+
+// longLineScript holds at a 100-character line ending at 100 ms
+var longLineScript = `[
+{"action":"PrintText","start":0,"end":100,"text":"` + strings.Repeat("a", 100) + `"},
+{"action":"Next","start":3000}
+]`
+
+// newTextSpeedTestGame creates a timeline test game at a text speed that
+// replays the advance action at ticks
+func newTextSpeedTestGame(t *testing.T, speed int, ticks ...int64) *Game {
+	t.Helper()
+	g := newTimelineTestGame(t, longLineScript)
+	if err := g.settings.Update(func(config *settings.Config) { config.TextSpeed = speed }); err != nil {
+		t.Fatal(err)
+	}
+
+	var frames []input.Frame
+	for tick := int64(1); tick <= 90; tick++ {
+		frame := input.Frame{Tick: tick}
+		if slices.Contains(ticks, tick) {
+			frame.Actions = []input.Action{input.ActionAdvance}
+		}
+		frames = append(frames, frame)
+	}
+	if _, err := g.input.StartPlayback(writeRecording(t, frames)); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestAdvanceRevealsLineFirst(t *testing.T) {
+	// A 4 s reveal at the slowest text speed
+	g := newTextSpeedTestGame(t, 0, 30, 60)
+	for tick := 1; tick <= 90; tick++ {
+		g.testTick(t)
+		waiting := g.script.IsWaiting()
+		switch {
+		case tick == 30 && (!waiting || !g.wait.revealed):
+			t.Fatal("first press did not just show the whole line")
+		case tick == 60 && waiting:
+			t.Fatal("second press did not advance")
+		}
+	}
+}
+
+func TestInstantTextAdvancesOnFirstPress(t *testing.T) {
+	g := newTextSpeedTestGame(t, 5, 30)
+	for tick := 1; tick <= 30; tick++ {
+		g.testTick(t)
+	}
+	if g.script.IsWaiting() {
+		t.Error("instant text still held after one press")
+	}
+}
+
+func TestAutoDelay(t *testing.T) {
+	tests := []struct {
+		text  string
+		speed int
+		want  time.Duration
+	}{
+		{"", autoDefaultSpeed, autoBaseDelay},
+		{"Hello", autoDefaultSpeed, autoBaseDelay + 5*autoCharDelay},
+		{"Привет", autoDefaultSpeed, autoBaseDelay + 6*autoCharDelay}, // Runes, not bytes
+		{"", 1, 3 * autoBaseDelay},
+		{"", 0, autoBaseDelay},  // Out of range uses the default
+		{"", 99, autoBaseDelay}, // Out of range uses the default
+	}
+	for _, test := range tests {
+		if got := autoDelay(test.text, test.speed); got != test.want {
+			t.Errorf("autoDelay(%q, %d) = %v, want %v", test.text, test.speed, got, test.want)
+		}
+	}
+}
+
+func TestVoiceStoppedMatchesFile(t *testing.T) {
+	g := &Game{}
+	g.voiceStarted("Voice00/a.ogg")
+	g.voiceStarted("Voice00/b.ogg")
+
+	g.voiceStopped("Voice00/a.ogg") // The replaced line finishing late
+	if g.voiceLine != "Voice00/b.ogg" {
+		t.Fatalf("voice line cleared by another file: %q", g.voiceLine)
+	}
+	g.voiceStopped("Voice00/b.ogg")
+	if g.voiceLine != "" {
+		t.Fatalf("voice line %q not cleared", g.voiceLine)
+	}
+}
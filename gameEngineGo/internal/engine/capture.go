@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+)
+
+// captureRange is a script time range to dump as a numbered PNG sequence
+type captureRange struct {
+	from, to int64 // Timeline positions in ms
+	started  bool
+}
+
+// CaptureTimeRange dumps every frame drawn while the active script's
+// timeline is between from and to (in ms) to a numbered PNG sequence
+func (g *Game) CaptureTimeRange(from, to int64) error {
+	if to <= from {
+		return fmt.Errorf("invalid capture range %d-%d ms", from, to)
+	}
+
+	g.burstRange = &captureRange{from: from, to: to}
+	log.Printf("Frame capture armed for %d-%d ms", from, to)
+	return nil
+}
+
+// updateBurstCapture starts and stops the burst as the timeline enters and leaves the range
+func (g *Game) updateBurstCapture() {
+	if g.burstRange == nil {
+		return
+	}
+
+	capture := g.graphics.GetFrameCapture()
+	position := g.script.GetPosition()
+	inRange := g.script.GetActiveScript() != nil && position >= g.burstRange.from && position <= g.burstRange.to
+
+	switch {
+	case inRange && !g.burstRange.started:
+		name := fmt.Sprintf("burst_%d-%d", g.burstRange.from, g.burstRange.to)
+		if scene := g.script.GetActiveScript(); scene != nil {
+			name = fmt.Sprintf("%s_%d-%d", scene.BaseName(), g.burstRange.from, g.burstRange.to)
+		}
+		if err := capture.StartBurst(name); err != nil {
+			log.Printf("Warning: %v", err)
+			g.burstRange = nil
+			return
+		}
+		g.burstRange.started = true
+
+	case !inRange && g.burstRange.started:
+		capture.StopBurst()
+		g.burstRange = nil
+	}
+}
@@ -0,0 +1,274 @@
+package engine
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"school-days-engine/internal/input"
+	"school-days-engine/internal/menu"
+)
+
+// consoleMaxOutput is how many output lines the console keeps
+const consoleMaxOutput = 12
+
+// console is the developer console for content debugging. While it is open
+// it has the keyboard and the game receives no input.
+type console struct {
+	open   bool
+	line   string
+	output []string
+}
+
+// consoleCommands describes the console commands for "help"
+var consoleCommands = []string{
+	"jump <ms>              seek the active script",
+	"load <jrs> [ms]        start a script, leaving the menus",
+	"flag set <name> <n>    set a game flag",
+	"flag get <name>        show a game flag",
+	"flag list              show the game flags",
+	"set <key> <value>      set a setting for this session",
+	"set                    show the session settings",
+	"state <n>              change the menu state",
+	"reload                 reload the active script and textures",
+	"capture <from> <to>    dump the frames of a timeline range in ms to PNGs",
+}
+
+// openConsole gives the keyboard to the console
+func (g *Game) openConsole() {
+	g.console.open = true
+	g.console.line = ""
+	g.input.SetSuspended(true)
+}
+
+// closeConsole gives the keyboard back to the game once the keys held now,
+// such as the Escape that closed it, are released
+func (g *Game) closeConsole() {
+	g.console.open = false
+	g.input.SetSuspended(false)
+}
+
+// updateConsole edits and runs the command line
+func (g *Game) updateConsole() {
+	switch {
+	case g.input.IsActionJustPressed(input.ActionConsoleCancel), g.input.IsActionJustPressed(input.ActionConsole):
+		g.closeConsole()
+		return
+	case g.input.IsActionJustPressed(input.ActionConsoleSubmit):
+		line := strings.TrimSpace(g.console.line)
+		g.console.line = ""
+		if line != "" {
+			g.consolePrint("> " + line)
+			g.runConsoleCommand(line)
+		}
+		return
+	case g.input.IsActionJustPressed(input.ActionConsoleErase):
+		if runes := []rune(g.console.line); len(runes) > 0 {
+			g.console.line = string(runes[:len(runes)-1])
+		}
+	}
+
+	for _, char := range g.input.InputChars() {
+		if char != '`' {
+			g.console.line += string(char)
+		}
+	}
+}
+
+// runConsoleCommand runs one console command line
+func (g *Game) runConsoleCommand(line string) {
+	args := strings.Fields(line)
+	var err error
+
+	switch strings.ToLower(args[0]) {
+	case "help":
+		for _, help := range consoleCommands {
+			g.consolePrint(help)
+		}
+	case "jump":
+		err = g.consoleJump(args[1:])
+	case "load":
+		err = g.consoleLoad(args[1:])
+	case "flag":
+		err = g.consoleFlag(args[1:])
+	case "set":
+		err = g.consoleSet(args[1:])
+	case "state":
+		err = g.consoleState(args[1:])
+	case "capture":
+		err = g.consoleCapture(args[1:])
+	case "reload":
+		g.graphics.ReloadTextures()
+		if err = g.ReloadScene(); err == nil {
+			g.consolePrint("Reloaded")
+		}
+	default:
+		err = fmt.Errorf("unknown command %q (try help)", args[0])
+	}
+
+	if err != nil {
+		g.consolePrint("Error: " + err.Error())
+	}
+}
+
+// consoleJump seeks the active script: jump <ms>
+func (g *Game) consoleJump(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: jump <ms>")
+	}
+	position, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid position %q", args[0])
+	}
+	if g.script.GetActiveScript() == nil {
+		return fmt.Errorf("no script is loaded")
+	}
+
+	g.seekScene(position)
+	g.consolePrint(fmt.Sprintf("Jumped to %d ms", g.script.GetPosition()))
+	return nil
+}
+
+// consoleLoad starts a script: load <jrs> [ms]
+func (g *Game) consoleLoad(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: load <jrs> [ms]")
+	}
+
+	var position int64
+	if len(args) == 2 {
+		var err error
+		if position, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return fmt.Errorf("invalid position %q", args[1])
+		}
+	}
+
+	g.startScene, g.startAt = args[0], position
+	if err := g.startSceneAt(); err != nil {
+		return err
+	}
+	g.consolePrint(fmt.Sprintf("Loaded %s at %d ms", args[0], position))
+	return nil
+}
+
+// consoleFlag sets or shows game flags: flag set <name> <n>, flag get
+// <name>, flag list
+func (g *Game) consoleFlag(args []string) error {
+	flags := g.script.Flags()
+
+	switch {
+	case len(args) == 3 && strings.EqualFold(args[0], "set"):
+		value, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid flag value %q", args[2])
+		}
+		flags.Set(args[1], value)
+		g.consolePrint(fmt.Sprintf("%s = %d", args[1], value))
+	case len(args) == 2 && strings.EqualFold(args[0], "get"):
+		value, ok := flags.Get(args[1])
+		if !ok {
+			g.consolePrint(fmt.Sprintf("%s is not set (0)", args[1]))
+			return nil
+		}
+		g.consolePrint(fmt.Sprintf("%s = %d", args[1], value))
+	case len(args) == 1 && strings.EqualFold(args[0], "list"):
+		names := flags.Names()
+		if len(names) == 0 {
+			g.consolePrint("No flags set")
+		}
+		for _, name := range names {
+			value, _ := flags.Get(name)
+			g.consolePrint(fmt.Sprintf("%s = %d", name, value))
+		}
+	default:
+		return fmt.Errorf("usage: flag set <name> <n> | flag get <name> | flag list")
+	}
+	return nil
+}
+
+// consoleSet overrides a setting for this session, or lists the session
+// settings: set <key> <value>, set
+func (g *Game) consoleSet(args []string) error {
+	switch {
+	case len(args) >= 2:
+		value := strings.Join(args[1:], " ")
+		if err := g.settings.SetOverride(args[0], value); err != nil {
+			return err
+		}
+		g.consolePrint(fmt.Sprintf("%s = %s", args[0], value))
+	case len(args) == 0:
+		overrides := g.settings.GetOverrides()
+		if len(overrides) == 0 {
+			g.consolePrint("No session settings")
+		}
+		for _, override := range overrides {
+			g.consolePrint(override)
+		}
+	default:
+		return fmt.Errorf("usage: set <key> <value> | set")
+	}
+	return nil
+}
+
+// consoleState changes the menu state: state <n>
+func (g *Game) consoleState(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: state <n>")
+	}
+	state, err := strconv.Atoi(args[0])
+	if err != nil || !menu.IsMenuState(state) {
+		return fmt.Errorf("invalid state %q", args[0])
+	}
+
+	g.menu.SetState(state)
+	g.consolePrint(fmt.Sprintf("Menu state %d", g.menu.GetState()))
+	return nil
+}
+
+// consoleCapture arms a burst capture of a timeline range: capture <from> <to>
+func (g *Game) consoleCapture(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: capture <from> <to>")
+	}
+	from, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid start %q", args[0])
+	}
+	to, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid end %q", args[1])
+	}
+
+	if err := g.CaptureTimeRange(from, to); err != nil {
+		return err
+	}
+	g.consolePrint(fmt.Sprintf("Capturing %d-%d ms", from, to))
+	return nil
+}
+
+// consolePrint adds a line to the console output and the log
+func (g *Game) consolePrint(line string) {
+	log.Printf("Console: %s", line)
+	g.console.output = append(g.console.output, line)
+	if extra := len(g.console.output) - consoleMaxOutput; extra > 0 {
+		g.console.output = g.console.output[extra:]
+	}
+}
+
+// drawConsole draws the console output and command line at the top of the window
+func (g *Game) drawConsole(screen *ebiten.Image) {
+	height := (len(g.console.output) + 1) * debugLineHeight
+	area := image.Rect(0, 0, screen.Bounds().Dx(), height+4)
+	screen.SubImage(area).(*ebiten.Image).Fill(color.RGBA{0, 0, 0, 255})
+
+	for i, line := range g.console.output {
+		ebitenutil.DebugPrintAt(screen, line, 4, i*debugLineHeight)
+	}
+	ebitenutil.DebugPrintAt(screen, "] "+g.console.line+"_", 4, len(g.console.output)*debugLineHeight)
+}
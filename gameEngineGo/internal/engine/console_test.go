@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConsoleCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		want     string // Start of the last output line
+	}{
+		{"unknown command", []string{"teleport"}, `Error: unknown command "teleport" (try help)`},
+		{"jump usage", []string{"jump"}, "Error: usage: jump <ms>"},
+		{"jump invalid position", []string{"jump soon"}, `Error: invalid position "soon"`},
+		{"jump", []string{"jump 1000"}, "Jumped to 1000 ms"},
+		{"load usage", []string{"load a b c"}, "Error: usage: load <jrs> [ms]"},
+		{"load invalid position", []string{"load 00-00-A00.jrs later"}, `Error: invalid position "later"`},
+		{"flag usage", []string{"flag set kotonoha"}, "Error: usage: flag set <name> <n> | flag get <name> | flag list"},
+		{"flag invalid value", []string{"flag set kotonoha yes"}, `Error: invalid flag value "yes"`},
+		{"flag set", []string{"flag set Kotonoha 2"}, "Kotonoha = 2"},
+		{"flag get", []string{"flag set Kotonoha 2", "flag get kotonoha"}, "kotonoha = 2"},
+		{"flag get unset", []string{"flag get sekai"}, "sekai is not set (0)"},
+		{"flag list empty", []string{"flag list"}, "No flags set"},
+		{"flag list", []string{"flag set sekai 1", "flag set kotonoha 3", "flag list"}, "sekai = 1"},
+		{"set", []string{"set bgm_volume 0.5"}, "bgm_volume = 0.5"},
+		{"set invalid value", []string{"set bgm_volume 2"}, "Error: "},
+		{"set list empty", []string{"set"}, "No session settings"},
+		{"set list", []string{"set text_speed 3", "set"}, "text_speed=3"},
+		{"set usage", []string{"set bgm_volume"}, "Error: usage: set <key> <value> | set"},
+		{"state usage", []string{"state"}, "Error: usage: state <n>"},
+		{"state not a number", []string{"state title"}, `Error: invalid state "title"`},
+		{"state undefined", []string{"state 99"}, `Error: invalid state "99"`},
+		{"capture usage", []string{"capture 100"}, "Error: usage: capture <from> <to>"},
+		{"capture invalid end", []string{"capture 100 end"}, `Error: invalid end "end"`},
+		{"capture empty range", []string{"capture 500 100"}, "Error: invalid capture range 500-100 ms"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newTimelineTestGame(t, replayTestScript)
+			for _, command := range test.commands {
+				g.runConsoleCommand(command)
+			}
+
+			output := g.console.output
+			got := output[len(output)-1]
+			if !strings.HasPrefix(got, test.want) {
+				t.Errorf("output %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConsoleFlagDoesNotOverrideSettings(t *testing.T) {
+	g := newTimelineTestGame(t, replayTestScript)
+	g.runConsoleCommand("flag set bgm_volume 0")
+
+	if overrides := g.settings.GetOverrides(); len(overrides) != 0 {
+		t.Errorf("flag changed the session settings: %v", overrides)
+	}
+	if value, ok := g.script.Flags().Get("bgm_volume"); !ok || value != 0 {
+		t.Errorf("flag = %d, %v", value, ok)
+	}
+}
+
+func TestConsoleSuspendsInput(t *testing.T) {
+	g := newTimelineTestGame(t, replayTestScript)
+
+	g.openConsole()
+	if !g.input.IsSuspended() {
+		t.Error("console open with the game still reading input")
+	}
+	g.testTick(t)
+	g.closeConsole()
+	if g.console.open || g.input.IsSuspended() {
+		t.Errorf("console closed: open %v, input suspended %v", g.console.open, g.input.IsSuspended())
+	}
+}
@@ -0,0 +1,18 @@
+package engine
+
+import (
+	"log"
+
+	"school-days-engine/internal/settings"
+)
+
+// saveInputBindings copies the changed input bindings into the config file
+func (g *Game) saveInputBindings() {
+	overrides := g.input.GetBindingOverrides()
+	err := g.settings.Update(func(config *settings.Config) {
+		config.InputBindings = overrides
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save input bindings: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"school-days-engine/internal/audio"
+	"school-days-engine/internal/graphics"
+	"school-days-engine/internal/input"
+	"school-days-engine/internal/menu"
+	"school-days-engine/internal/script"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// debugLineHeight is the height of a line of debug text
+const debugLineHeight = 16
+
+// maxDebugRunning is how many running actions the overlay lists
+const maxDebugRunning = 8
+
+// SetDebugOverlay shows the engine state and the menu regions on screen
+func (g *Game) SetDebugOverlay(enabled bool) {
+	g.debugOverlay = enabled
+	if g.menu != nil {
+		g.menu.SetDebugMode(enabled)
+	}
+}
+
+// ToggleDebugOverlay shows or hides the debug overlay
+func (g *Game) ToggleDebugOverlay() {
+	g.SetDebugOverlay(!g.debugOverlay)
+}
+
+// updateDebugKeys handles the overlay and console keys (called after input.Update)
+func (g *Game) updateDebugKeys() {
+	if g.console.open {
+		g.updateConsole()
+		return
+	}
+
+	if g.input.IsActionJustPressed(input.ActionDebugOverlay) {
+		g.ToggleDebugOverlay()
+	}
+	if g.input.IsActionJustPressed(input.ActionConsole) {
+		g.openConsole()
+	}
+}
+
+// drawDebugOverlay prints the menu, script, layer, texture and audio state
+// at the bottom left of the window
+func (g *Game) drawDebugOverlay(screen *ebiten.Image) {
+	lines := g.debugLines()
+	y := screen.Bounds().Dy() - len(lines)*debugLineHeight
+	for _, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, 0, y)
+		y += debugLineHeight
+	}
+}
+
+// debugLines describes the engine state for the overlay
+func (g *Game) debugLines() []string {
+	state := g.menu.GetState()
+	lines := []string{fmt.Sprintf("Menu: %s (%d)  TPS: %.1f", menu.GetStateInfo(state).Name, state, ebiten.ActualTPS())}
+
+	// Script timeline
+	if active := g.script.GetActiveScript(); active != nil {
+		status := ""
+		if g.script.IsPaused() {
+			status = " [paused]"
+		}
+		if g.script.IsWaiting() {
+			status = " [waiting]"
+		}
+		lines = append(lines, fmt.Sprintf("Script: %s  %d ms%s", active.Name, g.script.GetPosition(), status))
+
+		running := g.script.RunningActions()
+		for i, action := range running {
+			if i == maxDebugRunning {
+				lines = append(lines, fmt.Sprintf("  ... %d more", len(running)-i))
+				break
+			}
+			lines = append(lines, "  "+describeAction(action))
+		}
+	} else {
+		lines = append(lines, "Script: none")
+	}
+
+	// Layers with a texture
+	var layers []string
+	for layer := 0; layer < graphics.LayersCount; layer++ {
+		file, layerState := g.graphics.GetLayerInfo(layer)
+		if file == "" {
+			continue
+		}
+		entry := fmt.Sprintf("%d:%s", layer, filepath.Base(file))
+		if !layerState.Visible {
+			entry += "(hidden)"
+		} else if layerState.Alpha < 1.0 {
+			entry += fmt.Sprintf("(%.2f)", layerState.Alpha)
+		}
+		layers = append(layers, entry)
+	}
+	lines = append(lines, "Layers: "+strings.Join(layers, " "))
+	lines = append(lines, fmt.Sprintf("Textures cached: %d", g.graphics.GetTextureManager().GetCacheSize()))
+
+	// Audio channels
+	bgm := g.audio.GetCurrentBGMFile()
+	if bgm == "" || !g.audio.IsBGMPlaying() {
+		bgm = "-"
+	}
+	voice := "-"
+	if g.audio.IsVoicePlaying() {
+		voice = filepath.Base(g.audio.GetCurrentVoiceFile())
+	}
+	lines = append(lines, fmt.Sprintf("BGM: %s  Voice: %s", bgm, voice))
+
+	var channels []string
+	for channel := 0; channel < audio.SeChannels; channel++ {
+		if g.audio.IsSeChannelPlaying(channel) {
+			channels = append(channels, fmt.Sprintf("%d:%s", channel, filepath.Base(g.audio.GetSeChannelFile(channel))))
+		}
+	}
+	lines = append(lines, "SE: "+strings.Join(channels, " "))
+
+	return lines
+}
+
+// describeAction formats a script action for the overlay and console
+func describeAction(action *script.Action) string {
+	text := fmt.Sprintf("%s %d-%d", action.Action, action.Start, action.End)
+	if action.File != "" {
+		text += " " + action.File
+	}
+	if action.Layer >= 0 {
+		text += fmt.Sprintf(" L%d", action.Layer)
+	}
+	return text
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"image/color"
 	"log"
+	"path/filepath"
+	"time"
 
 	"school-days-engine/internal/audio"
 	"school-days-engine/internal/filesystem"
 	"school-days-engine/internal/graphics"
 	"school-days-engine/internal/input"
+	"school-days-engine/internal/locale"
 	"school-days-engine/internal/menu"
 	"school-days-engine/internal/script"
 	"school-days-engine/internal/settings"
@@ -24,12 +27,64 @@ type Game struct {
 	input      *input.Manager
 	filesystem *filesystem.Manager
 	script     *script.Engine
+	scene      *sceneHandler // Timeline handler of script
 	menu       *menu.Manager
 	settings   *settings.Manager
+	preloader  *Preloader
+	readLog    *script.ReadLog
+	localizer  *locale.Localizer
 
 	screenWidth  int
 	screenHeight int
 
+	// Window scaling: the game renders at screenWidth x screenHeight into
+	// offscreen, which the viewport fits into the window
+	viewport  *graphics.Viewport
+	offscreen *ebiten.Image
+
+	// Script time range dumped frame by frame for visual comparisons
+	burstRange *captureRange
+
+	// Input recording and replay files
+	recordPath string
+	replayPath string
+
+	// Command-line setting overrides, applied by Init
+	overrides []string
+
+	// Launch options set before Run
+	rootDir      string
+	configPath   string
+	startScene   string
+	startAt      int64
+	headless     bool
+	debugOverlay bool
+
+	// Developer console
+	console console
+
+	// Loose file watcher for hot reload
+	hotReload bool
+	watcher   *filesystem.Watcher
+
+	// Skip mode: latched from the menu, or held with the skip action.
+	// skipBlocked keeps a held key from skipping past a stop.
+	skipLatched bool
+	skipHeld    bool
+	skipBlocked bool
+
+	// When the read log was last written
+	readLogFlushed time.Time
+
+	// Line the timeline is held at, waiting for the player or auto mode,
+	// and the voice file auto mode waits for ("" when none is playing)
+	wait      *textWait
+	voiceLine string
+
+	// Characters heard this session and the one selected in the sound menu
+	spokenPersonas  map[string]bool
+	selectedPersona string
+
 	initialized bool
 }
 
@@ -38,6 +93,8 @@ func NewGame() *Game {
 	return &Game{
 		screenWidth:  800,
 		screenHeight: 600,
+		rootDir:      defaultRootDir,
+		configPath:   defaultConfigPath,
 	}
 }
 
@@ -45,48 +102,103 @@ func NewGame() *Game {
 func (g *Game) Init() error {
 	var err error
 
-	// Initialize settings
-	g.settings = settings.NewManager("./config/settings.json")
+	// Initialize filesystem
+	g.filesystem = filesystem.NewManager(g.rootDir)
+	if err = g.filesystem.Init(); err != nil {
+		return fmt.Errorf("failed to initialize filesystem: %w", err)
+	}
+	// Saved INI files go next to the settings file and replace the game's
+	g.filesystem.SetOverrideDir(filepath.Dir(g.configPath))
+
+	// Initialize settings: defaults, game INI, user JSON, command line
+	g.settings = settings.NewManager(g.configPath)
+	if err = g.settings.LoadGameINI(g.filesystem); err != nil {
+		log.Printf("Warning: failed to load game INI settings: %v", err)
+	}
 	if err = g.settings.Load(); err != nil {
 		log.Printf("Warning: failed to load settings: %v", err)
 	}
+	g.applyOverrides()
 
 	// Use settings for screen size
 	config := g.settings.GetConfig()
+
+	// Script and menu language
+	g.localizer = locale.NewLocalizer(g.filesystem, config.Language, config.FallbackLang)
 	g.screenWidth = config.ScreenWidth
 	g.screenHeight = config.ScreenHeight
+	g.viewport = graphics.NewViewport(g.screenWidth, g.screenHeight, graphics.ParseScaleMode(config.ScalingMode))
+	g.offscreen = ebiten.NewImage(g.screenWidth, g.screenHeight)
 
-	// Initialize filesystem
-	g.filesystem = filesystem.NewManager("./")
-	if err = g.filesystem.Init(); err != nil {
-		return fmt.Errorf("failed to initialize filesystem: %w", err)
-	}
 	// Initialize graphics renderer
 	g.graphics = graphics.NewRenderer(g.screenWidth, g.screenHeight, g.filesystem)
+	g.graphics.SetHeadless(g.headless)
 	if err = g.graphics.Init(); err != nil {
 		return fmt.Errorf("failed to initialize graphics: %w", err)
 	}
 
 	// Initialize audio manager
-	g.audio = audio.NewManager()
+	g.audio = audio.NewManager(g.filesystem)
 	if err = g.audio.Init(); err != nil {
 		return fmt.Errorf("failed to initialize audio: %w", err)
 	}
+	g.audio.SetVoiceFinishedHandler(g.voiceStopped)
 
 	// Initialize input manager
 	g.input = input.NewManager()
+	g.input.SetCoordinateMapper(g.viewport)
+
+	// Apply settings now and whenever they change
+	g.settings.Subscribe(g.applySettings)
 
 	// Initialize script engine
 	g.script = script.NewEngine()
 	if err = g.script.Init(); err != nil {
 		return fmt.Errorf("failed to initialize script engine: %w", err)
 	}
+	g.scene = newSceneHandler(g)
+	g.script.SetHandler(g.scene)
+	g.script.SetWaitFilter(g.waitsAtText)
+
+	// Load the dialogue lines seen in earlier sessions
+	g.readLog = script.NewReadLog(filepath.Join(filepath.Dir(g.configPath), readLogFile))
+	if err = g.readLog.Load(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Record or replay input on a fixed-step clock
+	if err = g.startInputCapture(); err != nil {
+		return fmt.Errorf("failed to start input capture: %w", err)
+	}
+
+	// Initialize asset preloader for the script timeline
+	g.preloader = NewPreloader(g.script, g.graphics.GetTextureManager(), g.audio, g.assetPath,
+		int64(config.PreloadSeconds)*1000)
+	g.preloader.Start()
+
 	// Initialize menu system
 	g.menu = menu.NewManager(g.graphics, g.audio, g.input, g.filesystem, g.screenWidth, g.screenHeight)
+	g.menu.SetFullscreenHandler(g.ToggleFullscreen)
+	g.menu.SetAutoModeHandler(g.ToggleAutoMode)
+	g.menu.SetSkipModeHandler(g.CycleSkipMode)
+	g.menu.SetSettingsClosedHandler(g.saveGameINI)
+	g.menu.SetSoundSettings(g)
+	g.menu.SetBindingsHandler(g.saveInputBindings)
+	g.menu.SetLanguageSettings(g)
+	g.menu.SetPathResolver(g.localizer.Resolve)
+	g.menu.SetDebugMode(g.debugOverlay)
 	if err = g.menu.Init(); err != nil {
 		return fmt.Errorf("failed to initialize menu: %w", err)
 	}
 
+	// Watch loose files for edits during development
+	g.startHotReload()
+
+	// Jump straight into a scene when one was given on the command line
+	if err = g.startSceneAt(); err != nil {
+		return fmt.Errorf("failed to start scene: %w", err)
+	}
+
 	g.initialized = true
 	log.Println("Game engine initialized successfully")
 	return nil
@@ -98,13 +210,34 @@ func (g *Game) Update() error {
 		return nil
 	}
 
+	// A finished replay ends the session
+	if g.input.IsPlaybackFinished() {
+		return g.replayFinished()
+	}
+
 	// Update input
 	g.input.Update()
 
+	// Debug overlay and developer console keys
+	g.updateDebugKeys()
+
+	// Alt+Enter or F11 toggles fullscreen
+	if g.input.IsActionJustPressed(input.ActionFullscreen) {
+		g.ToggleFullscreen()
+	}
+
+	// F12 saves a screenshot of the next frame
+	if g.input.IsActionJustPressed(input.ActionScreenshot) {
+		g.graphics.RequestScreenshot()
+	}
+
 	// Update script engine
-	if err := g.script.Update(); err != nil {
+	if err := g.updateTimeline(); err != nil {
 		return err
 	}
+	g.flushReadLog()
+	g.updateHotReload()
+	g.preloader.Update()
 
 	// Update menu system
 	if err := g.menu.Update(); err != nil {
@@ -114,6 +247,18 @@ func (g *Game) Update() error {
 	// Update audio
 	g.audio.Update()
 
+	// Record or check this tick's state
+	return g.endInputTick()
+}
+
+// updateTimeline advances the script and the text wait, auto and skip
+// modes that steer it
+func (g *Game) updateTimeline() error {
+	if err := g.script.Update(); err != nil {
+		return err
+	}
+	g.updateTextWait()
+	g.updateSkip()
 	return nil
 }
 
@@ -125,22 +270,61 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		return
 	}
 
-	// Clear screen
+	// Clear screen and the letterbox bars
 	screen.Fill(color.RGBA{0, 0, 0, 255})
+	g.offscreen.Fill(color.RGBA{0, 0, 0, 255})
 
 	// Draw all layers through graphics renderer
-	g.graphics.Draw(screen)
+	g.graphics.Draw(g.offscreen)
 
 	// Draw menu system
-	g.menu.Draw(screen)
+	g.menu.Draw(g.offscreen)
+
+	// Capture the composed frame before debug output is added
+	g.updateBurstCapture()
+	g.graphics.CaptureFrame(g.offscreen)
+
+	// Scale the logical screen into the window
+	g.viewport.Present(screen, g.offscreen)
 
 	// Debug info
 	ebitenutil.DebugPrint(screen, fmt.Sprintf("FPS: %.2f", ebiten.ActualFPS()))
+	if g.debugOverlay {
+		g.drawDebugOverlay(screen)
+	}
+	if g.console.open {
+		g.drawConsole(screen)
+	}
+	g.drawModeIndicator(screen)
 }
 
-// Layout returns the game's screen size
+// Layout uses the whole window; Draw fits the logical screen into it
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return g.screenWidth, g.screenHeight
+	if g.viewport == nil {
+		return g.screenWidth, g.screenHeight
+	}
+
+	g.viewport.Resize(outsideWidth, outsideHeight)
+	return outsideWidth, outsideHeight
+}
+
+// ToggleFullscreen switches between windowed and fullscreen mode and saves the choice
+func (g *Game) ToggleFullscreen() {
+	g.SetFullscreen(!ebiten.IsFullscreen())
+}
+
+// SetFullscreen sets the window mode and saves it to the settings file
+func (g *Game) SetFullscreen(fullscreen bool) {
+	ebiten.SetFullscreen(fullscreen)
+
+	err := g.settings.Update(func(config *settings.Config) {
+		config.Fullscreen = fullscreen
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save window mode: %v", err)
+	}
+
+	log.Printf("Fullscreen: %v", fullscreen)
 }
 
 // Run starts the game
@@ -150,15 +334,87 @@ func (g *Game) Run() error {
 		return err
 	}
 
+	// Without a window the game loop is driven here
+	if g.headless {
+		return g.runHeadless()
+	}
+
 	// Set window properties
-	ebiten.SetWindowSize(g.screenWidth, g.screenHeight)
+	config := g.settings.GetConfig()
+	windowWidth, windowHeight := g.screenWidth, g.screenHeight
+	if config.WindowWidth > 0 && config.WindowHeight > 0 {
+		windowWidth, windowHeight = config.WindowWidth, config.WindowHeight
+	}
+	ebiten.SetWindowSize(windowWidth, windowHeight)
 	ebiten.SetWindowTitle("School Days Engine - Go Port")
-	ebiten.SetWindowResizable(false)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetFullscreen(config.Fullscreen)
 
 	// Run the game loop
-	if err := ebiten.RunGame(g); err != nil {
+	err := ebiten.RunGame(g)
+	g.shutdown()
+
+	return err
+}
+
+// shutdown flushes recordings, logs and settings when the game loop ends
+func (g *Game) shutdown() {
+	g.stopHotReload()
+	if stopErr := g.input.StopRecording(); stopErr != nil {
+		log.Printf("Warning: %v", stopErr)
+	}
+	g.preloader.Close()
+	g.saveReadLog()
+	g.graphics.GetFrameCapture().Wait()
+	g.saveWindowSize()
+}
+
+// saveWindowSize remembers the windowed size for the next start
+func (g *Game) saveWindowSize() {
+	if g.headless || ebiten.IsFullscreen() {
+		return
+	}
+
+	width, height := ebiten.WindowSize()
+	err := g.settings.Update(func(config *settings.Config) {
+		config.WindowWidth, config.WindowHeight = width, height
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save window size: %v", err)
+	}
+}
+
+// LoadScene loads a JRS script in the current language through the
+// filesystem and starts playing it
+func (g *Game) LoadScene(filename string) error {
+	scene, err := script.LoadJRS(g.filesystem, g.localizer.ScriptPath(filename))
+	if err != nil {
+		return err
+	}
+
+	g.saveReadLog()
+	g.script.LoadScript(scene)
+	g.script.Start()
+	return nil
+}
+
+// ReloadScene parses the active script again, in the current language, and
+// resumes it at the same timeline position. Voices, BGM and backgrounds the
+// new version still plays at that position carry on.
+func (g *Game) ReloadScene() error {
+	active := g.script.GetActiveScript()
+	if active == nil {
+		return fmt.Errorf("no script is loaded")
+	}
+
+	scene, err := script.LoadJRS(g.filesystem, g.localizer.ScriptPath(active.Name))
+	if err != nil {
 		return err
 	}
 
+	g.script.ReplaceScript(scene)
+	g.restoreSceneState()
+
+	log.Printf("Reloaded %s at %d ms", active.Name, g.script.GetPosition())
 	return nil
 }
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"school-days-engine/internal/locale"
+)
+
+// hotReloadInterval is how often the loose files are scanned for changes
+const hotReloadInterval = time.Second
+
+// SetHotReload watches the loose texture and script directories under the
+// game root and the override directory while running: edited textures are
+// reloaded and an edited active script is parsed again and resumed at the
+// same position. Loose files then take precedence over GPK archives. Must
+// be called before Run.
+func (g *Game) SetHotReload(enabled bool) {
+	g.hotReload = enabled
+}
+
+// startHotReload starts the file watcher if hot reload is enabled (loose
+// files are read first, see applySettings)
+func (g *Game) startHotReload() {
+	if !g.hotReload {
+		return
+	}
+	g.watcher = g.filesystem.Watch(hotReloadInterval, g.hotReloadDirs())
+}
+
+// hotReloadDirs returns the loose directories holding the files hot reload
+// applies: the event CG folders ("Event00", ...), the menu textures in
+// "System" and the script trees, found in the game root or in the override
+// directory next to the settings file
+func (g *Game) hotReloadDirs() []string {
+	var dirs []string
+	found := make(map[string]bool)
+	for _, base := range []string{g.rootDir, g.filesystem.GetOverrideDir()} {
+		if base == "" {
+			continue
+		}
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			if base == g.rootDir {
+				log.Printf("Warning: hot reload cannot list %s: %v", base, err)
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			lower := strings.ToLower(name)
+			if found[name] || !entry.IsDir() {
+				continue
+			}
+			if strings.HasPrefix(lower, "event") || lower == "system" || strings.EqualFold(name, locale.ScriptRoot) {
+				found[name] = true
+				dirs = append(dirs, name)
+			}
+		}
+	}
+	return dirs
+}
+
+// stopHotReload stops the file watcher
+func (g *Game) stopHotReload() {
+	if g.watcher != nil {
+		g.watcher.Close()
+		g.watcher = nil
+	}
+}
+
+// updateHotReload applies the file changes found since the last tick
+func (g *Game) updateHotReload() {
+	if g.watcher == nil {
+		return
+	}
+
+	select {
+	case changed := <-g.watcher.Changes():
+		g.applyFileChanges(changed)
+	default:
+	}
+}
+
+// applyFileChanges reloads the textures and the active script among changed files
+func (g *Game) applyFileChanges(changed []string) {
+	reloadScript := false
+	for _, file := range changed {
+		switch strings.ToLower(path.Ext(file)) {
+		case ".png", ".jpg":
+			if g.graphics.ReloadTexture(file) {
+				log.Printf("Hot reload: texture %s", file)
+			}
+		case ".jrs":
+			if active := g.script.GetActiveScript(); active != nil && sameFile(active.Name, file) {
+				reloadScript = true
+			}
+		}
+	}
+
+	if reloadScript {
+		if err := g.ReloadScene(); err != nil {
+			log.Printf("Warning: hot reload failed: %v", err)
+		}
+	}
+}
+
+// sameFile compares a name used by the game with a watched path
+func sameFile(name, file string) bool {
+	name = strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "./")
+	return strings.EqualFold(path.Clean(name), path.Clean(file))
+}
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/filesystem"
+)
+
+func TestHotReloadDirs(t *testing.T) {
+	root, override := t.TempDir(), t.TempDir()
+	dirs := map[string][]string{
+		root:     {"Event00", "System", "Script", "Voice00"},
+		override: {"Event00", "Event01", "Ini"},
+	}
+	for base, names := range dirs {
+		for _, name := range names {
+			if err := os.MkdirAll(filepath.Join(base, name), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	g := &Game{rootDir: root, filesystem: filesystem.NewManager(root)}
+	g.filesystem.SetOverrideDir(override)
+
+	// Directories of either base, each once
+	got := g.hotReloadDirs()
+	sort.Strings(got)
+	if want := "Event00,Event01,Script,System"; strings.Join(got, ",") != want {
+		t.Errorf("watched %v, want %s", got, want)
+	}
+}
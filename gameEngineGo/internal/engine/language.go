@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"school-days-engine/internal/locale"
+	"school-days-engine/internal/menu"
+	"school-days-engine/internal/settings"
+)
+
+// applyLanguage switches the script and menu language when the setting
+// changes; the menu screen is shown again with its translated images and a
+// running scene is reloaded in the new language at the same position
+// (called from applySettings)
+func (g *Game) applyLanguage(config *settings.Config) {
+	g.localizer.SetFallback(config.FallbackLang)
+	if strings.EqualFold(locale.DirectoryName(config.Language), locale.DirectoryName(g.localizer.GetLanguage())) {
+		return
+	}
+
+	g.localizer.SetLanguage(config.Language)
+	log.Printf("Language: %s", config.Language)
+
+	if g.menu != nil && g.menu.GetState() != menu.MenuGame {
+		g.menu.SetState(g.menu.GetState())
+	}
+	if g.script != nil && g.script.GetActiveScript() != nil {
+		if err := g.ReloadScene(); err != nil {
+			log.Printf("Warning: failed to reload scene in %s: %v", config.Language, err)
+		}
+	}
+}
+
+// GetLanguageName returns the current language for the settings menu
+func (g *Game) GetLanguageName() string {
+	return fmt.Sprintf("%s (%s)", g.localizer.GetLanguage(), locale.DirectoryName(g.localizer.GetLanguage()))
+}
+
+// CycleLanguage switches to the next language that has scripts and saves it
+func (g *Game) CycleLanguage() {
+	languages := g.localizer.GetLanguages()
+	if len(languages) == 0 {
+		log.Printf("Warning: no script languages found")
+		return
+	}
+
+	next := languages[0]
+	current := g.localizer.GetLanguage()
+	for i, language := range languages {
+		if language == current {
+			next = languages[(i+1)%len(languages)]
+			break
+		}
+	}
+
+	err := g.settings.Update(func(config *settings.Config) {
+		config.Language = next
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save language: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"school-days-engine/internal/script"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Default locations of the game data and the user settings file
+const (
+	defaultRootDir    = "./"
+	defaultConfigPath = "./config/settings.json"
+)
+
+// SetRootDir sets the directory holding the game data (GPK archives and
+// loose files); must be called before Run
+func (g *Game) SetRootDir(dir string) {
+	g.rootDir = dir
+}
+
+// SetConfigPath sets the user settings file; the read log is kept next to
+// it. Must be called before Run.
+func (g *Game) SetConfigPath(path string) {
+	g.configPath = path
+}
+
+// SetStartScene skips the menus and starts a JRS script at a timeline
+// position in milliseconds, for content authors checking a scene
+func (g *Game) SetStartScene(filename string, positionMs int64) {
+	g.startScene = filename
+	g.startAt = positionMs
+}
+
+// SetHeadless runs the game without a window or drawing, updating at the
+// normal tick rate. Useful with SetReplay to check recordings. Must be
+// called before Run.
+func (g *Game) SetHeadless(headless bool) {
+	g.headless = headless
+}
+
+// startSceneAt loads the start scene given with SetStartScene, if any
+func (g *Game) startSceneAt() error {
+	if g.startScene == "" {
+		return nil
+	}
+
+	if err := g.LoadScene(g.startScene); err != nil {
+		return err
+	}
+	if g.startAt > 0 {
+		g.seekScene(g.startAt)
+	}
+	g.menu.EnterGame()
+
+	log.Printf("Started %s at %d ms", g.startScene, g.startAt)
+	return nil
+}
+
+// seekScene moves the active script to a position and restores the scene
+// state left by the actions before it. Actions running at the position
+// restart on the next update.
+func (g *Game) seekScene(position int64) {
+	g.script.SeekTo(position)
+	g.restoreSceneState()
+}
+
+// restoreSceneState applies the scene state left by the actions before the
+// timeline position: the last fade, and the background and BGM without an
+// end. BGM that is already playing carries on.
+func (g *Game) restoreSceneState() {
+	active := g.script.GetActiveScript()
+	if active == nil || g.scene == nil {
+		return
+	}
+	for _, action := range active.PersistentActions(g.script.GetPosition()) {
+		if action.Action == script.ActionPlayBgm && g.audio.IsBGMFilePlaying(g.assetPath(action.File)) {
+			continue
+		}
+		g.scene.StartAction(action)
+		g.scene.EndAction(action)
+	}
+}
+
+// runHeadless drives the game loop itself instead of ebiten.RunGame, so no
+// window, display or GPU is needed. Nothing is drawn and textures are not
+// decoded; ticks run at the normal rate until the game ends (e.g. a replay
+// finishes).
+func (g *Game) runHeadless() error {
+	log.Printf("Running headless at %d ticks per second", ebiten.TPS())
+	defer g.shutdown()
+
+	ticker := time.NewTicker(time.Second / time.Duration(max(1, ebiten.TPS())))
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := g.Update(); err != nil {
+			if errors.Is(err, ebiten.Termination) {
+				return nil // As RunGame
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"context"
+	"log"
+
+	"school-days-engine/internal/audio"
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/graphics"
+	"school-days-engine/internal/script"
+)
+
+// preloadQueueSize is the number of assets that may wait for the worker
+const preloadQueueSize = 64
+
+// preloadKind selects what the worker does with an asset
+type preloadKind int
+
+const (
+	preloadTexture preloadKind = iota // Decode an image into the texture cache
+	preloadSound                      // Decode a sound effect to PCM
+	preloadVoice                      // Read a voice line into memory
+)
+
+// preloadJob is a single asset to decode in the background
+type preloadJob struct {
+	ctx  context.Context
+	path string
+	kind preloadKind
+}
+
+// Preloader scans the active script timeline ahead of the playback position
+// and decodes upcoming images, sound effects and ambience on a background
+// goroutine, so that starting an action does not stall on I/O, PNG or Vorbis
+// decoding. Voice lines are read ahead and decoded while they stream; BGM is
+// streamed from disk and movies are not played by the engine, so neither is
+// preloaded.
+type Preloader struct {
+	script    *script.Engine
+	textures  *graphics.TextureManager
+	audio     *audio.Manager
+	assetPath func(file string) string
+	lookahead int64 // Lookahead window in ms
+
+	jobs       chan preloadJob
+	done       chan struct{}
+	queued     map[string]bool
+	generation uint64
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// NewPreloader creates a preloader looking lookaheadMs ahead of the script clock
+func NewPreloader(scriptEngine *script.Engine, textures *graphics.TextureManager, aud *audio.Manager, assetPath func(string) string, lookaheadMs int64) *Preloader {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Preloader{
+		script:     scriptEngine,
+		textures:   textures,
+		audio:      aud,
+		assetPath:  assetPath,
+		lookahead:  lookaheadMs,
+		jobs:       make(chan preloadJob, preloadQueueSize),
+		done:       make(chan struct{}),
+		queued:     make(map[string]bool),
+		generation: scriptEngine.Generation(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start launches the background worker
+func (p *Preloader) Start() {
+	go p.worker()
+	log.Printf("Asset preloader started (lookahead %d ms)", p.lookahead)
+}
+
+// Update queues assets of upcoming actions; must be called from the game loop
+func (p *Preloader) Update() {
+	// The timeline jumped: everything in flight is for the wrong position
+	if generation := p.script.Generation(); generation != p.generation {
+		p.reset()
+		p.generation = generation
+	}
+
+	if p.lookahead <= 0 {
+		return
+	}
+
+	for _, action := range p.script.Upcoming(p.lookahead) {
+		job, ok := p.jobFor(action)
+		if !ok || p.queued[job.path] {
+			continue
+		}
+
+		select {
+		case p.jobs <- job:
+			p.queued[job.path] = true
+		default:
+			return // Queue full, retry on the next tick
+		}
+	}
+}
+
+// Close cancels pending work and stops the worker
+func (p *Preloader) Close() {
+	p.cancel()
+	close(p.jobs)
+	<-p.done
+}
+
+// reset cancels in-flight jobs and drops assets preloaded for the old position
+func (p *Preloader) reset() {
+	p.cancel()
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	for path := range p.queued {
+		delete(p.queued, path)
+	}
+	p.audio.ClearPreloaded()
+}
+
+// jobFor returns the preload job for an action, if it references an asset
+func (p *Preloader) jobFor(action *script.Action) (preloadJob, bool) {
+	if action.File == "" {
+		return preloadJob{}, false
+	}
+
+	switch action.Action {
+	case script.ActionCreateBG:
+		return preloadJob{ctx: p.ctx, path: filesystem.NormalizeName(action.File), kind: preloadTexture}, true
+	case script.ActionPlaySe, script.ActionPlayES:
+		return preloadJob{ctx: p.ctx, path: p.assetPath(action.File), kind: preloadSound}, true
+	case script.ActionPlayVoice:
+		return preloadJob{ctx: p.ctx, path: p.assetPath(action.File), kind: preloadVoice}, true
+	default:
+		return preloadJob{}, false
+	}
+}
+
+// worker decodes queued assets until the job channel is closed
+func (p *Preloader) worker() {
+	defer close(p.done)
+
+	for job := range p.jobs {
+		if job.ctx.Err() != nil {
+			continue // Cancelled by a seek or scene change
+		}
+
+		var err error
+		switch job.kind {
+		case preloadTexture:
+			err = p.textures.Preload(job.path)
+		case preloadSound:
+			err = p.audio.PreloadSound(job.path)
+		case preloadVoice:
+			err = p.audio.PreloadAudio(job.path)
+		}
+		if job.kind != preloadTexture && job.ctx.Err() != nil {
+			// Finished after cancellation, don't keep stale data around
+			p.audio.DiscardPreloaded(job.path)
+			continue
+		}
+
+		if err != nil && job.ctx.Err() == nil {
+			log.Printf("Warning: failed to preload %s: %v", job.path, err)
+		}
+	}
+}
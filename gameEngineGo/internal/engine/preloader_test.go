@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"testing"
+
+	"school-days-engine/internal/script"
+)
+
+func TestPreloadJobFor(t *testing.T) {
+	p := &Preloader{assetPath: func(file string) string { return "asset:" + file }}
+
+	tests := []struct {
+		action string
+		file   string
+		ok     bool
+		kind   preloadKind
+	}{
+		{script.ActionCreateBG, "Event00/TEST/BG-001", true, preloadTexture},
+		{script.ActionPlaySe, "Se00/A", true, preloadSound},
+		{script.ActionPlayES, "Se00/AMB01", true, preloadSound},
+		{script.ActionPlayVoice, "Voice00/line", true, preloadVoice},
+		{script.ActionPlayBgm, "BGM/track", false, 0},
+		{script.ActionPlayMovie, "Movie/op", false, 0},
+		{script.ActionPlaySe, "", false, 0},
+	}
+
+	for _, test := range tests {
+		job, ok := p.jobFor(&script.Action{Action: test.action, File: test.file})
+		if ok != test.ok || (ok && job.kind != test.kind) {
+			t.Errorf("%s %q: job %+v (ok %v), want kind %d (ok %v)", test.action, test.file, job, ok, test.kind, test.ok)
+		}
+	}
+}
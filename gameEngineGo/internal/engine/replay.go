@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SetRecording records the session's input to path when the game runs
+func (g *Game) SetRecording(path string) {
+	g.recordPath = path
+}
+
+// SetReplay replays input recorded with SetRecording instead of live input.
+// The game exits when the recording ends, with an error if the menu or
+// script state diverged from the recorded session.
+func (g *Game) SetReplay(path string) {
+	g.replayPath = path
+}
+
+// startInputCapture starts recording or replaying input on a fixed-step clock
+func (g *Game) startInputCapture() error {
+	switch {
+	case g.replayPath != "":
+		header, err := g.input.StartPlayback(g.replayPath)
+		if err != nil {
+			return err
+		}
+		if header.TPS > 0 {
+			ebiten.SetTPS(header.TPS)
+		}
+		g.script.SetFixedStep(time.Second / time.Duration(ebiten.TPS()))
+
+	case g.recordPath != "":
+		if err := g.input.StartRecording(g.recordPath, ebiten.TPS()); err != nil {
+			return err
+		}
+		g.script.SetFixedStep(time.Second / time.Duration(ebiten.TPS()))
+	}
+	return nil
+}
+
+// endInputTick hands the state reached this tick to the recorder or replay
+func (g *Game) endInputTick() error {
+	if !g.input.IsRecording() && !g.input.IsPlayingBack() {
+		return nil
+	}
+	return g.input.EndTick(g.stateSnapshot())
+}
+
+// stateSnapshot describes the menu and script state compared between a
+// recording and its replay
+func (g *Game) stateSnapshot() string {
+	return fmt.Sprintf("menu=%d dlg=%v %s", g.menu.GetState(), g.menu.InDialog(), g.scriptSnapshot())
+}
+
+// scriptSnapshot describes the script state part of stateSnapshot
+func (g *Game) scriptSnapshot() string {
+	scriptName := ""
+	if active := g.script.GetActiveScript(); active != nil {
+		scriptName = active.Name
+	}
+	return fmt.Sprintf("script=%s pos=%d gen=%d running=%d", scriptName, g.script.GetPosition(),
+		g.script.Generation(), len(g.script.RunningActions()))
+}
+
+// replayFinished reports the end of a replay and stops the game
+func (g *Game) replayFinished() error {
+	log.Printf("Replay finished after %d ticks without divergence", g.input.GetTick())
+	return ebiten.Termination
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"school-days-engine/internal/input"
+	"school-days-engine/internal/script"
+	"school-days-engine/internal/settings"
+)
+
+const replayTestScript = `[
+{"action":"PrintText","start":0,"end":500,"text":"one"},
+{"action":"PrintText","start":1000,"end":1500,"text":"two"},
+{"action":"Next","start":3000}
+]`
+
+// newTimelineTestGame creates a game with only what the script timeline
+// needs: the script engine on a fixed step, input, settings and read log
+func newTimelineTestGame(t *testing.T, jrs string) *Game {
+	t.Helper()
+	dir := t.TempDir()
+
+	g := NewGame()
+	g.configPath = filepath.Join(dir, "settings.json")
+	g.settings = settings.NewManager(g.configPath)
+	g.input = input.NewManager()
+	g.readLog = script.NewReadLog(filepath.Join(dir, readLogFile))
+	g.script = script.NewEngine()
+	g.script.SetWaitFilter(g.waitsAtText)
+	g.script.SetFixedStep(time.Second / 60)
+
+	parsed, err := script.ParseJRS(strings.NewReader(jrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed.Name = "Script/00/00-00-A00.jrs"
+	g.script.LoadScript(parsed)
+	g.script.Start()
+	return g
+}
+
+// tick runs the input and timeline part of one Game.Update
+func (g *Game) testTick(t *testing.T) {
+	t.Helper()
+	g.input.Update()
+	if err := g.updateTimeline(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.input.EndTick(g.scriptSnapshot()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeRecording writes a recording file from frames
+func writeRecording(t *testing.T, frames []input.Frame) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.Encode(input.RecordingHeader{Version: 1, TPS: 60})
+	for _, frame := range frames {
+		encoder.Encode(frame)
+	}
+	return path
+}
+
+func TestReplayAdvanceInput(t *testing.T) {
+	// Advance pressed at ticks 60 and 150, the session ends at tick 300
+	var frames []input.Frame
+	for tick := int64(1); tick <= 300; tick++ {
+		frame := input.Frame{Tick: tick}
+		if tick == 60 || tick == 150 {
+			frame.Actions = []input.Action{input.ActionAdvance}
+		}
+		frames = append(frames, frame)
+	}
+
+	g := newTimelineTestGame(t, replayTestScript)
+	if _, err := g.input.StartPlayback(writeRecording(t, frames)); err != nil {
+		t.Fatal(err)
+	}
+	for !g.input.IsPlaybackFinished() {
+		g.testTick(t)
+	}
+
+	// Held at 500 ms until tick 60, then 1000 ms of script time to the
+	// second line's end at 1500 ms, released at tick 150 and 150 ticks on
+	const wantPosition = 1500 + 150*1000/60
+	if got := g.script.GetActiveScript().Name; got != "Script/00/00-00-A00.jrs" {
+		t.Errorf("replay ended in scene %s", got)
+	}
+	if got := g.script.GetPosition(); got < wantPosition-2 || got > wantPosition+2 {
+		t.Errorf("replay ended at %d ms, want %d", got, wantPosition)
+	}
+}
+
+func TestReplayAutoAdvanceIgnoresVoiceTiming(t *testing.T) {
+	const ticks = 400
+	recordPath := filepath.Join(t.TempDir(), "session.jsonl")
+
+	// Record an auto mode session where the first voice plays for 100 ticks
+	recorded := newTimelineTestGame(t, replayTestScript)
+	recorded.SetAutoMode(true)
+	if err := recorded.input.StartRecording(recordPath, 60); err != nil {
+		t.Fatal(err)
+	}
+	recorded.voiceStarted("Voice00/one.ogg")
+	for tick := 0; tick < ticks; tick++ {
+		if tick == 100 {
+			recorded.voiceStopped("Voice00/one.ogg")
+		}
+		recorded.testTick(t)
+	}
+	if err := recorded.input.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	if recorded.script.GetPosition() <= 1500 {
+		t.Fatalf("auto mode did not pass the second line, at %d ms", recorded.script.GetPosition())
+	}
+
+	// Replay it while the voice finishes much earlier; auto mode must
+	// still advance at the recorded ticks and reach the same state
+	replayed := newTimelineTestGame(t, replayTestScript)
+	replayed.SetAutoMode(true)
+	if _, err := replayed.input.StartPlayback(recordPath); err != nil {
+		t.Fatal(err)
+	}
+	replayed.voiceStarted("Voice00/one.ogg")
+	for tick := 0; !replayed.input.IsPlaybackFinished(); tick++ {
+		if tick == 10 {
+			replayed.voiceStopped("Voice00/one.ogg")
+		}
+		replayed.testTick(t) // Fails on the first diverging tick
+	}
+
+	if got, want := replayed.scriptSnapshot(), recorded.scriptSnapshot(); got != want {
+		t.Errorf("replay ended in %q, recording in %q", got, want)
+	}
+}
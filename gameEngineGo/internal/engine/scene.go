@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"log"
+	"time"
+
+	"school-days-engine/internal/audio"
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/render"
+	"school-days-engine/internal/script"
+)
+
+// sceneHandler applies JRS timeline actions: visuals go through the shared
+// render.ScenePlayer, audio is handled here
+type sceneHandler struct {
+	game   *Game
+	visual *render.ScenePlayer
+
+	// PlayES actions playing on the ambience channels, and the channel the
+	// next one replaces when all are busy
+	ambience     [esChannels]*script.Action
+	nextAmbience int
+}
+
+// newSceneHandler creates the timeline handler for a game
+func newSceneHandler(g *Game) *sceneHandler {
+	return &sceneHandler{
+		game:   g,
+		visual: render.NewScenePlayer(g.graphics),
+	}
+}
+
+// StartAction handles an action reaching its start time (matches Qt runAction)
+func (h *sceneHandler) StartAction(action *script.Action) {
+	g := h.game
+	h.visual.StartAction(action)
+
+	switch action.Action {
+	case script.ActionPlayBgm:
+		if err := g.audio.LoadBGM(g.assetPath(action.File)); err != nil {
+			log.Printf("Warning: failed to load BGM %s: %v", action.File, err)
+			return
+		}
+		g.audio.PlayBGM(-1)
+
+	case script.ActionEndBGM:
+		// Ending song, played once and faded out before the action ends
+		if err := g.audio.LoadBGM(g.assetPath(action.File)); err != nil {
+			log.Printf("Warning: failed to load ending BGM %s: %v", action.File, err)
+			return
+		}
+		g.audio.PlayBGM(1)
+
+	case script.ActionPlaySe:
+		if err := g.audio.PlaySeChannel(seChannel(action), g.assetPath(action.File), false); err != nil {
+			log.Printf("Warning: failed to play SE %s: %v", action.File, err)
+		}
+
+	case script.ActionPlayES:
+		// Environment sounds (ambience) loop until their action ends
+		if err := g.audio.PlaySeChannel(h.startAmbience(action), g.assetPath(action.File), true); err != nil {
+			log.Printf("Warning: failed to play ES %s: %v", action.File, err)
+		}
+
+	case script.ActionPlayVoice:
+		if g.IsSkipping() {
+			return // Voices are not played while skipping
+		}
+		path := g.assetPath(action.File)
+		if err := g.audio.PlayVoice(path, action.Persona); err != nil {
+			log.Printf("Warning: failed to play voice %s: %v", action.File, err)
+			return
+		}
+		g.voiceStarted(path)
+		g.voiceSpoken(action.Persona)
+
+	case script.ActionCreateBG, script.ActionBlackFade, script.ActionWhiteFade:
+		// Handled by the scene player
+
+	case script.ActionPrintText:
+		// Remembered for skip mode
+		g.markRead(action)
+
+	case script.ActionSkipFrame, script.ActionNext:
+		// Markers only, nothing to do
+
+	default:
+		log.Printf("Script action %s at %d ms not handled yet", action.Action, action.Start)
+	}
+}
+
+// UpdateAction handles a running action each frame
+func (h *sceneHandler) UpdateAction(action *script.Action, position int64) {
+	h.visual.UpdateAction(action, position)
+
+	if action.Action == script.ActionEndBGM && action.HasEnd() {
+		remaining := action.End - position
+		if remaining <= audio.DefaultBGMFadeOut.Milliseconds() {
+			h.game.audio.FadeOutBGMFile(h.game.assetPath(action.File), time.Duration(remaining)*time.Millisecond)
+		}
+	}
+}
+
+// EndAction handles an action reaching its end time (matches Qt action_stop).
+// BGM, sounds and voices without an end keep playing until they finish or
+// something replaces them.
+func (h *sceneHandler) EndAction(action *script.Action) {
+	h.visual.EndAction(action)
+
+	switch action.Action {
+	case script.ActionPlayBgm:
+		// Fade rather than stop, so a following PlayBgm crossfades
+		if action.HasEnd() {
+			h.game.audio.FadeOutBGMFile(h.game.assetPath(action.File), audio.DefaultBGMFadeOut)
+		}
+
+	case script.ActionEndBGM:
+		if action.HasEnd() {
+			h.game.audio.FadeOutBGMFile(h.game.assetPath(action.File), 0)
+		}
+
+	case script.ActionPlayVoice:
+		// A newer line may already have replaced this one
+		if action.HasEnd() {
+			path := h.game.assetPath(action.File)
+			h.game.audio.StopVoiceFile(path)
+			h.game.voiceStopped(path)
+		}
+
+	case script.ActionPlaySe:
+		// A newer sound may already have replaced this one on the channel
+		if action.HasEnd() {
+			h.game.audio.StopSeChannelFile(seChannel(action), h.game.assetPath(action.File))
+		}
+
+	case script.ActionPlayES:
+		// Ambience without an end keeps its channel until replaced
+		if action.HasEnd() {
+			if channel, ok := h.endAmbience(action); ok {
+				h.game.audio.StopSeChannelFile(channel, h.game.assetPath(action.File))
+			}
+		}
+	}
+}
+
+// PlayES actions have no layer and play on their own channels, above the
+// layers the scripts use for PlaySe, so that ambience tracks can overlap
+const (
+	esChannels     = 4
+	esFirstChannel = audio.SeChannels - esChannels
+)
+
+// seChannel returns the mixer channel of a PlaySe action: its script layer
+func seChannel(action *script.Action) int {
+	if action.Layer < 0 {
+		return 0
+	}
+	return action.Layer
+}
+
+// startAmbience returns the channel a PlayES action plays on: the first one
+// that is free, or the one used longest ago when all are busy
+func (h *sceneHandler) startAmbience(action *script.Action) int {
+	slot := h.nextAmbience
+	for i, playing := range h.ambience {
+		if playing == nil || !h.game.audio.IsSeChannelPlaying(esFirstChannel+i) {
+			slot = i
+			break
+		}
+	}
+	h.ambience[slot] = action
+	h.nextAmbience = (slot + 1) % esChannels
+	return esFirstChannel + slot
+}
+
+// endAmbience releases the channel of a PlayES action, returning false if
+// another action has taken it over since
+func (h *sceneHandler) endAmbience(action *script.Action) (int, bool) {
+	for i, playing := range h.ambience {
+		if playing == action {
+			h.ambience[i] = nil
+			return esFirstChannel + i, true
+		}
+	}
+	return 0, false
+}
+
+// assetPath returns the filesystem name of a script asset reference, resolved
+// through the mounted archives and loose files by the consumer
+func (g *Game) assetPath(file string) string {
+	return filesystem.NormalizeName(file)
+}
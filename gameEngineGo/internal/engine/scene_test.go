@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"school-days-engine/internal/audio"
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/script"
+)
+
+func TestSeChannel(t *testing.T) {
+	tests := []struct {
+		layer int
+		want  int
+	}{
+		{3, 3},
+		{-1, 0}, // No layer in the script
+	}
+	for _, test := range tests {
+		action := &script.Action{Action: script.ActionPlaySe, Layer: test.layer}
+		if got := seChannel(action); got != test.want {
+			t.Errorf("PlaySe on layer %d: channel %d, want %d", test.layer, got, test.want)
+		}
+	}
+}
+
+// newSceneTestGame creates a timeline test game that plays its actions
+// through the scene handler, with real audio assets: each of sounds is a
+// copy of the audio package's test OGG
+func newSceneTestGame(t *testing.T, jrs string, sounds ...string) *Game {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "audio", "testdata", "test.ogg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	for _, name := range sounds {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := newTimelineTestGame(t, jrs)
+	g.filesystem = filesystem.NewManager(root)
+	if err := g.filesystem.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.filesystem.Close() })
+	g.audio = audio.NewManager(g.filesystem)
+	if err := g.audio.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(g.audio.Cleanup)
+	g.scene = newSceneHandler(g)
+	g.script.SetHandler(g.scene)
+	return g
+}
+
+func TestEndlessSoundsKeepPlaying(t *testing.T) {
+	g := newSceneTestGame(t, `[
+{"action":"PlayES","start":0,"file":"Se00/AMB01"},
+{"action":"PlaySe","start":0,"layer":2,"file":"Se00/SE01"},
+{"action":"Next","start":3000}
+]`, "Se00/AMB01.ogg", "Se00/SE01.ogg")
+
+	g.testTick(t)
+	g.testTick(t)
+
+	if !g.audio.IsSeChannelPlaying(esFirstChannel) {
+		t.Error("ambience without an end stopped")
+	}
+	if !g.audio.IsSeChannelPlaying(2) {
+		t.Error("sound without an end stopped")
+	}
+}
+
+func TestAmbienceChannels(t *testing.T) {
+	g := newSceneTestGame(t, `[
+{"action":"PlayES","start":0,"end":1000,"file":"Se00/AMB01"},
+{"action":"PlayES","start":100,"end":2000,"file":"Se00/AMB02"},
+{"action":"PlayES","start":1100,"end":2000,"file":"Se00/AMB03"},
+{"action":"Next","start":3000}
+]`, "Se00/AMB01.ogg", "Se00/AMB02.ogg", "Se00/AMB03.ogg")
+
+	channelFiles := func() []string {
+		files := make([]string, esChannels)
+		for i := range files {
+			files[i] = g.audio.GetSeChannelFile(esFirstChannel + i)
+		}
+		return files
+	}
+	check := func(when string, want ...string) {
+		t.Helper()
+		got := channelFiles()
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s: ambience channels %q, want %q", when, got, want)
+				return
+			}
+		}
+	}
+
+	// Overlapping tracks mix on separate channels
+	for g.script.GetPosition() < 500 {
+		g.testTick(t)
+	}
+	check("overlap", "Se00/AMB01.ogg", "Se00/AMB02.ogg", "", "")
+
+	// The first track ends and frees its channel for the third
+	for g.script.GetPosition() < 1200 {
+		g.testTick(t)
+	}
+	check("after the first ended", "Se00/AMB03.ogg", "Se00/AMB02.ogg", "", "")
+
+	for g.script.GetPosition() < 2100 {
+		g.testTick(t)
+	}
+	check("all ended", "", "", "", "")
+}
+
+func TestAmbienceChannelsReplaceOldest(t *testing.T) {
+	g := newSceneTestGame(t, `[
+{"action":"PlayES","start":0,"end":1000,"file":"Se00/AMB01"},
+{"action":"PlayES","start":100,"end":2000,"file":"Se00/AMB02"},
+{"action":"PlayES","start":200,"end":2000,"file":"Se00/AMB03"},
+{"action":"PlayES","start":300,"end":2000,"file":"Se00/AMB04"},
+{"action":"PlayES","start":400,"end":2000,"file":"Se00/AMB05"},
+{"action":"Next","start":3000}
+]`, "Se00/AMB01.ogg", "Se00/AMB02.ogg", "Se00/AMB03.ogg", "Se00/AMB04.ogg", "Se00/AMB05.ogg")
+
+	for g.script.GetPosition() < 500 {
+		g.testTick(t)
+	}
+	if file := g.audio.GetSeChannelFile(esFirstChannel); file != "Se00/AMB05.ogg" {
+		t.Errorf("fifth track on %q's channel, want the oldest", file)
+	}
+
+	// The replaced track ending leaves the one replacing it alone
+	for g.script.GetPosition() < 1100 {
+		g.testTick(t)
+	}
+	if !g.audio.IsSeChannelPlaying(esFirstChannel) {
+		t.Error("the replaced track stopped the one replacing it")
+	}
+}
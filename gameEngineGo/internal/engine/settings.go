@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"log"
+
+	"school-days-engine/internal/graphics"
+	"school-days-engine/internal/settings"
+)
+
+// SetOverride overrides a setting for this run only ("key=value" with the
+// settings file key, e.g. "bgm_volume=0.5"); must be called before Run
+func (g *Game) SetOverride(spec string) {
+	g.overrides = append(g.overrides, spec)
+}
+
+// applyOverrides adds the command-line overrides as the top settings layer
+func (g *Game) applyOverrides() {
+	for _, spec := range g.overrides {
+		key, value, err := settings.ParseOverride(spec)
+		if err == nil {
+			err = g.settings.SetOverride(key, value)
+		}
+		if err != nil {
+			log.Printf("Warning: ignoring setting override: %v", err)
+		}
+	}
+}
+
+// applySettings pushes the effective settings into the subsystems (called
+// at startup and after every settings change)
+func (g *Game) applySettings(config *settings.Config) {
+	// Audio
+	g.audio.SetBGMVolume(config.BGMVolume)
+	g.audio.SetSEVolume(config.SFXVolume)
+	g.audio.SetVoiceVolume(config.VoiceVolume)
+	g.audio.SetMuted(config.Muted)
+	g.applyVoicePersonas(config)
+	g.applyDucking(config)
+
+	// Renderer
+	g.viewport.SetMode(graphics.ParseScaleMode(config.ScalingMode))
+	if config.ScreenshotDir != "" {
+		g.graphics.GetFrameCapture().SetDirectory(config.ScreenshotDir)
+	}
+
+	// Filesystem: hot reload always reads edited loose files first
+	g.filesystem.SetLooseFirst(config.LooseFilesFirst || g.hotReload)
+
+	// Input
+	g.input.ResetBindings()
+	g.input.LoadBindings(config.InputBindings)
+
+	// Script and menu language
+	g.applyLanguage(config)
+
+	// Text speed (the typewriter reveal) and auto mode are read from the
+	// config when the timeline holds at a line
+}
+
+// saveGameINI writes the settings shared with the original game back to its
+// INI files (called when the settings menu is left)
+func (g *Game) saveGameINI() {
+	if err := g.settings.SaveGameINI(); err != nil {
+		log.Printf("Warning: failed to save game INI settings: %v", err)
+	}
+}
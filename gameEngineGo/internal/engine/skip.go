@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"log"
+	"time"
+
+	"school-days-engine/internal/input"
+	"school-days-engine/internal/script"
+	"school-days-engine/internal/settings"
+)
+
+// Skip modes stored in settings.Config.SkipMode
+const (
+	SkipModeRead = "read" // Skip only text seen before
+	SkipModeAll  = "all"  // Skip everything up to the next choice
+)
+
+// skipStepMs is how far the timeline jumps each tick while skipping
+const skipStepMs = 200
+
+// readLogFile is the file next to the settings file that keeps the set of
+// seen dialogue lines
+const readLogFile = "readtext.json"
+
+// readLogFlushInterval is how often newly seen lines are written to disk,
+// so a crash loses little of the log
+const readLogFlushInterval = 10 * time.Second
+
+// SetSkipping starts or stops latched skip mode. It stops by itself at
+// unread text (in read mode) and at choices.
+func (g *Game) SetSkipping(enabled bool) {
+	g.skipLatched = enabled
+	log.Printf("Skip mode: %v", enabled)
+}
+
+// ToggleSkipping starts or stops latched skip mode
+func (g *Game) ToggleSkipping() {
+	g.SetSkipping(!g.skipLatched)
+}
+
+// SetSkipMode sets whether skipping passes only read text or everything up
+// to the next choice, and saves it
+func (g *Game) SetSkipMode(mode string) {
+	err := g.settings.Update(func(config *settings.Config) {
+		config.SkipMode = mode
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save skip mode: %v", err)
+	}
+	log.Printf("Skip mode set to skip %s text", mode)
+}
+
+// CycleSkipMode switches between skipping read text and skipping all text
+func (g *Game) CycleSkipMode() {
+	if g.settings.GetConfig().SkipMode == SkipModeAll {
+		g.SetSkipMode(SkipModeRead)
+	} else {
+		g.SetSkipMode(SkipModeAll)
+	}
+}
+
+// IsSkipping returns whether the timeline is being fast-forwarded, latched
+// or while the skip action (Ctrl by default) is held
+func (g *Game) IsSkipping() bool {
+	return g.skipLatched || g.skipHeld
+}
+
+// updateSkip fast-forwards the timeline while skipping (called after the
+// script update)
+func (g *Game) updateSkip() {
+	held := g.input.IsActionPressed(input.ActionSkip)
+	if !held {
+		g.skipBlocked = false
+	}
+	g.skipHeld = held && !g.skipBlocked
+
+	// The toggle action latches skipping; advancing by hand ends it
+	if g.input.IsActionJustPressed(input.ActionSkipToggle) {
+		g.ToggleSkipping()
+	} else if g.skipLatched && g.input.IsActionJustPressed(input.ActionAdvance) {
+		g.SetSkipping(false)
+	}
+
+	if !g.IsSkipping() || g.script.GetActiveScript() == nil {
+		return
+	}
+
+	if stop := g.script.StepUntil(skipStepMs, g.skipStopsAt); stop != nil {
+		log.Printf("Skip stopped at %s (action %d, %d ms)", stop.Action, stop.Index, stop.Start)
+		g.skipLatched = false
+		// Holding the key keeps skipping only after it is pressed again
+		g.skipBlocked = held
+		g.skipHeld = false
+	}
+}
+
+// skipStopsAt returns whether skipping must stop at an action: choices
+// always stop it, text only when it is unread and the mode is "read"
+func (g *Game) skipStopsAt(action *script.Action) bool {
+	switch action.Action {
+	case script.ActionSetSelect:
+		return true
+	case script.ActionPrintText:
+		if g.settings.GetConfig().SkipMode == SkipModeAll {
+			return false
+		}
+		return !g.readLog.IsRead(g.script.GetActiveScript(), action)
+	default:
+		return false
+	}
+}
+
+// markRead remembers that a line of the active script has been shown
+func (g *Game) markRead(action *script.Action) {
+	if active := g.script.GetActiveScript(); active != nil {
+		g.readLog.MarkRead(active, action)
+	}
+}
+
+// flushReadLog writes newly seen lines to disk every readLogFlushInterval
+func (g *Game) flushReadLog() {
+	if time.Since(g.readLogFlushed) < readLogFlushInterval {
+		return
+	}
+	g.readLogFlushed = time.Now()
+	g.saveReadLog()
+}
+
+// saveReadLog writes the seen dialogue lines to disk
+func (g *Game) saveReadLog() {
+	if err := g.readLog.Save(); err != nil {
+		log.Printf("Warning: failed to save read-text log: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"school-days-engine/internal/input"
+)
+
+const skipTestScript = `[
+{"action":"PrintText","start":0,"end":500,"text":"one"},
+{"action":"PrintText","start":1000,"end":1500,"text":"two"},
+{"action":"PrintText","start":2000,"end":2500,"text":"three"},
+{"action":"Next","start":3000}
+]`
+
+// replayFrames replays frames with the given actions pressed at some ticks
+func replayFrames(t *testing.T, g *Game, ticks int64, pressed map[int64]input.Action) {
+	t.Helper()
+	var frames []input.Frame
+	for tick := int64(1); tick <= ticks; tick++ {
+		frame := input.Frame{Tick: tick}
+		if action, ok := pressed[tick]; ok {
+			frame.Actions = []input.Action{action}
+		}
+		frames = append(frames, frame)
+	}
+	if _, err := g.input.StartPlayback(writeRecording(t, frames)); err != nil {
+		t.Fatal(err)
+	}
+	for !g.input.IsPlaybackFinished() {
+		g.testTick(t)
+	}
+}
+
+func TestSkipToggleStopsAtUnreadText(t *testing.T) {
+	g := newTimelineTestGame(t, skipTestScript)
+	active := g.script.GetActiveScript()
+	g.readLog.MarkRead(active, active.Actions[0])
+	g.readLog.MarkRead(active, active.Actions[1])
+
+	// Latch skipping at the first line; it runs through the read lines
+	// and stops where the unread third line starts
+	replayFrames(t, g, 60, map[int64]input.Action{10: input.ActionSkipToggle})
+
+	if g.IsSkipping() {
+		t.Error("skipping did not stop at unread text")
+	}
+	if got := g.script.GetPosition(); got < 2000 || got > 2500 {
+		t.Errorf("skip stopped at %d ms, want the third line (2000-2500 ms)", got)
+	}
+}
+
+func TestSkipToggleTwiceStops(t *testing.T) {
+	g := newTimelineTestGame(t, skipTestScript)
+	replayFrames(t, g, 1, map[int64]input.Action{1: input.ActionSkipToggle})
+	if !g.skipLatched {
+		t.Fatal("toggle action did not latch skipping")
+	}
+	g.ToggleSkipping()
+	if g.IsSkipping() {
+		t.Fatal("second toggle did not stop skipping")
+	}
+}
+
+func TestCycleSkipMode(t *testing.T) {
+	g := newTimelineTestGame(t, skipTestScript)
+	g.CycleSkipMode()
+	if got := g.settings.GetConfig().SkipMode; got != SkipModeAll {
+		t.Fatalf("skip mode %q, want %q", got, SkipModeAll)
+	}
+	g.CycleSkipMode()
+	if got := g.settings.GetConfig().SkipMode; got != SkipModeRead {
+		t.Fatalf("skip mode %q, want %q", got, SkipModeRead)
+	}
+}
+
+func TestFlushReadLog(t *testing.T) {
+	g := newTimelineTestGame(t, skipTestScript)
+	path := filepath.Join(filepath.Dir(g.configPath), readLogFile)
+	active := g.script.GetActiveScript()
+
+	g.markRead(active.Actions[0])
+	g.flushReadLog()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("first flush did not write the log: %v", err)
+	}
+
+	// Within the interval new lines wait for the next flush
+	os.Remove(path)
+	g.markRead(active.Actions[1])
+	g.flushReadLog()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("log written again before the flush interval")
+	}
+
+	g.readLogFlushed = g.readLogFlushed.Add(-readLogFlushInterval)
+	g.flushReadLog()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("log not written after the flush interval: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"school-days-engine/internal/menu"
+	"school-days-engine/internal/settings"
+)
+
+// Sound settings menu steps
+const (
+	duckAmountStep = 0.1
+	duckTimeStepMs = 100
+	duckTimeMaxMs  = 3000
+)
+
+// applyDucking pushes the ducking settings into the audio manager
+func (g *Game) applyDucking(config *settings.Config) {
+	g.audio.SetDucking(config.DuckingEnabled, config.DuckingAmount,
+		time.Duration(config.DuckingAttackMs)*time.Millisecond,
+		time.Duration(config.DuckingReleaseMs)*time.Millisecond)
+}
+
+// AdjustSoundOption changes a sound settings menu option and saves it
+func (g *Game) AdjustSoundOption(option, step int) {
+	if option < 0 || option >= menu.SoundOptionsCount {
+		return
+	}
+	if option >= menu.SoundOptionPersona {
+		g.adjustPersonaOption(option, step)
+		return
+	}
+
+	err := g.settings.Update(func(config *settings.Config) {
+		switch option {
+		case menu.SoundOptionDucking:
+			config.DuckingEnabled = !config.DuckingEnabled
+		case menu.SoundOptionDuckAmount:
+			config.DuckingAmount = clampFloat(config.DuckingAmount+float64(step)*duckAmountStep, 0.0, 1.0)
+		case menu.SoundOptionDuckAttack:
+			config.DuckingAttackMs = clampInt(config.DuckingAttackMs+step*duckTimeStepMs, 0, duckTimeMaxMs)
+		case menu.SoundOptionDuckRelease:
+			config.DuckingReleaseMs = clampInt(config.DuckingReleaseMs+step*duckTimeStepMs, 0, duckTimeMaxMs)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save sound settings: %v", err)
+	}
+}
+
+// DescribeSoundOption returns the label and value of a sound settings menu option
+func (g *Game) DescribeSoundOption(option int) string {
+	config := g.settings.GetConfig()
+
+	switch option {
+	case menu.SoundOptionDucking:
+		state := "Off"
+		if config.DuckingEnabled {
+			state = "On"
+		}
+		return "Lower BGM during voice: " + state
+	case menu.SoundOptionDuckAmount:
+		return fmt.Sprintf("Ducking amount: %.0f%%", config.DuckingAmount*100)
+	case menu.SoundOptionDuckAttack:
+		return fmt.Sprintf("Ducking attack: %d ms", config.DuckingAttackMs)
+	case menu.SoundOptionDuckRelease:
+		return fmt.Sprintf("Ducking release: %d ms", config.DuckingReleaseMs)
+	case menu.SoundOptionPersona, menu.SoundOptionPersonaVolume, menu.SoundOptionPersonaMuted:
+		return g.describePersonaOption(option)
+	default:
+		return ""
+	}
+}
+
+// clampFloat limits a value to a range
+func clampFloat(value, lo, hi float64) float64 {
+	return max(lo, min(hi, value))
+}
+
+// clampInt limits a value to a range
+func clampInt(value, lo, hi int) int {
+	return max(lo, min(hi, value))
+}
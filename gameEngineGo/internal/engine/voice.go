@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"school-days-engine/internal/audio"
+	"school-days-engine/internal/menu"
+	"school-days-engine/internal/settings"
+)
+
+// applyVoicePersonas pushes the saved per-character voice settings into the audio manager
+func (g *Game) applyVoicePersonas(config *settings.Config) {
+	voices := make(map[string]audio.PersonaVoice, len(config.VoicePersonas))
+	for persona, voice := range config.VoicePersonas {
+		voices[persona] = audio.PersonaVoice{Volume: voice.Volume, Muted: voice.Muted}
+	}
+	g.audio.LoadPersonaVoices(voices)
+}
+
+// SetPersonaVolume changes the voice volume of a character and saves it
+func (g *Game) SetPersonaVolume(persona string, volume float64) {
+	g.audio.SetPersonaVolume(persona, volume)
+	g.savePersonaVoice(persona)
+}
+
+// SetPersonaMuted mutes or unmutes a character's voice and saves it
+func (g *Game) SetPersonaMuted(persona string, muted bool) {
+	g.audio.SetPersonaMuted(persona, muted)
+	g.savePersonaVoice(persona)
+}
+
+// personaVolumeStep is the change of a character's voice volume per menu step
+const personaVolumeStep = 0.1
+
+// voiceSpoken remembers a character heard this session, so the sound menu
+// can offer it before it has saved settings
+func (g *Game) voiceSpoken(persona string) {
+	persona = strings.ToLower(strings.TrimSpace(persona))
+	if persona == "" {
+		return
+	}
+	if g.spokenPersonas == nil {
+		g.spokenPersonas = make(map[string]bool)
+	}
+	g.spokenPersonas[persona] = true
+}
+
+// menuPersonas returns the characters shown in the sound menu, sorted: those
+// with saved voice settings and those heard this session
+func (g *Game) menuPersonas() []string {
+	found := make(map[string]bool, len(g.spokenPersonas))
+	for persona := range g.spokenPersonas {
+		found[persona] = true
+	}
+	for persona := range g.settings.GetConfig().VoicePersonas {
+		found[strings.ToLower(strings.TrimSpace(persona))] = true
+	}
+
+	personas := make([]string, 0, len(found))
+	for persona := range found {
+		personas = append(personas, persona)
+	}
+	sort.Strings(personas)
+	return personas
+}
+
+// menuPersona returns the character selected in the sound menu, the first
+// one if none is, or "" if no character is known
+func (g *Game) menuPersona() string {
+	personas := g.menuPersonas()
+	for _, persona := range personas {
+		if persona == g.selectedPersona {
+			return persona
+		}
+	}
+	if len(personas) == 0 {
+		return ""
+	}
+	return personas[0]
+}
+
+// adjustPersonaOption changes a character voice option of the sound menu;
+// the changed setting is saved by SetPersonaVolume or SetPersonaMuted
+func (g *Game) adjustPersonaOption(option, step int) {
+	personas := g.menuPersonas()
+	persona := g.menuPersona()
+	if persona == "" {
+		return
+	}
+
+	switch option {
+	case menu.SoundOptionPersona:
+		index := sort.SearchStrings(personas, persona)
+		g.selectedPersona = personas[((index+step)%len(personas)+len(personas))%len(personas)]
+	case menu.SoundOptionPersonaVolume:
+		voice := g.audio.GetPersonaVoice(persona)
+		g.SetPersonaVolume(persona, clampFloat(voice.Volume+float64(step)*personaVolumeStep, 0.0, 1.0))
+	case menu.SoundOptionPersonaMuted:
+		g.SetPersonaMuted(persona, !g.audio.GetPersonaVoice(persona).Muted)
+	}
+}
+
+// describePersonaOption returns the label and value of a character voice
+// option of the sound menu
+func (g *Game) describePersonaOption(option int) string {
+	persona := g.menuPersona()
+	if persona == "" {
+		if option == menu.SoundOptionPersona {
+			return "Character: none heard yet"
+		}
+		return ""
+	}
+
+	voice := g.audio.GetPersonaVoice(persona)
+	switch option {
+	case menu.SoundOptionPersona:
+		return "Character: " + strings.ToUpper(persona)
+	case menu.SoundOptionPersonaVolume:
+		return fmt.Sprintf("Character volume: %.0f%%", voice.Volume*100)
+	case menu.SoundOptionPersonaMuted:
+		state := "On"
+		if voice.Muted {
+			state = "Muted"
+		}
+		return "Character voice: " + state
+	default:
+		return ""
+	}
+}
+
+// savePersonaVoice copies a character's voice settings into the config file
+func (g *Game) savePersonaVoice(persona string) {
+	voice := g.audio.GetPersonaVoice(persona)
+
+	err := g.settings.Update(func(config *settings.Config) {
+		if config.VoicePersonas == nil {
+			config.VoicePersonas = make(map[string]settings.PersonaVoice)
+		}
+		// Keyed in lower case like the audio manager, so "MAK" and "mak" share one entry
+		config.VoicePersonas[strings.ToLower(strings.TrimSpace(persona))] = settings.PersonaVoice{Volume: voice.Volume, Muted: voice.Muted}
+	})
+	if err != nil {
+		log.Printf("Warning: failed to save voice settings: %v", err)
+	}
+}
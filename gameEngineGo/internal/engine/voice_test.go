@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"path/filepath"
+	"testing"
+
+	"school-days-engine/internal/audio"
+	"school-days-engine/internal/menu"
+	"school-days-engine/internal/settings"
+)
+
+func TestSoundMenuPersonaOptions(t *testing.T) {
+	g := NewGame()
+	g.settings = settings.NewManager(filepath.Join(t.TempDir(), "settings.json"))
+	g.audio = audio.NewManager(nil)
+
+	if got := g.DescribeSoundOption(menu.SoundOptionPersona); got != "Character: none heard yet" {
+		t.Errorf("no characters described as %q", got)
+	}
+	g.AdjustSoundOption(menu.SoundOptionPersonaVolume, -1) // Nothing to change
+
+	g.voiceSpoken("SEK")
+	g.voiceSpoken("mak")
+	if got := g.DescribeSoundOption(menu.SoundOptionPersona); got != "Character: MAK" {
+		t.Errorf("first character described as %q", got)
+	}
+
+	// Lower MAK twice, then switch to SEK and mute it
+	g.AdjustSoundOption(menu.SoundOptionPersonaVolume, -1)
+	g.AdjustSoundOption(menu.SoundOptionPersonaVolume, -1)
+	g.AdjustSoundOption(menu.SoundOptionPersona, 1)
+	g.AdjustSoundOption(menu.SoundOptionPersonaMuted, 1)
+
+	voices := g.settings.GetConfig().VoicePersonas
+	if voice := voices["mak"]; voice.Volume < 0.79 || voice.Volume > 0.81 || voice.Muted {
+		t.Errorf("saved mak voice %+v, want volume 0.8", voice)
+	}
+	if voice := voices["sek"]; !voice.Muted || voice.Volume != 1 {
+		t.Errorf("saved sek voice %+v, want muted", voice)
+	}
+	if got := g.DescribeSoundOption(menu.SoundOptionPersonaMuted); got != "Character voice: Muted" {
+		t.Errorf("muted character described as %q", got)
+	}
+
+	// Saved characters stay in the menu in the next session
+	g.spokenPersonas = nil
+	g.AdjustSoundOption(menu.SoundOptionPersona, 1)
+	if got := g.DescribeSoundOption(menu.SoundOptionPersona); got != "Character: MAK" {
+		t.Errorf("selection wrapped to %q", got)
+	}
+	if got := g.DescribeSoundOption(menu.SoundOptionPersonaVolume); got != "Character volume: 80%" {
+		t.Errorf("volume described as %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// ArchiveFile is an open GPK entry. It reads with ReadAt on the shared
+// package handle, so any number of entries can be streamed at once.
+type ArchiveFile struct {
+	*io.SectionReader
+	name     string
+	buffered bool   // Unpacked into memory instead of read from the package
+	release  func() // Ends the use of the package handle, nil once closed
+}
+
+// Close releases the entry. The package handle is closed with its last
+// entry if the package was closed meanwhile; further reads fail.
+func (f *ArchiveFile) Close() error {
+	if f.release != nil {
+		f.release()
+		f.release = nil
+	}
+	f.SectionReader = io.NewSectionReader(closedReader{}, 0, f.Size())
+	return nil
+}
+
+// Stat describes the entry like os.File.Stat, so streaming consumers can
+// treat archive entries and loose files alike
+func (f *ArchiveFile) Stat() (fs.FileInfo, error) {
+	return archiveFileInfo{name: path.Base(strings.ReplaceAll(f.name, "\\", "/")), size: f.Size()}, nil
+}
+
+// Buffered returns whether the entry data is held in memory
+func (f *ArchiveFile) Buffered() bool {
+	return f.buffered
+}
+
+// closedReader fails every read of a closed entry
+type closedReader struct{}
+
+func (closedReader) ReadAt(p []byte, off int64) (int, error) {
+	return 0, fs.ErrClosed
+}
+
+// archiveFileInfo is the fs.FileInfo of an archive entry
+type archiveFileInfo struct {
+	name string
+	size int64
+}
+
+func (i archiveFileInfo) Name() string       { return i.name }
+func (i archiveFileInfo) Size() int64        { return i.size }
+func (i archiveFileInfo) Mode() fs.FileMode  { return 0444 }
+func (i archiveFileInfo) ModTime() time.Time { return time.Time{} }
+func (i archiveFileInfo) IsDir() bool        { return false }
+func (i archiveFileInfo) Sys() interface{}   { return nil }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 	"unicode/utf16"
 )
 
@@ -51,14 +52,23 @@ type GPKEntry struct {
 // GPK represents a GPK package file
 type GPK struct {
 	entries  []GPKEntry
+	index    map[string]int // Lower-case entry name -> position in entries
 	fileName string
-	file     *os.File
+
+	// The package is opened on first use and shared by every reader; data
+	// is read with ReadAt, so readers on several goroutines never race on
+	// a file position. Open readers keep the handle alive past Close.
+	openMutex sync.Mutex
+	file      *os.File
+	readers   int  // Users of file: open entries and extractions in progress
+	closed    bool // Close was called while readers were still open
 }
 
 // NewGPK creates a new GPK instance
 func NewGPK(fileName string) (*GPK, error) {
 	gpk := &GPK{
 		entries:  make([]GPKEntry, 0),
+		index:    make(map[string]int),
 		fileName: fileName,
 	}
 
@@ -70,12 +80,59 @@ func NewGPK(fileName string) (*GPK, error) {
 	return gpk, nil
 }
 
-// Close closes the GPK file handle
+// Close closes the GPK file handle. Entries still open keep reading from it
+// until they are closed; the handle is released with the last of them.
 func (g *GPK) Close() error {
-	if g.file != nil {
-		return g.file.Close()
+	g.openMutex.Lock()
+	defer g.openMutex.Unlock()
+
+	if g.readers > 0 {
+		g.closed = true
+		return nil
 	}
-	return nil
+	return g.closeFile()
+}
+
+// acquireFile returns the package file handle, opening it on first use, and
+// counts the caller as a reader until releaseFile
+func (g *GPK) acquireFile() (*os.File, error) {
+	g.openMutex.Lock()
+	defer g.openMutex.Unlock()
+
+	if g.file == nil {
+		file, err := os.Open(g.fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open GPK file: %w", err)
+		}
+		g.file = file
+	}
+	// Used again after Close, like a package that was never closed
+	g.closed = false
+	g.readers++
+	return g.file, nil
+}
+
+// releaseFile ends a use of the file handle, closing it if Close was called
+// while it was in use
+func (g *GPK) releaseFile() {
+	g.openMutex.Lock()
+	defer g.openMutex.Unlock()
+
+	g.readers--
+	if g.readers == 0 && g.closed {
+		g.closeFile()
+	}
+}
+
+// closeFile closes the file handle; openMutex must be held
+func (g *GPK) closeFile() error {
+	g.closed = false
+	if g.file == nil {
+		return nil
+	}
+	err := g.file.Close()
+	g.file = nil
+	return err
 }
 
 // GetEntries returns all entries in the GPK
@@ -85,34 +142,27 @@ func (g *GPK) GetEntries() []GPKEntry {
 
 // FindEntry finds an entry by name (case-insensitive)
 func (g *GPK) FindEntry(name string) (*GPKEntry, bool) {
-	for _, entry := range g.entries {
-		if equalsCaseInsensitive(entry.Name, name) {
-			return &entry, true
-		}
+	i, ok := g.index[lowerASCII(name)]
+	if !ok {
+		return nil, false
 	}
-	return nil, false
+	entry := g.entries[i]
+	return &entry, true
 }
 
-// ExtractFile extracts a file from the GPK and returns its data
+// ExtractFile extracts a file from the GPK and returns its data. Safe to
+// call from several goroutines.
 func (g *GPK) ExtractFile(entry *GPKEntry) ([]byte, error) {
-	if g.file == nil {
-		file, err := os.Open(g.fileName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to open GPK file: %w", err)
-		}
-		g.file = file
-	}
-
-	// Seek to file data
-	_, err := g.file.Seek(int64(entry.Header.Offset), 0)
+	file, err := g.acquireFile()
 	if err != nil {
-		return nil, fmt.Errorf("failed to seek to file data: %w", err)
+		return nil, err
 	}
+	defer g.releaseFile()
 
 	// Read compressed data
 	compressedData := make([]byte, entry.Header.CompressedFileLen)
-	_, err = g.file.Read(compressedData)
-	if err != nil {
+	section := io.NewSectionReader(file, int64(entry.Header.Offset), int64(entry.Header.CompressedFileLen))
+	if _, err := io.ReadFull(section, compressedData); err != nil {
 		return nil, fmt.Errorf("failed to read compressed data: %w", err)
 	}
 
@@ -127,6 +177,33 @@ func (g *GPK) ExtractFile(entry *GPKEntry) ([]byte, error) {
 	return compressedData, nil
 }
 
+// OpenEntry opens an entry for reading without extracting it first. Stored
+// entries are read from the package on demand; DFLT entries have to be
+// unpacked and are served from memory. Safe to call from several goroutines.
+func (g *GPK) OpenEntry(entry *GPKEntry) (*ArchiveFile, error) {
+	if string(entry.Header.MagicDFLT[:4]) == "DFLT" && entry.Header.UncompressedLen > 0 {
+		data, err := g.ExtractFile(entry)
+		if err != nil {
+			return nil, err
+		}
+		return &ArchiveFile{
+			SectionReader: io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data))),
+			name:          entry.Name,
+			buffered:      true,
+		}, nil
+	}
+
+	file, err := g.acquireFile()
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveFile{
+		SectionReader: io.NewSectionReader(file, int64(entry.Header.Offset), int64(entry.Header.CompressedFileLen)),
+		name:          entry.Name,
+		release:       g.releaseFile,
+	}, nil
+}
+
 // load loads and parses the GPK file
 func (g *GPK) load() error {
 	file, err := os.Open(g.fileName)
@@ -302,6 +379,10 @@ func (g *GPK) parseEntries(data []byte) error {
 			Name:   filename,
 			Header: *header,
 		}
+		// The first of duplicate names wins, as with a scan of the entries
+		if _, exists := g.index[lowerASCII(filename)]; !exists {
+			g.index[lowerASCII(filename)] = len(g.entries)
+		}
 		g.entries = append(g.entries, entry)
 
 		// Check for continuation or end of data
@@ -480,6 +561,18 @@ func decompressPIDX(compressedData []byte, forceSkipDecryption bool) ([]byte, er
 	return uncompressedData, nil
 }
 
+// lowerASCII lower-cases the ASCII letters of a string, the case folding of
+// equalsCaseInsensitive
+func lowerASCII(s string) string {
+	lower := []byte(s)
+	for i, c := range lower {
+		if c >= 'A' && c <= 'Z' {
+			lower[i] = c + 'a' - 'A'
+		}
+	}
+	return string(lower)
+}
+
 // equalsCaseInsensitive compares two strings case-insensitively
 func equalsCaseInsensitive(a, b string) bool {
 	if len(a) != len(b) {
@@ -0,0 +1,330 @@
+package filesystem
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"unicode/utf16"
+)
+
+// testEntry is a file to pack into a test GPK
+type testEntry struct {
+	name       string
+	data       []byte
+	compressed bool
+}
+
+// writeTestGPK packs entries into an unencrypted GPK in the retail layout:
+// entry data, the zlib-compressed PIDX and the 32-byte signature
+func writeTestGPK(t *testing.T, path string, entries []testEntry) {
+	t.Helper()
+
+	var body, pidx bytes.Buffer
+	for _, entry := range entries {
+		stored := entry.data
+		magic := [4]byte{}
+		uncompressed := uint32(0)
+		if entry.compressed {
+			var packed bytes.Buffer
+			binary.Write(&packed, binary.LittleEndian, uint32(len(entry.data)))
+			writer := zlib.NewWriter(&packed)
+			writer.Write(entry.data)
+			writer.Close()
+			stored = packed.Bytes()
+			copy(magic[:], "DFLT")
+			uncompressed = uint32(len(entry.data))
+		}
+
+		name := utf16.Encode([]rune(entry.name))
+		binary.Write(&pidx, binary.LittleEndian, uint16(len(name)))
+		binary.Write(&pidx, binary.LittleEndian, name)
+		binary.Write(&pidx, binary.LittleEndian, uint16(0))          // SubVersion
+		binary.Write(&pidx, binary.LittleEndian, uint16(1))          // Version
+		binary.Write(&pidx, binary.LittleEndian, uint16(0))          // Zero
+		binary.Write(&pidx, binary.LittleEndian, uint32(body.Len())) // Offset
+		binary.Write(&pidx, binary.LittleEndian, uint32(len(stored)))
+		pidx.Write(magic[:])
+		binary.Write(&pidx, binary.LittleEndian, uncompressed)
+		pidx.WriteByte(0) // No compression header
+
+		body.Write(stored)
+	}
+
+	var packedPIDX bytes.Buffer
+	writer := zlib.NewWriter(&packedPIDX)
+	writer.Write(pidx.Bytes())
+	writer.Close()
+
+	body.Write(packedPIDX.Bytes())
+	body.WriteString(GPKTailerIdent0)
+	binary.Write(&body, binary.LittleEndian, uint32(packedPIDX.Len()))
+	body.WriteString(GPKTailerIdent1)
+
+	if err := os.WriteFile(path, body.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGPKExtractFile(t *testing.T) {
+	entries := []testEntry{
+		{name: "Se00/stored.ogg", data: []byte("OggS stored entry data")},
+		{name: "Event00/packed.PNG", data: bytes.Repeat([]byte("packed "), 100), compressed: true},
+	}
+	path := filepath.Join(t.TempDir(), "Test.GPK")
+	writeTestGPK(t, path, entries)
+
+	gpk, err := NewGPK(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gpk.Close()
+
+	if got := len(gpk.GetEntries()); got != len(entries) {
+		t.Fatalf("got %d entries, want %d", got, len(entries))
+	}
+	for _, want := range entries {
+		entry, ok := gpk.FindEntry(want.name)
+		if !ok {
+			t.Fatalf("entry %s not found", want.name)
+		}
+		data, err := gpk.ExtractFile(entry)
+		if err != nil {
+			t.Fatalf("%s: %v", want.name, err)
+		}
+		if !bytes.Equal(data, want.data) {
+			t.Errorf("%s: got %q, want %q", want.name, data, want.data)
+		}
+	}
+
+	if _, ok := gpk.FindEntry("se00/STORED.ogg"); !ok {
+		t.Error("entry lookup is not case-insensitive")
+	}
+}
+
+func TestGPKExtractFileConcurrent(t *testing.T) {
+	var entries []testEntry
+	for i := 0; i < 16; i++ {
+		entries = append(entries, testEntry{
+			name:       fmt.Sprintf("Voice00/%02d.ogg", i),
+			data:       bytes.Repeat([]byte{byte('a' + i)}, 4096+i),
+			compressed: i%2 == 1,
+		})
+	}
+	path := filepath.Join(t.TempDir(), "Voice00.GPK")
+	writeTestGPK(t, path, entries)
+
+	gpk, err := NewGPK(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gpk.Close()
+
+	// Entries read from several goroutines at once must not mix up data
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for round := 0; round < 200; round++ {
+				want := entries[(worker+round)%len(entries)]
+				entry, _ := gpk.FindEntry(want.name)
+				data, err := gpk.ExtractFile(entry)
+				if err != nil {
+					t.Errorf("%s: %v", want.name, err)
+					return
+				}
+				if !bytes.Equal(data, want.data) {
+					t.Errorf("%s: read corrupted data", want.name)
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+}
+
+func TestManagerOpenArchiveEntry(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "packs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	stored := bytes.Repeat([]byte("OggS voice "), 64)
+	packed := bytes.Repeat([]byte("packed "), 64)
+	writeTestGPK(t, filepath.Join(root, "packs", "Voice00.GPK"), []testEntry{
+		{name: "Voice00/stored.ogg", data: stored},
+		{name: "Voice00/packed.ogg", data: packed, compressed: true},
+	})
+
+	fs := NewManager(root)
+	if err := fs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	tests := []struct {
+		name     string
+		want     []byte
+		buffered bool
+	}{
+		{"Voice00/stored.ogg", stored, false}, // Streamed from the package
+		{"Voice00/packed.ogg", packed, true},  // Unpacked into memory
+	}
+	for _, test := range tests {
+		reader, err := fs.Open(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, ok := reader.(*ArchiveFile)
+		if !ok {
+			t.Fatalf("%s: Open returned %T, want *ArchiveFile", test.name, reader)
+		}
+		if file.Buffered() != test.buffered {
+			t.Errorf("%s: buffered = %v, want %v", test.name, file.Buffered(), test.buffered)
+		}
+
+		info, err := file.Stat()
+		if err != nil || info.Size() != int64(len(test.want)) || info.Name() != filepath.Base(test.name) {
+			t.Errorf("%s: stat = %v %v, want %d bytes", test.name, info, err, len(test.want))
+		}
+
+		// Random access, as the audio decoder seeks around the file
+		tail := make([]byte, 16)
+		if _, err := file.ReadAt(tail, int64(len(test.want)-16)); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !bytes.Equal(tail, test.want[len(test.want)-16:]) {
+			t.Errorf("%s: ReadAt got %q", test.name, tail)
+		}
+		file.Close()
+	}
+}
+
+func TestManagerOpenArchiveFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "packs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("OggS voice "), 64)
+	entries := []testEntry{{name: "Voice00/line.ogg", data: data}}
+	writeTestGPK(t, filepath.Join(root, "packs", "Voice00.GPK"), entries)
+	writeTestGPK(t, filepath.Join(root, "Extra.GPK"), entries)
+
+	fs := NewManager(root)
+	if err := fs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	tests := []struct {
+		archive  string
+		buffered bool
+	}{
+		{"Voice00.GPK", false}, // Mounted: streamed from the shared handle
+		{"Extra.GPK", true},    // Not mounted: read into memory
+	}
+	for _, test := range tests {
+		reader, err := fs.OpenArchiveFile(test.archive, "voice00/LINE.ogg")
+		if err != nil {
+			t.Fatalf("%s: %v", test.archive, err)
+		}
+		file := reader.(*ArchiveFile)
+		if file.Buffered() != test.buffered {
+			t.Errorf("%s: buffered = %v, want %v", test.archive, file.Buffered(), test.buffered)
+		}
+		got, err := io.ReadAll(file)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: read %d bytes, %v", test.archive, len(got), err)
+		}
+		file.Close()
+	}
+
+	if _, err := fs.OpenArchiveFile("Voice00.GPK", "Voice00/missing.ogg"); err == nil {
+		t.Error("missing entry opened")
+	}
+}
+
+func TestGPKFindEntryDuplicate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Event00.GPK")
+	writeTestGPK(t, path, []testEntry{
+		{name: "Event00/BG-001.PNG", data: []byte("first")},
+		{name: "EVENT00/bg-001.png", data: []byte("second")},
+	})
+
+	gpk, err := NewGPK(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gpk.Close()
+
+	// The first entry of a name wins
+	entry, ok := gpk.FindEntry("event00/BG-001.png")
+	if !ok {
+		t.Fatal("entry not found")
+	}
+	data, err := gpk.ExtractFile(entry)
+	if err != nil || string(data) != "first" {
+		t.Errorf("found %q, %v, want the first entry", data, err)
+	}
+	if _, ok := gpk.FindEntry("Event00/BG-001"); ok {
+		t.Error("partial name found")
+	}
+}
+
+func TestGPKCloseWithOpenEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Voice00.GPK")
+	data := bytes.Repeat([]byte("OggS voice "), 64)
+	writeTestGPK(t, path, []testEntry{{name: "Voice00/line.ogg", data: data}})
+
+	gpk, err := NewGPK(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := gpk.FindEntry("Voice00/line.ogg")
+	first, err := gpk.OpenEntry(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := gpk.OpenEntry(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Open entries keep streaming after the package is closed
+	if err := gpk.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(first); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("read after package Close: %d bytes, %v", len(got), err)
+	}
+
+	// The handle goes with the last entry; closed entries fail to read
+	first.Close()
+	first.Close()
+	if gpk.file == nil {
+		t.Fatal("handle closed while an entry is open")
+	}
+	if _, err := first.ReadAt(make([]byte, 4), 0); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("read of a closed entry: %v, want fs.ErrClosed", err)
+	}
+	second.Close()
+	if gpk.file != nil || gpk.readers != 0 {
+		t.Errorf("handle open after the last entry closed (%d readers)", gpk.readers)
+	}
+
+	// A closed package reopens on use
+	got, err := gpk.ExtractFile(entry)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("extract after Close: %d bytes, %v", len(got), err)
+	}
+	if err := gpk.Close(); err != nil || gpk.file != nil {
+		t.Errorf("Close with no entries open left the handle open: %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package filesystem
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +14,15 @@ import (
 type Manager struct {
 	rootDir  string
 	archives []*GPK
+
+	// Loose files take precedence over archive entries, so edited files can
+	// replace packed ones (set from game.ini UseLocalFileFirst and by hot
+	// reload)
+	looseFirst bool
+
+	// Directory of files the engine writes back, such as saved INI files.
+	// It is read before the archives and loose files.
+	overrideDir string
 }
 
 // FileInfo represents information about a file in the filesystem
@@ -41,10 +52,10 @@ func (m *Manager) Init() error {
 	err := m.findAndMountArchives()
 	if err != nil {
 		// Log the error but continue - we can still work with loose files
-		fmt.Printf("Warning: Failed to mount some archives: %v\n", err)
+		log.Printf("Warning: Failed to mount some archives: %v", err)
 	}
 
-	fmt.Printf("Filesystem initialized with %d GPK archives\n", len(m.archives))
+	log.Printf("Filesystem initialized with %d GPK archives", len(m.archives))
 	return nil
 }
 
@@ -54,7 +65,7 @@ func (m *Manager) findAndMountArchives() error {
 
 	// Check if packs directory exists
 	if _, err := os.Stat(packsDir); os.IsNotExist(err) {
-		fmt.Printf("No packs directory found at %s\n", packsDir)
+		log.Printf("No packs directory found at %s", packsDir)
 		return nil
 	}
 
@@ -72,27 +83,72 @@ func (m *Manager) findAndMountArchives() error {
 		// Try to mount the GPK file
 		gpk, mountErr := NewGPK(path)
 		if mountErr != nil {
-			fmt.Printf("Warning: Failed to mount GPK %s: %v\n", info.Name(), mountErr)
+			log.Printf("Warning: Failed to mount GPK %s: %v", info.Name(), mountErr)
 			return nil // Continue with other files
 		}
 
 		m.archives = append(m.archives, gpk)
-		fmt.Printf("Mounted GPK: %s (%d files)\n", info.Name(), len(gpk.GetEntries()))
+		log.Printf("Mounted GPK: %s (%d files)", info.Name(), len(gpk.GetEntries()))
 		return nil
 	})
 	return err
 }
 
-// Open opens a file, checking archives first, then filesystem
+// SetLooseFirst makes loose files take precedence over archive entries
+func (m *Manager) SetLooseFirst(looseFirst bool) {
+	m.looseFirst = looseFirst
+}
+
+// SetOverrideDir sets the directory files are saved to by the engine. Files
+// in it replace archive entries and loose files of the same name.
+func (m *Manager) SetOverrideDir(dir string) {
+	m.overrideDir = dir
+}
+
+// GetOverrideDir returns the override directory, or "" if none is set
+func (m *Manager) GetOverrideDir() string {
+	return m.overrideDir
+}
+
+// GetOverridePath returns where a file is saved in the override directory,
+// or "" if none is set
+func (m *Manager) GetOverridePath(filename string) string {
+	if m.overrideDir == "" {
+		return ""
+	}
+	return filepath.Join(m.overrideDir, filepath.FromSlash(strings.ReplaceAll(filename, "\\", "/")))
+}
+
+// overrideFile returns the path of a file saved in the override directory
+func (m *Manager) overrideFile(filename string) (string, bool) {
+	path := m.GetOverridePath(filename)
+	if path == "" {
+		return "", false
+	}
+	info, err := os.Stat(path)
+	return path, err == nil && !info.IsDir()
+}
+
+// Open opens a file, checking the override directory first, then archives,
+// then filesystem (loose files first when SetLooseFirst is on). Archive
+// entries are returned as an *ArchiveFile, loose files as an *os.File; both
+// support ReadAt and Stat.
 func (m *Manager) Open(filename string) (io.ReadCloser, error) {
+	if path, ok := m.overrideFile(filename); ok {
+		return os.Open(path)
+	}
+	if m.looseFirst && m.isLooseFile(filename) {
+		return os.Open(m.getFullPath(filename))
+	}
+
 	// First check mounted GPK archives
 	for _, gpk := range m.archives {
 		if entry, found := gpk.FindEntry(filename); found {
-			data, err := gpk.ExtractFile(entry)
+			file, err := gpk.OpenEntry(entry)
 			if err != nil {
 				continue // Try next archive or filesystem
 			}
-			return &ByteReadCloser{data: data}, nil
+			return file, nil
 		}
 	}
 
@@ -106,28 +162,64 @@ func (m *Manager) Open(filename string) (io.ReadCloser, error) {
 	return file, nil
 }
 
-// ByteReadCloser wraps a byte slice to implement io.ReadCloser
-type ByteReadCloser struct {
-	data   []byte
-	offset int
-}
+// ReadArchiveFile reads an entry from a specific GPK package, bypassing the
+// normal lookup order. The package is matched by file name against mounted
+// archives, or opened from the path if it is not mounted.
+func (m *Manager) ReadArchiveFile(archive, entry string) ([]byte, error) {
+	for _, gpk := range m.archives {
+		if !equalsCaseInsensitive(filepath.Base(gpk.fileName), filepath.Base(archive)) {
+			continue
+		}
+		if found, ok := gpk.FindEntry(entry); ok {
+			return gpk.ExtractFile(found)
+		}
+		return nil, fmt.Errorf("entry %s not found in %s", entry, archive)
+	}
 
-func (b *ByteReadCloser) Read(p []byte) (n int, err error) {
-	if b.offset >= len(b.data) {
-		return 0, io.EOF
+	gpk, err := NewGPK(m.getFullPath(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", archive, err)
 	}
+	defer gpk.Close()
 
-	n = copy(p, b.data[b.offset:])
-	b.offset += n
-	return n, nil
+	found, ok := gpk.FindEntry(entry)
+	if !ok {
+		return nil, fmt.Errorf("entry %s not found in %s", entry, archive)
+	}
+	return gpk.ExtractFile(found)
 }
 
-func (b *ByteReadCloser) Close() error {
-	return nil
+// OpenArchiveFile opens an entry from a specific GPK package for streaming,
+// bypassing the normal lookup order like ReadArchiveFile. Entries of a
+// package that is not mounted are read into memory.
+func (m *Manager) OpenArchiveFile(archive, entry string) (io.ReadCloser, error) {
+	for _, gpk := range m.archives {
+		if !equalsCaseInsensitive(filepath.Base(gpk.fileName), filepath.Base(archive)) {
+			continue
+		}
+		if found, ok := gpk.FindEntry(entry); ok {
+			return gpk.OpenEntry(found)
+		}
+		return nil, fmt.Errorf("entry %s not found in %s", entry, archive)
+	}
+
+	data, err := m.ReadArchiveFile(archive, entry)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveFile{
+		SectionReader: io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data))),
+		name:          entry,
+		buffered:      true,
+	}, nil
 }
 
-// Exists checks if a file exists in archives or filesystem
+// Exists checks if a file exists in the override directory, archives or filesystem
 func (m *Manager) Exists(filename string) bool {
+	if _, ok := m.overrideFile(filename); ok {
+		return true
+	}
+
 	// Check mounted GPK archives first
 	for _, gpk := range m.archives {
 		if _, found := gpk.FindEntry(filename); found {
@@ -161,6 +253,12 @@ func (m *Manager) getFullPath(filename string) string {
 	return filepath.Join(m.rootDir, cleanFilename)
 }
 
+// GetLoosePath returns where a file is stored as a loose file under the root
+// directory, for writing edited files back
+func (m *Manager) GetLoosePath(filename string) string {
+	return m.getFullPath(filename)
+}
+
 // ListDirectory lists files in a directory
 func (m *Manager) ListDirectory(dirPath string) ([]string, error) {
 	fullPath := m.getFullPath(dirPath)
@@ -215,3 +313,28 @@ func (m *Manager) ListFiles() []FileInfo {
 func (m *Manager) GetArchiveCount() int {
 	return len(m.archives)
 }
+
+// NormalizeName appends the extension implied by a script asset's top-level
+// folder (matches Qt QFileSystem::normalize_name). JRS files reference assets
+// without extensions, e.g. "Voice03/03-A1/03-A1-E01/03-A1-E01-0030".
+func NormalizeName(name string) string {
+	if filepath.Ext(name) != "" {
+		return name
+	}
+
+	pkg := name
+	if idx := strings.IndexAny(name, "/\\"); idx >= 0 {
+		pkg = name[:idx]
+	}
+
+	switch {
+	case strings.HasPrefix(pkg, "SysSe"), strings.HasPrefix(pkg, "Se"), strings.HasPrefix(pkg, "Voice"):
+		return name + ".ogg"
+	case strings.HasPrefix(pkg, "BGM"):
+		return name + "_loop.ogg"
+	case strings.HasPrefix(pkg, "Event"):
+		return name + ".PNG"
+	default:
+		return name
+	}
+}
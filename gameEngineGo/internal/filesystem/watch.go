@@ -0,0 +1,165 @@
+package filesystem
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchedFile is a loose file under one of the watched base directories
+type watchedFile struct {
+	base int    // Index in Watcher.bases
+	name string // Path relative to the base, with forward slashes
+}
+
+// Watcher polls the loose files in some directories under the root and the
+// override directory and reports the ones that were added or changed. GPK
+// archives are not watched.
+type Watcher struct {
+	bases    []string // The root, then the override directory if set
+	dirs     []string // Watched directories, relative to each base
+	interval time.Duration
+	files    map[watchedFile]fileStamp
+	changes  chan []string
+	stop     chan struct{}
+	done     sync.WaitGroup
+}
+
+// Watch starts watching the loose files in dirs (relative to the root
+// directory, e.g. "Script"), scanning every interval. The same directories
+// are watched in the override directory, whose files replace the game's;
+// changes there are reported under the game file names. Close stops it.
+func (m *Manager) Watch(interval time.Duration, dirs []string) *Watcher {
+	w := &Watcher{
+		bases:    []string{m.rootDir},
+		dirs:     dirs,
+		interval: interval,
+		changes:  make(chan []string, 16),
+		stop:     make(chan struct{}),
+	}
+	if m.overrideDir != "" && !sameDir(m.overrideDir, m.rootDir) {
+		w.bases = append(w.bases, m.overrideDir)
+	}
+	w.files = w.scan()
+
+	w.done.Add(1)
+	go w.run()
+
+	log.Printf("Watching %d loose files in %s under %s", len(w.files), strings.Join(dirs, ", "), strings.Join(w.bases, ", "))
+	return w
+}
+
+// sameDir returns whether two paths name the same directory
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// Changes delivers the paths (relative to the root, with forward slashes)
+// changed since the previous scan
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close stops the watcher
+func (w *Watcher) Close() {
+	close(w.stop)
+	w.done.Wait()
+}
+
+// run scans the tree until stopped
+func (w *Watcher) run() {
+	defer w.done.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		files := w.scan()
+		var changed []string
+		seen := make(map[string]bool)
+		for file, stamp := range files {
+			if previous, exists := w.files[file]; (!exists || previous != stamp) && !seen[file.name] {
+				seen[file.name] = true
+				changed = append(changed, file.name)
+			}
+		}
+		w.files = files
+
+		if len(changed) == 0 {
+			continue
+		}
+		select {
+		case w.changes <- changed:
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// scan records the modification time and size of every loose file in the
+// watched directories
+func (w *Watcher) scan() map[watchedFile]fileStamp {
+	files := make(map[watchedFile]fileStamp)
+	for base := range w.bases {
+		for _, dir := range w.dirs {
+			w.scanDir(base, filepath.Join(w.bases[base], filepath.FromSlash(dir)), files)
+		}
+	}
+	return files
+}
+
+// scanDir records the loose files of one directory tree under a base
+func (w *Watcher) scanDir(base int, dir string, files map[watchedFile]fileStamp) {
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Files can vanish while scanning
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			// Skip hidden directories (e.g. .git)
+			if path != dir && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(name, ".") || strings.EqualFold(filepath.Ext(name), ".gpk") {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		relative, err := filepath.Rel(w.bases[base], path)
+		if err != nil {
+			return nil
+		}
+		files[watchedFile{base: base, name: filepath.ToSlash(relative)}] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+}
+
+// isLooseFile returns whether a file exists outside the archives
+func (m *Manager) isLooseFile(filename string) bool {
+	info, err := os.Stat(m.getFullPath(filename))
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,86 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcherWatchesOnlyGivenDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"Script/00", "Event00/TEST", "Voice00", "Script/.git"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewManager(root)
+	watcher := fs.Watch(10*time.Millisecond, []string{"Script", "Event00", "Missing"})
+	defer watcher.Close()
+
+	files := []string{
+		"Script/00/00-00-A00.jrs",
+		"Event00/TEST/BG-001.PNG",
+		"Voice00/line.ogg",      // Not watched
+		"Script/.git/HEAD",      // Hidden directory
+		"Event00/TEST/.tmp.png", // Hidden file
+		"loose.txt",             // Root is not watched
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(file)), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case changed := <-watcher.Changes():
+		sort.Strings(changed)
+		want := []string{"Event00/TEST/BG-001.PNG", "Script/00/00-00-A00.jrs"}
+		if strings.Join(changed, ",") != strings.Join(want, ",") {
+			t.Errorf("changed %v, want %v", changed, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes reported")
+	}
+}
+
+func TestWatcherWatchesOverrideDir(t *testing.T) {
+	root, override := t.TempDir(), t.TempDir()
+	for _, base := range []string{root, override} {
+		if err := os.MkdirAll(filepath.Join(base, "Event00"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "Event00", "BG-001.PNG"), []byte("game"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewManager(root)
+	fs.SetOverrideDir(override)
+	watcher := fs.Watch(10*time.Millisecond, []string{"Event00"})
+	defer watcher.Close()
+
+	// A file saved over the game's is reported under the game file name
+	if err := os.WriteFile(filepath.Join(override, "Event00", "BG-001.PNG"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changed := <-watcher.Changes():
+		if strings.Join(changed, ",") != "Event00/BG-001.PNG" {
+			t.Errorf("changed %v, want Event00/BG-001.PNG", changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes reported")
+	}
+
+	// Unchanged files in both directories are not reported again
+	select {
+	case changed := <-watcher.Changes():
+		t.Errorf("unchanged files reported: %v", changed)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
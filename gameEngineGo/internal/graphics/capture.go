@@ -0,0 +1,177 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// FrameCapture writes composited frames to PNG files, either a single
+// screenshot on request or every frame while a burst is running
+type FrameCapture struct {
+	dir string
+
+	screenshotPending bool
+
+	bursting   bool
+	burstDir   string
+	burstFrame int
+
+	// PNG encoding runs off the game loop on a fixed pool of encoders
+	startEncoders sync.Once
+	jobs          chan encodeJob
+	pending       sync.WaitGroup
+}
+
+// Frame encoding limits: a long burst blocks the game loop once the queue is
+// full instead of holding thousands of frames in memory
+const (
+	captureEncoders  = 4
+	captureQueueSize = 16
+)
+
+// encodeJob is a frame waiting to be written
+type encodeJob struct {
+	pixels *image.RGBA
+	path   string
+}
+
+// NewFrameCapture creates a frame capture writing into dir
+func NewFrameCapture(dir string) *FrameCapture {
+	return &FrameCapture{dir: dir}
+}
+
+// SetDirectory changes the output directory
+func (fc *FrameCapture) SetDirectory(dir string) {
+	fc.dir = dir
+}
+
+// GetDirectory returns the output directory
+func (fc *FrameCapture) GetDirectory() string {
+	return fc.dir
+}
+
+// RequestScreenshot captures the next composited frame
+func (fc *FrameCapture) RequestScreenshot() {
+	fc.screenshotPending = true
+}
+
+// StartBurst starts dumping every frame to numbered PNGs in a new
+// subdirectory named after the burst
+func (fc *FrameCapture) StartBurst(name string) error {
+	if fc.bursting {
+		return fmt.Errorf("burst capture already running in %s", fc.burstDir)
+	}
+
+	dir := filepath.Join(fc.dir, fmt.Sprintf("%s_%s", name, time.Now().Format("20060102_150405")))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create burst directory: %w", err)
+	}
+
+	fc.bursting = true
+	fc.burstDir = dir
+	fc.burstFrame = 0
+
+	log.Printf("Burst capture started: %s", dir)
+	return nil
+}
+
+// StopBurst stops the running burst and returns the number of frames written
+func (fc *FrameCapture) StopBurst() int {
+	if !fc.bursting {
+		return 0
+	}
+
+	fc.bursting = false
+	log.Printf("Burst capture stopped: %d frames in %s", fc.burstFrame, fc.burstDir)
+	return fc.burstFrame
+}
+
+// IsBursting returns whether a burst capture is running
+func (fc *FrameCapture) IsBursting() bool {
+	return fc.bursting
+}
+
+// Capture handles pending screenshots and bursts for a composited frame.
+// Must be called from Draw, after the frame is fully composed.
+func (fc *FrameCapture) Capture(frame *ebiten.Image) {
+	if !fc.screenshotPending && !fc.bursting {
+		return
+	}
+
+	pixels := readFrame(frame)
+
+	if fc.screenshotPending {
+		fc.screenshotPending = false
+		name := fmt.Sprintf("screenshot_%s.png", time.Now().Format("20060102_150405.000"))
+		fc.encode(pixels, filepath.Join(fc.dir, name))
+	}
+
+	if fc.bursting {
+		fc.burstFrame++
+		fc.encode(pixels, filepath.Join(fc.burstDir, fmt.Sprintf("frame_%06d.png", fc.burstFrame)))
+	}
+}
+
+// Wait blocks until all queued PNG files are written
+func (fc *FrameCapture) Wait() {
+	fc.pending.Wait()
+}
+
+// encode queues pixels to be written to a PNG file by the encoder pool,
+// blocking while the queue is full
+func (fc *FrameCapture) encode(pixels *image.RGBA, path string) {
+	fc.startEncoders.Do(func() {
+		fc.jobs = make(chan encodeJob, captureQueueSize)
+		for i := 0; i < captureEncoders; i++ {
+			go fc.encoder()
+		}
+	})
+
+	fc.pending.Add(1)
+	fc.jobs <- encodeJob{pixels: pixels, path: path}
+}
+
+// encoder writes queued frames until the program exits
+func (fc *FrameCapture) encoder() {
+	for job := range fc.jobs {
+		if err := SavePNG(job.pixels, job.path); err != nil {
+			log.Printf("Warning: failed to save frame: %v", err)
+		} else {
+			log.Printf("Saved frame: %s", job.path)
+		}
+		fc.pending.Done()
+	}
+}
+
+// readFrame copies an Ebiten image into CPU memory
+func readFrame(frame *ebiten.Image) *image.RGBA {
+	pixels := image.NewRGBA(frame.Bounds())
+	frame.ReadPixels(pixels.Pix)
+	return pixels
+}
+
+// SavePNG writes an image to a PNG file, creating parent directories
+func SavePNG(img image.Image, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return nil
+}
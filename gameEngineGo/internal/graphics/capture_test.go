@@ -0,0 +1,29 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFrameCaptureEncodesEveryQueuedFrame(t *testing.T) {
+	dir := t.TempDir()
+	capture := NewFrameCapture(dir)
+
+	// More frames than the queue holds, so encode has to wait for the pool
+	frames := captureQueueSize*2 + captureEncoders
+	for i := 0; i < frames; i++ {
+		capture.encode(image.NewRGBA(image.Rect(0, 0, 4, 4)), filepath.Join(dir, fmt.Sprintf("frame_%06d.png", i)))
+	}
+	capture.Wait()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != frames {
+		t.Errorf("wrote %d frames, want %d", len(entries), frames)
+	}
+}
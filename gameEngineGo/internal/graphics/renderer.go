@@ -6,28 +6,30 @@ import (
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
+
+	"school-days-engine/internal/render"
 )
 
 // FileSystemInterface defines the interface for filesystem operations
-type FileSystemInterface interface {
-	ReadFile(filename string) ([]byte, error)
-	Exists(filename string) bool
-}
+type FileSystemInterface = render.FileSystemInterface
 
-// Layer constants based on the original C++ engine
+// Renderer implements the render.Backend compositor interface
+var _ render.Backend = (*Renderer)(nil)
+
+// Layer constants based on the original C++ engine (shared with the software renderer)
 const (
-	LayerBG          = 0
-	LayerBGOverlay0  = 1
-	LayerBGOverlay1  = 2
-	LayerBGOverlay2  = 3
-	LayerTitleBase   = 4
-	LayerMenu        = 5
-	LayerMenuOverlay = 6
-	LayerSysBase     = 7
-	LayerDlg         = 8
-	LayerDlgOverlay  = 9
-	LayerOverlay     = 10
-	LayersCount      = 11
+	LayerBG          = render.LayerBG
+	LayerBGOverlay0  = render.LayerBGOverlay0
+	LayerBGOverlay1  = render.LayerBGOverlay1
+	LayerBGOverlay2  = render.LayerBGOverlay2
+	LayerTitleBase   = render.LayerTitleBase
+	LayerMenu        = render.LayerMenu
+	LayerMenuOverlay = render.LayerMenuOverlay
+	LayerSysBase     = render.LayerSysBase
+	LayerDlg         = render.LayerDlg
+	LayerDlgOverlay  = render.LayerDlgOverlay
+	LayerOverlay     = render.LayerOverlay
+	LayersCount      = render.LayersCount
 )
 
 // LayerState represents the state of a layer
@@ -49,6 +51,7 @@ type Renderer struct {
 	// Layer textures
 	layers      [LayersCount]*ebiten.Image
 	layerStates [LayersCount]LayerState
+	layerFiles  [LayersCount]string // Texture names, for debugging
 
 	// Fade effect
 	fadeTexture *ebiten.Image
@@ -60,6 +63,9 @@ type Renderer struct {
 
 	// Filesystem interface for loading assets
 	filesystem FileSystemInterface
+
+	// Screenshot and burst frame capture
+	capture *FrameCapture
 }
 
 // NewRenderer creates a new graphics renderer
@@ -72,6 +78,7 @@ func NewRenderer(width, height int, filesystem FileSystemInterface) *Renderer {
 
 	// Initialize texture manager
 	renderer.textureManager = NewTextureManager(filesystem, width, height)
+	renderer.capture = NewFrameCapture("./screenshots")
 
 	return renderer
 }
@@ -114,6 +121,7 @@ func (r *Renderer) UnloadTexture(layer int) {
 
 	r.layers[layer] = nil
 	r.layerStates[layer].Visible = false
+	r.layerFiles[layer] = ""
 
 	log.Printf("Unloaded texture from layer %d", layer)
 }
@@ -222,6 +230,13 @@ func (r *Renderer) GetScreenSize() (int, int) {
 	return r.screenWidth, r.screenHeight
 }
 
+// SetHeadless stops decoding textures for a run that never draws: images
+// made outside ebiten's game loop are only uploaded and released by it.
+// Layers keep their files and states.
+func (r *Renderer) SetHeadless(headless bool) {
+	r.textureManager.cache.headless = headless
+}
+
 // GetTextureManager returns the texture manager instance
 func (r *Renderer) GetTextureManager() *TextureManager {
 	return r.textureManager
@@ -240,10 +255,74 @@ func (r *Renderer) LoadTextureFromCache(filename string, layer int) error {
 
 	r.layers[layer] = texture
 	r.layerStates[layer].Visible = true
+	r.layerFiles[layer] = filename
 	return nil
 }
 
+// ReloadTexture reads a texture file again and updates the layers showing it
+// (for hot reload). Returns whether the texture was in use.
+func (r *Renderer) ReloadTexture(filename string) bool {
+	used := r.textureManager.Invalidate(filename)
+
+	key := sameTextureKey(filename)
+	for layer, file := range r.layerFiles {
+		if file != "" && sameTextureKey(file) == key {
+			r.reloadLayer(layer)
+			used = true
+		}
+	}
+	return used
+}
+
+// ReloadTextures drops the texture cache and reloads every layer's texture
+func (r *Renderer) ReloadTextures() {
+	r.textureManager.InvalidateAll()
+	for layer, file := range r.layerFiles {
+		if file != "" {
+			r.reloadLayer(layer)
+		}
+	}
+	log.Println("Textures reloaded")
+}
+
+// reloadLayer loads a layer's texture again, keeping the layer state. The
+// old image was disposed, so a layer that fails to reload is left empty
+// until the next reload.
+func (r *Renderer) reloadLayer(layer int) {
+	state := r.layerStates[layer]
+	if err := r.LoadTextureFromCache(r.layerFiles[layer], layer); err != nil {
+		log.Printf("Warning: failed to reload layer %d: %v", layer, err)
+		r.layers[layer] = nil
+		return
+	}
+	r.layerStates[layer] = state
+}
+
+// GetLayerInfo returns the texture name and state of a layer ("" if empty)
+func (r *Renderer) GetLayerInfo(layer int) (string, LayerState) {
+	if layer < 0 || layer >= LayersCount {
+		return "", LayerState{}
+	}
+	return r.layerFiles[layer], r.layerStates[layer]
+}
+
 // ClearTextureCache clears the texture cache
 func (r *Renderer) ClearTextureCache() {
 	r.textureManager.ClearCache()
 }
+
+// GetFrameCapture returns the screenshot and burst capture helper
+func (r *Renderer) GetFrameCapture() *FrameCapture {
+	return r.capture
+}
+
+// RequestScreenshot saves the next composited frame as a PNG
+func (r *Renderer) RequestScreenshot() {
+	r.capture.RequestScreenshot()
+}
+
+// CaptureFrame hands the fully composited frame (layers, fades and menus)
+// to the frame capture; called once per Draw
+func (r *Renderer) CaptureFrame(frame *ebiten.Image) {
+	r.capture.Capture(frame)
+}
@@ -0,0 +1,32 @@
+package graphics
+
+import (
+	"errors"
+	"testing"
+)
+
+// missingFS is a filesystem without any files
+type missingFS struct{}
+
+func (missingFS) ReadFile(string) ([]byte, error) { return nil, errors.New("not found") }
+func (missingFS) Exists(string) bool              { return false }
+
+func TestHeadlessTextures(t *testing.T) {
+	r := NewRenderer(1280, 720, missingFS{})
+	if err := r.Init(); err != nil {
+		t.Fatal(err)
+	}
+	r.SetHeadless(true)
+
+	// Nothing is read or decoded, but the layer still shows the file
+	if err := r.LoadTexture("Event00/TEST/BG-001", LayerBG); err != nil {
+		t.Fatal(err)
+	}
+	file, state := r.GetLayerInfo(LayerBG)
+	if file != "Event00/TEST/BG-001" || !state.Visible {
+		t.Errorf("layer shows %q (visible %v)", file, state.Visible)
+	}
+	if size := r.layers[LayerBG].Bounds().Size(); size.X != 1 || size.Y != 1 {
+		t.Errorf("headless texture is %v, want a 1x1 stand-in", size)
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
+
+	"school-days-engine/internal/render"
 )
 
 // TextureCache manages texture loading and caching
@@ -19,6 +21,7 @@ type TextureCache struct {
 	filesystem FileSystemInterface
 	cache      map[string]*ebiten.Image
 	mutex      sync.RWMutex
+	headless   bool // Textures are stand-ins, see Renderer.SetHeadless
 }
 
 // NewTextureCache creates a new texture cache
@@ -32,10 +35,7 @@ func NewTextureCache(filesystem FileSystemInterface) *TextureCache {
 // LoadTexture loads a texture from file or cache
 func (tc *TextureCache) LoadTexture(filename string) (*ebiten.Image, error) {
 	// Normalize filename - add .png extension if not present
-	normalizedName := strings.ToLower(filename)
-	if !strings.Contains(normalizedName, ".") {
-		normalizedName += ".png"
-	}
+	normalizedName := render.NormalizeTextureName(filename)
 
 	// Check cache first
 	tc.mutex.RLock()
@@ -62,6 +62,10 @@ func (tc *TextureCache) LoadTexture(filename string) (*ebiten.Image, error) {
 
 // loadFromFile loads texture data from filesystem
 func (tc *TextureCache) loadFromFile(filename string) (*ebiten.Image, error) {
+	if tc.headless {
+		return ebiten.NewImage(1, 1), nil
+	}
+
 	// Try to load from filesystem (GPK or regular file)
 	data, err := tc.filesystem.ReadFile(filename)
 	if err != nil {
@@ -81,10 +85,7 @@ func (tc *TextureCache) loadFromFile(filename string) (*ebiten.Image, error) {
 
 // GetTexture returns a cached texture if available
 func (tc *TextureCache) GetTexture(filename string) *ebiten.Image {
-	normalizedName := strings.ToLower(filename)
-	if !strings.Contains(normalizedName, ".") {
-		normalizedName += ".png"
-	}
+	normalizedName := render.NormalizeTextureName(filename)
 
 	tc.mutex.RLock()
 	defer tc.mutex.RUnlock()
@@ -108,6 +109,42 @@ func (tc *TextureCache) ClearCache() {
 	log.Println("Texture cache cleared")
 }
 
+// Invalidate drops a texture from the cache and disposes it, so the next
+// load reads the file again. Layers showing it must load it again before
+// they are drawn (see Renderer.ReloadTexture).
+func (tc *TextureCache) Invalidate(filename string) bool {
+	key := sameTextureKey(filename)
+
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	found := false
+	for name, texture := range tc.cache {
+		if sameTextureKey(name) == key {
+			if texture != nil {
+				texture.Dispose()
+			}
+			delete(tc.cache, name)
+			found = true
+		}
+	}
+	return found
+}
+
+// InvalidateAll drops and disposes every texture in the cache, like
+// ClearCache; layers must load their textures again before they are drawn
+func (tc *TextureCache) InvalidateAll() {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	for _, texture := range tc.cache {
+		if texture != nil {
+			texture.Dispose()
+		}
+	}
+	tc.cache = make(map[string]*ebiten.Image)
+}
+
 // CacheSize returns the number of cached textures
 func (tc *TextureCache) CacheSize() int {
 	tc.mutex.RLock()
@@ -163,6 +200,23 @@ func (tm *TextureManager) LoadTexture(filename string) (*ebiten.Image, error) {
 	return texture, nil
 }
 
+// Preload decodes a texture into the cache without assigning it to a layer.
+// Safe to call from a background goroutine: the cache is locked and GPK
+// entries are read with ReadAt.
+func (tm *TextureManager) Preload(filename string) error {
+	if tm.cache.GetTexture(filename) != nil {
+		return nil
+	}
+
+	_, err := tm.cache.LoadTexture(filename)
+	return err
+}
+
+// IsCached returns true if the texture is already decoded
+func (tm *TextureManager) IsCached(filename string) bool {
+	return tm.cache.GetTexture(filename) != nil
+}
+
 // LoadTextureToLayer loads a texture directly to a renderer layer
 func (tm *TextureManager) LoadTextureToLayer(renderer *Renderer, filename string, layer int) error {
 	if layer < 0 || layer >= LayersCount {
@@ -176,6 +230,7 @@ func (tm *TextureManager) LoadTextureToLayer(renderer *Renderer, filename string
 
 	renderer.layers[layer] = texture
 	renderer.layerStates[layer].Visible = true
+	renderer.layerFiles[layer] = filename
 
 	log.Printf("Loaded texture %s to layer %d", filename, layer)
 	return nil
@@ -196,21 +251,8 @@ func (tm *TextureManager) createPlaceholderTexture(filename string) *ebiten.Imag
 	img := ebiten.NewImage(tm.screenWidth, tm.screenHeight)
 
 	// Create different colors based on filename or use default
-	var fillColor [4]uint8
-	lowerName := strings.ToLower(filename)
-
-	switch {
-	case strings.Contains(lowerName, "bg") || strings.Contains(lowerName, "background"):
-		fillColor = [4]uint8{50, 50, 100, 255} // Dark blue
-	case strings.Contains(lowerName, "title"):
-		fillColor = [4]uint8{100, 50, 50, 255} // Dark red
-	case strings.Contains(lowerName, "menu"):
-		fillColor = [4]uint8{50, 100, 50, 255} // Dark green
-	case strings.Contains(lowerName, "chip"):
-		fillColor = [4]uint8{100, 100, 50, 255} // Dark yellow
-	default:
-		fillColor = [4]uint8{80, 80, 80, 255} // Gray
-	}
+	fill := render.PlaceholderColor(filename)
+	fillColor := [4]uint8{fill.R, fill.G, fill.B, fill.A}
 	solidTexture := tm.cache.CreateSolidTexture(tm.screenWidth, tm.screenHeight, fillColor)
 	img.DrawImage(solidTexture, &ebiten.DrawImageOptions{})
 
@@ -222,7 +264,23 @@ func (tm *TextureManager) ClearCache() {
 	tm.cache.ClearCache()
 }
 
+// Invalidate drops and disposes a cached texture
+func (tm *TextureManager) Invalidate(filename string) bool {
+	return tm.cache.Invalidate(filename)
+}
+
+// InvalidateAll drops and disposes every cached texture
+func (tm *TextureManager) InvalidateAll() {
+	tm.cache.InvalidateAll()
+}
+
 // GetCacheSize returns the number of cached textures
 func (tm *TextureManager) GetCacheSize() int {
 	return tm.cache.CacheSize()
 }
+
+// sameTextureKey normalizes a texture name for comparing script names with
+// file paths, which may differ in case and path separators
+func sameTextureKey(filename string) string {
+	return strings.ReplaceAll(render.NormalizeTextureName(filename), "\\", "/")
+}
@@ -0,0 +1,115 @@
+package graphics
+
+import (
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Scaling modes for presenting the logical screen in a larger window
+const (
+	ScaleSmooth  = iota // Fit the window, linear filtering
+	ScaleInteger        // Largest whole multiple that fits, nearest filtering
+)
+
+// ParseScaleMode converts a settings value ("smooth" or "integer") to a scaling mode
+func ParseScaleMode(name string) int {
+	if strings.EqualFold(name, "integer") {
+		return ScaleInteger
+	}
+	return ScaleSmooth
+}
+
+// ScaleModeName returns the settings name of a scaling mode
+func ScaleModeName(mode int) string {
+	if mode == ScaleInteger {
+		return "integer"
+	}
+	return "smooth"
+}
+
+// Viewport maps the fixed logical screen onto the window, keeping the aspect
+// ratio with letterbox (horizontal) or pillarbox (vertical) bars
+type Viewport struct {
+	logicalWidth  int
+	logicalHeight int
+	outsideWidth  int
+	outsideHeight int
+	mode          int
+
+	scale   float64
+	offsetX float64
+	offsetY float64
+}
+
+// NewViewport creates a viewport for the given logical resolution
+func NewViewport(logicalWidth, logicalHeight, mode int) *Viewport {
+	v := &Viewport{
+		logicalWidth:  logicalWidth,
+		logicalHeight: logicalHeight,
+		mode:          mode,
+	}
+	v.Resize(logicalWidth, logicalHeight)
+	return v
+}
+
+// Resize recomputes scale and bar offsets for a new window size
+func (v *Viewport) Resize(outsideWidth, outsideHeight int) {
+	if outsideWidth <= 0 || outsideHeight <= 0 {
+		return
+	}
+	v.outsideWidth = outsideWidth
+	v.outsideHeight = outsideHeight
+
+	scaleX := float64(outsideWidth) / float64(v.logicalWidth)
+	scaleY := float64(outsideHeight) / float64(v.logicalHeight)
+	v.scale = math.Min(scaleX, scaleY)
+
+	// Integer scaling only when the window is at least the logical size
+	if v.mode == ScaleInteger && v.scale >= 1 {
+		v.scale = math.Floor(v.scale)
+	}
+
+	v.offsetX = math.Floor((float64(outsideWidth) - float64(v.logicalWidth)*v.scale) / 2)
+	v.offsetY = math.Floor((float64(outsideHeight) - float64(v.logicalHeight)*v.scale) / 2)
+}
+
+// SetMode changes the scaling mode
+func (v *Viewport) SetMode(mode int) {
+	v.mode = mode
+	v.Resize(v.outsideWidth, v.outsideHeight)
+}
+
+// GetMode returns the scaling mode
+func (v *Viewport) GetMode() int {
+	return v.mode
+}
+
+// GetScale returns the current logical-to-window scale factor
+func (v *Viewport) GetScale() float64 {
+	return v.scale
+}
+
+// ToLogical converts window coordinates (e.g. the cursor) to logical screen coordinates
+func (v *Viewport) ToLogical(x, y int) (float64, float64) {
+	if v.scale == 0 {
+		return float64(x), float64(y)
+	}
+	return (float64(x) - v.offsetX) / v.scale, (float64(y) - v.offsetY) / v.scale
+}
+
+// Present draws the logical screen into the window with bars around it
+func (v *Viewport) Present(window, logical *ebiten.Image) {
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(v.scale, v.scale)
+	opts.GeoM.Translate(v.offsetX, v.offsetY)
+
+	if v.mode == ScaleInteger {
+		opts.Filter = ebiten.FilterNearest
+	} else {
+		opts.Filter = ebiten.FilterLinear
+	}
+
+	window.DrawImage(logical, opts)
+}
@@ -0,0 +1,39 @@
+package graphics
+
+import "testing"
+
+func TestToLogical(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int // Window size for a 1280x720 logical screen
+		mode          int
+		x, y          int
+		wantX, wantY  float64
+	}{
+		{"same size", 1280, 720, ScaleSmooth, 100, 200, 100, 200},
+		{"pillarbox left edge", 1920, 720, ScaleSmooth, 320, 0, 0, 0},
+		{"pillarbox right edge", 1920, 720, ScaleSmooth, 1600, 720, 1280, 720},
+		{"letterbox top edge", 1280, 1080, ScaleSmooth, 640, 180, 640, 0},
+		{"letterbox bottom edge", 1280, 1080, ScaleSmooth, 640, 900, 640, 720},
+		{"left bar", 1920, 720, ScaleSmooth, 100, 360, -220, 360},
+		{"right bar", 1920, 720, ScaleSmooth, 1700, 360, 1380, 360},
+		{"top bar", 1280, 1080, ScaleSmooth, 640, 20, 640, -160},
+		{"non-integer scale", 1920, 1080, ScaleSmooth, 960, 540, 640, 360},
+		{"non-integer scale with bars", 1000, 720, ScaleSmooth, 500, 78, 640, 0},
+		{"downscaled", 640, 360, ScaleSmooth, 320, 180, 640, 360},
+		{"integer scale with bars", 1920, 1080, ScaleInteger, 320, 180, 0, 0},
+		{"integer scale double", 2560, 1440, ScaleInteger, 1280, 720, 640, 360},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewViewport(1280, 720, test.mode)
+			v.Resize(test.width, test.height)
+
+			x, y := v.ToLogical(test.x, test.y)
+			if x != test.wantX || y != test.wantY {
+				t.Errorf("ToLogical(%d, %d) = (%g, %g), want (%g, %g)", test.x, test.y, x, y, test.wantX, test.wantY)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package input
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Action is a logical input that game code queries instead of raw keys
+type Action int
+
+// Input actions
+const (
+	ActionAdvance Action = iota
+	ActionBack
+	ActionConfirm
+	ActionUp
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionSkip
+	ActionSkipToggle
+	ActionAuto
+	ActionHideUI
+	ActionQuickSave
+	ActionQuickLoad
+	ActionScreenshot
+	ActionFullscreen
+
+	// Developer actions, for the debug overlay and console
+	ActionDebugOverlay
+	ActionConsole
+	ActionConsoleSubmit
+	ActionConsoleErase
+	ActionConsoleCancel
+	ActionsCount
+)
+
+// GameActionsCount is the number of game actions. The developer actions after
+// them are read live even while input is suspended or replayed, are never
+// recorded and are not listed on the controls screen.
+const GameActionsCount = ActionDebugOverlay
+
+// actionNames are the action names used in the settings file
+var actionNames = [ActionsCount]string{
+	ActionAdvance:    "advance",
+	ActionBack:       "back",
+	ActionConfirm:    "confirm",
+	ActionUp:         "up",
+	ActionDown:       "down",
+	ActionLeft:       "left",
+	ActionRight:      "right",
+	ActionSkip:       "skip",
+	ActionSkipToggle: "skip_toggle",
+	ActionAuto:       "auto",
+	ActionHideUI:     "hide_ui",
+	ActionQuickSave:  "quick_save",
+	ActionQuickLoad:  "quick_load",
+	ActionScreenshot: "screenshot",
+	ActionFullscreen: "fullscreen",
+
+	ActionDebugOverlay:  "debug_overlay",
+	ActionConsole:       "console",
+	ActionConsoleSubmit: "console_submit",
+	ActionConsoleErase:  "console_erase",
+	ActionConsoleCancel: "console_cancel",
+}
+
+// String returns the settings name of an action
+func (a Action) String() string {
+	if a < 0 || a >= ActionsCount {
+		return fmt.Sprintf("action(%d)", int(a))
+	}
+	return actionNames[a]
+}
+
+// MarshalText implements encoding.TextMarshaler using the settings name
+func (a Action) MarshalText() ([]byte, error) {
+	if a < 0 || a >= ActionsCount {
+		return nil, fmt.Errorf("invalid action %d", int(a))
+	}
+	return []byte(actionNames[a]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (a *Action) UnmarshalText(text []byte) error {
+	action, ok := ParseAction(string(text))
+	if !ok {
+		return fmt.Errorf("unknown action %q", text)
+	}
+	*a = action
+	return nil
+}
+
+// ParseAction converts a settings name to an action
+func ParseAction(name string) (Action, bool) {
+	for action, actionName := range actionNames {
+		if strings.EqualFold(actionName, name) {
+			return Action(action), true
+		}
+	}
+	return 0, false
+}
+
+// DefaultBindings returns the default bindings of every action
+func DefaultBindings() map[Action][]Binding {
+	return map[Action][]Binding{
+		ActionAdvance:    mustParseBindings("key:Enter", "key:Space", "mouse:Left", "mouse:WheelDown", "pad:A"),
+		ActionBack:       mustParseBindings("key:Escape", "mouse:Right", "pad:B"),
+		ActionConfirm:    mustParseBindings("key:Enter", "key:Space", "pad:A"),
+		ActionUp:         mustParseBindings("key:ArrowUp", "pad:Up", "pad:StickUp"),
+		ActionDown:       mustParseBindings("key:ArrowDown", "pad:Down", "pad:StickDown"),
+		ActionLeft:       mustParseBindings("key:ArrowLeft", "pad:Left", "pad:StickLeft"),
+		ActionRight:      mustParseBindings("key:ArrowRight", "pad:Right", "pad:StickRight"),
+		ActionSkip:       mustParseBindings("key:ControlLeft", "key:ControlRight", "pad:RB"),
+		ActionSkipToggle: mustParseBindings("key:S", "pad:LB"),
+		ActionAuto:       mustParseBindings("key:A", "pad:Y"),
+		ActionHideUI:     mustParseBindings("key:H", "mouse:Middle", "pad:X"),
+		ActionQuickSave:  mustParseBindings("key:F5"),
+		ActionQuickLoad:  mustParseBindings("key:F9"),
+		ActionScreenshot: mustParseBindings("key:F12"),
+		ActionFullscreen: mustParseBindings("key:Alt+Enter", "key:F11"),
+
+		ActionDebugOverlay:  mustParseBindings("key:F3"),
+		ActionConsole:       mustParseBindings("key:Backquote"),
+		ActionConsoleSubmit: mustParseBindings("key:Enter", "key:NumpadEnter"),
+		ActionConsoleErase:  mustParseBindings("key:Backspace"),
+		ActionConsoleCancel: mustParseBindings("key:Escape"),
+	}
+}
+
+// IsActionPressed returns true while any binding of the action is held
+func (m *Manager) IsActionPressed(action Action) bool {
+	return m.actionDown(m.actions, action)
+}
+
+// IsActionJustPressed returns true on the tick the action started
+func (m *Manager) IsActionJustPressed(action Action) bool {
+	return m.actionDown(m.actions, action) && !m.actionDown(m.prevActions, action)
+}
+
+// IsActionJustReleased returns true on the tick the action ended
+func (m *Manager) IsActionJustReleased(action Action) bool {
+	return !m.actionDown(m.actions, action) && m.actionDown(m.prevActions, action)
+}
+
+// GetBindings returns the bindings of an action
+func (m *Manager) GetBindings(action Action) []Binding {
+	return append([]Binding(nil), m.bindings[action]...)
+}
+
+// SetBindings replaces the bindings of an action
+func (m *Manager) SetBindings(action Action, bindings []Binding) {
+	if action < 0 || action >= ActionsCount {
+		return
+	}
+	m.bindings[action] = append([]Binding(nil), bindings...)
+}
+
+// ResetBindings restores the default bindings of every action
+func (m *Manager) ResetBindings() {
+	m.bindings = DefaultBindings()
+}
+
+// LoadBindings applies binding overrides from the settings file, keyed by
+// action name. Unknown actions and invalid bindings are skipped with a warning.
+func (m *Manager) LoadBindings(overrides map[string][]string) {
+	for name, specs := range overrides {
+		action, ok := ParseAction(name)
+		if !ok {
+			log.Printf("Warning: unknown input action in settings: %s", name)
+			continue
+		}
+
+		bindings := make([]Binding, 0, len(specs))
+		for _, spec := range specs {
+			binding, err := ParseBinding(spec)
+			if err != nil {
+				log.Printf("Warning: invalid binding for %s: %v", name, err)
+				continue
+			}
+			bindings = append(bindings, binding)
+		}
+		m.SetBindings(action, bindings)
+	}
+}
+
+// GetBindingOverrides returns the bindings that differ from the defaults,
+// in the settings file format
+func (m *Manager) GetBindingOverrides() map[string][]string {
+	defaults := DefaultBindings()
+	overrides := make(map[string][]string)
+
+	for action := Action(0); action < ActionsCount; action++ {
+		current := m.bindings[action]
+		if bindingsEqual(current, defaults[action]) {
+			continue
+		}
+
+		specs := make([]string, len(current))
+		for i, binding := range current {
+			specs[i] = binding.String()
+		}
+		overrides[action.String()] = specs
+	}
+	return overrides
+}
+
+// actionDown returns the action state from a state array
+func (m *Manager) actionDown(states [ActionsCount]bool, action Action) bool {
+	if action < 0 || action >= ActionsCount {
+		return false
+	}
+	return states[action]
+}
+
+// updateActions evaluates the bindings of a range of actions for this tick
+// (called from Update)
+func (m *Manager) updateActions(first, end Action) {
+	for action := first; action < end; action++ {
+		m.actions[action] = false
+		for _, binding := range m.bindings[action] {
+			if m.isBindingDown(binding) {
+				m.actions[action] = true
+				break
+			}
+		}
+	}
+}
+
+// bindingsEqual returns whether two binding lists are the same
+func bindingsEqual(a, b []Binding) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mustParseBindings parses built-in binding specs
+func mustParseBindings(specs ...string) []Binding {
+	bindings := make([]Binding, len(specs))
+	for i, spec := range specs {
+		binding, err := ParseBinding(spec)
+		if err != nil {
+			panic(err)
+		}
+		bindings[i] = binding
+	}
+	return bindings
+}
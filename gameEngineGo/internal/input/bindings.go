@@ -0,0 +1,318 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Binding devices
+const (
+	DeviceKey = iota
+	DeviceMouse
+	DeviceGamepad
+)
+
+// Pseudo buttons for the mouse wheel and the gamepad left stick
+const (
+	mouseWheelUp   = 100
+	mouseWheelDown = 101
+	padStickBase   = 100 // padStickBase + NavUp etc.
+)
+
+// Binding is one key, mouse button or gamepad button bound to an action.
+// Written in the settings file as "key:Enter", "key:Alt+Enter",
+// "mouse:Left" or "pad:A".
+type Binding struct {
+	Device int
+	Code   int
+	Alt    bool
+	Ctrl   bool
+	Shift  bool
+}
+
+// mouseNames maps settings names to mouse buttons
+var mouseNames = map[string]int{
+	"Left":      int(ebiten.MouseButtonLeft),
+	"Right":     int(ebiten.MouseButtonRight),
+	"Middle":    int(ebiten.MouseButtonMiddle),
+	"Back":      int(ebiten.MouseButton3),
+	"Forward":   int(ebiten.MouseButton4),
+	"WheelUp":   mouseWheelUp,
+	"WheelDown": mouseWheelDown,
+}
+
+// padNames maps settings names to standard gamepad buttons (Xbox labels)
+var padNames = map[string]int{
+	"A":          int(ebiten.StandardGamepadButtonRightBottom),
+	"B":          int(ebiten.StandardGamepadButtonRightRight),
+	"X":          int(ebiten.StandardGamepadButtonRightLeft),
+	"Y":          int(ebiten.StandardGamepadButtonRightTop),
+	"LB":         int(ebiten.StandardGamepadButtonFrontTopLeft),
+	"RB":         int(ebiten.StandardGamepadButtonFrontTopRight),
+	"LT":         int(ebiten.StandardGamepadButtonFrontBottomLeft),
+	"RT":         int(ebiten.StandardGamepadButtonFrontBottomRight),
+	"Back":       int(ebiten.StandardGamepadButtonCenterLeft),
+	"Start":      int(ebiten.StandardGamepadButtonCenterRight),
+	"Guide":      int(ebiten.StandardGamepadButtonCenterCenter),
+	"LS":         int(ebiten.StandardGamepadButtonLeftStick),
+	"RS":         int(ebiten.StandardGamepadButtonRightStick),
+	"Up":         int(ebiten.StandardGamepadButtonLeftTop),
+	"Down":       int(ebiten.StandardGamepadButtonLeftBottom),
+	"Left":       int(ebiten.StandardGamepadButtonLeftLeft),
+	"Right":      int(ebiten.StandardGamepadButtonLeftRight),
+	"StickUp":    padStickBase + NavUp,
+	"StickDown":  padStickBase + NavDown,
+	"StickLeft":  padStickBase + NavLeft,
+	"StickRight": padStickBase + NavRight,
+}
+
+// ParseBinding parses a binding from its settings file form
+func ParseBinding(spec string) (Binding, error) {
+	device, name, found := strings.Cut(strings.TrimSpace(spec), ":")
+	if !found {
+		return Binding{}, fmt.Errorf("binding %q has no device prefix", spec)
+	}
+
+	var binding Binding
+	parts := strings.Split(name, "+")
+	for _, modifier := range parts[:len(parts)-1] {
+		switch strings.ToLower(modifier) {
+		case "alt":
+			binding.Alt = true
+		case "ctrl", "control":
+			binding.Ctrl = true
+		case "shift":
+			binding.Shift = true
+		default:
+			return Binding{}, fmt.Errorf("binding %q has unknown modifier %q", spec, modifier)
+		}
+	}
+	name = parts[len(parts)-1]
+
+	switch strings.ToLower(device) {
+	case "key":
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(name)); err != nil {
+			return Binding{}, fmt.Errorf("binding %q has unknown key: %w", spec, err)
+		}
+		binding.Device, binding.Code = DeviceKey, int(key)
+	case "mouse":
+		code, ok := lookupName(mouseNames, name)
+		if !ok {
+			return Binding{}, fmt.Errorf("binding %q has unknown mouse button", spec)
+		}
+		binding.Device, binding.Code = DeviceMouse, code
+	case "pad":
+		code, ok := lookupName(padNames, name)
+		if !ok {
+			return Binding{}, fmt.Errorf("binding %q has unknown gamepad button", spec)
+		}
+		binding.Device, binding.Code = DeviceGamepad, code
+	default:
+		return Binding{}, fmt.Errorf("binding %q has unknown device %q", spec, device)
+	}
+
+	return binding, nil
+}
+
+// String returns the settings file form of a binding
+func (b Binding) String() string {
+	var builder strings.Builder
+
+	switch b.Device {
+	case DeviceMouse:
+		builder.WriteString("mouse:")
+	case DeviceGamepad:
+		builder.WriteString("pad:")
+	default:
+		builder.WriteString("key:")
+	}
+
+	if b.Ctrl {
+		builder.WriteString("Ctrl+")
+	}
+	if b.Alt {
+		builder.WriteString("Alt+")
+	}
+	if b.Shift {
+		builder.WriteString("Shift+")
+	}
+
+	switch b.Device {
+	case DeviceMouse:
+		builder.WriteString(reverseLookup(mouseNames, b.Code))
+	case DeviceGamepad:
+		builder.WriteString(reverseLookup(padNames, b.Code))
+	default:
+		builder.WriteString(ebiten.Key(b.Code).String())
+	}
+	return builder.String()
+}
+
+// isBindingDown returns whether a binding is held this tick. Alt must match
+// exactly, so Alt+Enter does not also trigger the plain Enter bindings;
+// Ctrl and Shift only have to be held when the binding asks for them. A
+// binding to a modifier key itself ignores the Alt state.
+func (m *Manager) isBindingDown(b Binding) bool {
+	if b.Alt != m.IsAltPressed() && !(b.Device == DeviceKey && isModifierKey(ebiten.Key(b.Code))) {
+		return false
+	}
+	if b.Ctrl && !m.isKeyDown(ebiten.KeyControl) {
+		return false
+	}
+	if b.Shift && !m.isKeyDown(ebiten.KeyShift) {
+		return false
+	}
+
+	switch b.Device {
+	case DeviceKey:
+		return m.isKeyDown(ebiten.Key(b.Code))
+	case DeviceMouse:
+		switch b.Code {
+		case mouseWheelUp:
+			return m.wheelY > 0
+		case mouseWheelDown:
+			return m.wheelY < 0
+		default:
+			return m.isButtonDown(ebiten.MouseButton(b.Code))
+		}
+	case DeviceGamepad:
+		if b.Code >= padStickBase {
+			return m.stickDir == b.Code-padStickBase
+		}
+		return m.IsGamepadButtonPressed(ebiten.StandardGamepadButton(b.Code))
+	default:
+		return false
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler using the settings file form
+func (b Binding) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *Binding) UnmarshalText(text []byte) error {
+	binding, err := ParseBinding(string(text))
+	if err != nil {
+		return err
+	}
+	*b = binding
+	return nil
+}
+
+// CaptureBinding returns the first key, mouse button or gamepad button
+// pressed this tick, for the rebinding screen
+func (m *Manager) CaptureBinding() (Binding, bool) {
+	if m.captured == nil {
+		return Binding{}, false
+	}
+	return *m.captured, true
+}
+
+// captureLive finds the first key, mouse button or gamepad button pressed
+// this tick (called from Update so that replays see the same result). A
+// modifier is only bound on its own (e.g. Ctrl for skip) when it is released
+// without another key pressed with it, so that Ctrl+S can still be captured.
+func (m *Manager) captureLive() (Binding, bool) {
+	modifiers := Binding{
+		Alt:   m.IsAltPressed(),
+		Ctrl:  m.isKeyDown(ebiten.KeyControl),
+		Shift: m.isKeyDown(ebiten.KeyShift),
+	}
+
+	for _, key := range m.keys {
+		if !m.isKeyJustPressed(key) {
+			continue
+		}
+		if isModifierKey(key) {
+			// Wait for the release; a second modifier makes a combination
+			if !isVirtualModifierKey(key) {
+				m.loneModifier = noKey
+				if len(m.modifiersHeld()) == 1 {
+					m.loneModifier = key
+				}
+			}
+			continue
+		}
+		m.loneModifier = noKey
+		return Binding{Device: DeviceKey, Code: int(key), Alt: modifiers.Alt, Ctrl: modifiers.Ctrl, Shift: modifiers.Shift}, true
+	}
+
+	if key := m.loneModifier; key != noKey && !m.isKeyDown(key) {
+		m.loneModifier = noKey
+		return Binding{Device: DeviceKey, Code: int(key)}, true
+	}
+
+	for button := ebiten.MouseButton0; button <= ebiten.MouseButtonMax; button++ {
+		if m.isButtonJustPressed(button) {
+			m.loneModifier = noKey
+			return Binding{Device: DeviceMouse, Code: int(button), Alt: modifiers.Alt}, true
+		}
+	}
+
+	for button := ebiten.StandardGamepadButton(0); button <= ebiten.StandardGamepadButtonMax; button++ {
+		if m.IsGamepadButtonJustPressed(button) {
+			return Binding{Device: DeviceGamepad, Code: int(button)}, true
+		}
+	}
+
+	if m.stickDir != NavNone && m.stickDir != m.prevStickDir {
+		return Binding{Device: DeviceGamepad, Code: padStickBase + m.stickDir}, true
+	}
+
+	return Binding{}, false
+}
+
+// noKey marks no key, e.g. no modifier waiting to be captured
+const noKey ebiten.Key = -1
+
+// isModifierKey returns whether a key is Alt, Ctrl or Shift, on either side
+func isModifierKey(key ebiten.Key) bool {
+	switch key {
+	case ebiten.KeyAltLeft, ebiten.KeyAltRight, ebiten.KeyControlLeft, ebiten.KeyControlRight,
+		ebiten.KeyShiftLeft, ebiten.KeyShiftRight:
+		return true
+	default:
+		return isVirtualModifierKey(key)
+	}
+}
+
+// isVirtualModifierKey returns whether a key is KeyAlt, KeyControl or
+// KeyShift, which ebiten reports along with the key of either side
+func isVirtualModifierKey(key ebiten.Key) bool {
+	return key == ebiten.KeyAlt || key == ebiten.KeyControl || key == ebiten.KeyShift
+}
+
+// modifiersHeld returns the Alt, Ctrl and Shift keys held this tick
+func (m *Manager) modifiersHeld() []ebiten.Key {
+	var held []ebiten.Key
+	for _, key := range m.keys {
+		if isModifierKey(key) && !isVirtualModifierKey(key) {
+			held = append(held, key)
+		}
+	}
+	return held
+}
+
+// lookupName finds a name case-insensitively
+func lookupName(names map[string]int, name string) (int, bool) {
+	for candidate, code := range names {
+		if strings.EqualFold(candidate, name) {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// reverseLookup returns the name of a code
+func reverseLookup(names map[string]int, code int) string {
+	for name, candidate := range names {
+		if candidate == code {
+			return name
+		}
+	}
+	return fmt.Sprintf("%d", code)
+}
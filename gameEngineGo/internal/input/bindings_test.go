@@ -0,0 +1,176 @@
+package input
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// pressKeys sets the keys held this tick and the tick before
+func (m *Manager) pressKeys(previous, current []ebiten.Key) {
+	m.prevKeys = previous
+	m.keys = current
+}
+
+// tickKeys runs one live Update with keys held, instead of the devices
+func (m *Manager) tickKeys(keys ...ebiten.Key) {
+	m.prevMouse = m.mouse
+	m.prevActions = m.actions
+	m.tick++
+	m.pressKeys(m.keys, keys)
+	m.updateState()
+}
+
+func TestCaptureLive(t *testing.T) {
+	var (
+		ctrl  = []ebiten.Key{ebiten.KeyControlLeft, ebiten.KeyControl}
+		shift = []ebiten.Key{ebiten.KeyShiftLeft, ebiten.KeyShift}
+		alt   = []ebiten.Key{ebiten.KeyAltLeft, ebiten.KeyAlt}
+	)
+	with := func(keys []ebiten.Key, more ...ebiten.Key) []ebiten.Key {
+		return append(append([]ebiten.Key{}, keys...), more...)
+	}
+
+	tests := []struct {
+		name    string
+		ticks   [][]ebiten.Key // Keys held on each tick
+		buttons []ebiten.MouseButton
+		want    []string // Captured on each tick
+	}{
+		{"key", [][]ebiten.Key{{ebiten.KeyS}}, nil, []string{"key:S"}},
+		{"held key", [][]ebiten.Key{{ebiten.KeyS}, {ebiten.KeyS}}, nil, []string{"key:S", ""}},
+		{"modifiers", [][]ebiten.Key{shift, with(shift, ebiten.KeyA)}, nil, []string{"", "key:Shift+A"}},
+		{"combination", [][]ebiten.Key{ctrl, with(ctrl, ebiten.KeyS), ctrl, nil}, nil, []string{"", "key:Ctrl+S", "", ""}},
+		{"modifier alone", [][]ebiten.Key{ctrl, ctrl, nil}, nil, []string{"", "", "key:ControlLeft"}},
+		{"two modifiers", [][]ebiten.Key{ctrl, with(ctrl, shift...), ctrl, nil}, nil, []string{"", "", "", ""}},
+		{"mouse", [][]ebiten.Key{alt, alt}, []ebiten.MouseButton{ebiten.MouseButtonMiddle}, []string{"", "mouse:Alt+Middle"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager()
+			var previous []ebiten.Key
+			for i, keys := range test.ticks {
+				m.pressKeys(previous, keys)
+				previous = keys
+				if i == len(test.ticks)-1 {
+					for _, button := range test.buttons {
+						m.buttons[button] = true
+					}
+				}
+
+				got := ""
+				if binding, ok := m.captureLive(); ok {
+					got = binding.String()
+				}
+				if got != test.want[i] {
+					t.Errorf("tick %d: captured %q, want %q", i, got, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModifierKeyBinding(t *testing.T) {
+	m := NewManager()
+	binding, err := ParseBinding("key:AltLeft")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.pressKeys(nil, []ebiten.Key{ebiten.KeyAltLeft, ebiten.KeyAlt})
+	if !m.isBindingDown(binding) {
+		t.Error("binding to Alt not down while Alt is held")
+	}
+	m.pressKeys(nil, []ebiten.Key{ebiten.KeyAltRight, ebiten.KeyAlt})
+	if m.isBindingDown(binding) {
+		t.Error("binding to the left Alt down for the right one")
+	}
+}
+
+func TestBindingsReadSnapshot(t *testing.T) {
+	m := NewManager()
+	m.pressKeys(nil, []ebiten.Key{ebiten.KeyEnter})
+	m.buttons[ebiten.MouseButtonRight] = true
+	m.updateActions(0, ActionsCount)
+
+	for _, action := range []Action{ActionAdvance, ActionConfirm, ActionBack, ActionConsoleSubmit} {
+		if !m.IsActionPressed(action) {
+			t.Errorf("%s not pressed", action)
+		}
+	}
+
+	// Alt must match exactly: Alt+Enter is fullscreen, not advance
+	m.pressKeys(nil, []ebiten.Key{ebiten.KeyEnter, ebiten.KeyAltLeft, ebiten.KeyAlt})
+	m.buttons = [ebiten.MouseButtonMax + 1]bool{}
+	m.updateActions(0, ActionsCount)
+	if m.IsActionPressed(ActionAdvance) || !m.IsActionPressed(ActionFullscreen) {
+		t.Errorf("Alt+Enter: advance %v, fullscreen %v", m.IsActionPressed(ActionAdvance), m.IsActionPressed(ActionFullscreen))
+	}
+}
+
+func TestDeveloperActionsNotRecorded(t *testing.T) {
+	m := NewManager()
+	if err := m.StartRecording(filepath.Join(t.TempDir(), "input.jsonl"), 60); err != nil {
+		t.Fatal(err)
+	}
+	defer m.StopRecording()
+
+	// A suspended tick keeps the developer actions only
+	m.pressKeys(nil, []ebiten.Key{ebiten.KeyEnter, ebiten.KeyBackquote})
+	m.updateActions(0, ActionsCount)
+	m.SetSuspended(true)
+	m.clearPresses()
+	m.updateActions(GameActionsCount, ActionsCount)
+	if m.IsActionPressed(ActionAdvance) || !m.IsActionPressed(ActionConsole) || !m.IsActionPressed(ActionConsoleSubmit) {
+		t.Error("suspension changed the developer actions or kept the game actions")
+	}
+
+	m.SetSuspended(false)
+	m.updateActions(0, ActionsCount)
+	m.recordFrame()
+	for _, action := range m.recording.frame.Actions {
+		if action >= GameActionsCount {
+			t.Errorf("developer action %s recorded", action)
+		}
+	}
+	if len(m.recording.frame.Actions) == 0 {
+		t.Error("game actions not recorded")
+	}
+}
+
+func TestResumeWaitsForRelease(t *testing.T) {
+	m := NewManager()
+
+	// Escape closes the console on the tick it goes down
+	m.SetSuspended(true)
+	m.tickKeys(ebiten.KeyEscape)
+	if !m.IsActionJustPressed(ActionConsoleCancel) || m.IsActionPressed(ActionBack) {
+		t.Fatal("suspended tick did not keep Escape for the console only")
+	}
+	m.SetSuspended(false)
+
+	// Held on, it must not reach the game as a new press
+	m.tickKeys(ebiten.KeyEscape)
+	if m.IsActionJustPressed(ActionBack) || m.IsActionPressed(ActionBack) {
+		t.Error("Escape held from the console reached the game")
+	}
+	if !m.IsSuspended() {
+		t.Error("resumed with Escape still held")
+	}
+
+	m.tickKeys()
+	m.tickKeys(ebiten.KeyEscape)
+	if m.IsSuspended() || !m.IsActionJustPressed(ActionBack) {
+		t.Error("a new Escape press after the release did not reach the game")
+	}
+
+	// Resuming with nothing held takes effect at once
+	m.tickKeys()
+	m.SetSuspended(true)
+	m.SetSuspended(false)
+	if m.IsSuspended() {
+		t.Error("resume with no keys held deferred")
+	}
+}
@@ -1,64 +1,218 @@
 package input
 
 import (
+	"math"
+	"slices"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 // MouseState represents the current mouse state
 type MouseState struct {
-	X, Y         int
-	LeftButton   bool
-	RightButton  bool
-	LeftPressed  bool
-	RightPressed bool
+	X            int  `json:"x"`
+	Y            int  `json:"y"`
+	LeftButton   bool `json:"left,omitempty"`
+	RightButton  bool `json:"right,omitempty"`
+	LeftPressed  bool `json:"left_pressed,omitempty"`
+	RightPressed bool `json:"right_pressed,omitempty"`
+}
+
+// CoordinateMapper converts window coordinates to logical screen coordinates
+type CoordinateMapper interface {
+	ToLogical(x, y int) (float64, float64)
 }
 
-// Manager handles all input from keyboard and mouse
+// Manager handles all input from keyboard, mouse and gamepads
 type Manager struct {
 	mouse     MouseState
 	prevMouse MouseState
-	keys      map[ebiten.Key]bool
-	prevKeys  map[ebiten.Key]bool
+	wheelY    float64
+	mapper    CoordinateMapper
+
+	// Keys, mouse buttons and characters read from the devices this tick;
+	// bindings, rebinding and text entry all work from this snapshot
+	keys, prevKeys       []ebiten.Key
+	buttons, prevButtons [ebiten.MouseButtonMax + 1]bool
+	chars                []rune
+
+	// Action layer
+	bindings    map[Action][]Binding
+	actions     [ActionsCount]bool
+	prevActions [ActionsCount]bool
+
+	// Standard-layout gamepads and left stick direction
+	gamepadIDs   []ebiten.GamepadID
+	stickDir     int
+	prevStickDir int
+
+	// First binding pressed this tick, for the rebinding screen, and the
+	// modifier held on its own, captured if released without another key
+	captured     *Binding
+	loneModifier ebiten.Key
+
+	// Live input is ignored, e.g. while the developer console has the
+	// keyboard, and stays ignored after resuming until all keys are up
+	suspended       bool
+	resumeOnRelease bool
+
+	// Input recording and replay
+	tick      int64
+	recording *recorder
+	playback  *playback
 }
 
 // NewManager creates a new input manager
 func NewManager() *Manager {
 	return &Manager{
-		keys:     make(map[ebiten.Key]bool),
-		prevKeys: make(map[ebiten.Key]bool),
+		bindings:     DefaultBindings(),
+		loneModifier: noKey,
 	}
 }
 
-// Update updates the input state
+// Update updates the input state, from the devices or from a replay
 func (m *Manager) Update() {
 	// Store previous mouse state
 	m.prevMouse = m.mouse
+	m.prevActions = m.actions
+	m.tick++
+
+	m.updateGamepads()
+	m.readKeysAndButtons()
+	m.updateState()
+}
+
+// updateState derives this tick's input from the device snapshot or the
+// replay (called from Update)
+func (m *Manager) updateState() {
+	if m.resumeOnRelease && !m.isAnyHeld() {
+		m.suspended, m.resumeOnRelease = false, false
+	}
 
-	// Update mouse position
-	m.mouse.X, m.mouse.Y = ebiten.CursorPosition()
+	if m.playback != nil {
+		m.readPlayback()
+	} else {
+		m.readDevices()
+		if m.suspended {
+			m.clearPresses()
+		}
+		if m.recording != nil {
+			m.recordFrame()
+		}
+	}
+
+	// Developer actions stay live, whatever happened to the game actions
+	m.updateActions(GameActionsCount, ActionsCount)
+}
+
+// readKeysAndButtons takes this tick's snapshot of the keyboard, mouse
+// buttons and typed characters (called from Update)
+func (m *Manager) readKeysAndButtons() {
+	m.prevKeys = append(m.prevKeys[:0], m.keys...)
+	m.keys = inpututil.AppendPressedKeys(m.keys[:0])
+
+	m.prevButtons = m.buttons
+	for button := range m.buttons {
+		m.buttons[button] = ebiten.IsMouseButtonPressed(ebiten.MouseButton(button))
+	}
+
+	m.chars = ebiten.AppendInputChars(m.chars[:0])
+}
+
+// readDevices reads this tick's state from the keyboard, mouse and gamepads
+func (m *Manager) readDevices() {
+	// Update mouse position, mapped through the letterbox if the window is scaled
+	cursorX, cursorY := ebiten.CursorPosition()
+	if m.mapper != nil {
+		logicalX, logicalY := m.mapper.ToLogical(cursorX, cursorY)
+		cursorX, cursorY = int(math.Floor(logicalX)), int(math.Floor(logicalY))
+	}
+	m.mouse.X, m.mouse.Y = cursorX, cursorY
 
 	// Update mouse buttons
-	m.mouse.LeftButton = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
-	m.mouse.RightButton = ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
+	m.mouse.LeftButton = m.isButtonDown(ebiten.MouseButtonLeft)
+	m.mouse.RightButton = m.isButtonDown(ebiten.MouseButtonRight)
 
 	// Check for mouse button presses (just pressed this frame)
-	m.mouse.LeftPressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
-	m.mouse.RightPressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+	m.mouse.LeftPressed = m.isButtonJustPressed(ebiten.MouseButtonLeft)
+	m.mouse.RightPressed = m.isButtonJustPressed(ebiten.MouseButtonRight)
 
-	// Store previous key states
-	for k, v := range m.keys {
-		m.prevKeys[k] = v
+	_, m.wheelY = ebiten.Wheel()
+
+	m.updateActions(0, GameActionsCount)
+
+	m.captured = nil
+	if binding, ok := m.captureLive(); ok {
+		m.captured = &binding
 	}
+}
 
-	// Update key states
-	m.keys[ebiten.KeyEscape] = ebiten.IsKeyPressed(ebiten.KeyEscape)
-	m.keys[ebiten.KeyEnter] = ebiten.IsKeyPressed(ebiten.KeyEnter)
-	m.keys[ebiten.KeySpace] = ebiten.IsKeyPressed(ebiten.KeySpace)
-	m.keys[ebiten.KeyArrowUp] = ebiten.IsKeyPressed(ebiten.KeyArrowUp)
-	m.keys[ebiten.KeyArrowDown] = ebiten.IsKeyPressed(ebiten.KeyArrowDown)
-	m.keys[ebiten.KeyArrowLeft] = ebiten.IsKeyPressed(ebiten.KeyArrowLeft)
-	m.keys[ebiten.KeyArrowRight] = ebiten.IsKeyPressed(ebiten.KeyArrowRight)
+// SetSuspended makes live input idle until resumed; the mouse still moves.
+// Resuming waits for the keys and buttons held now to be released, so that
+// the key that resumed (e.g. Escape closing the console) does not reach the
+// game as a new press.
+func (m *Manager) SetSuspended(suspended bool) {
+	if !suspended && m.suspended && m.isAnyHeld() {
+		m.resumeOnRelease = true
+		return
+	}
+	m.suspended, m.resumeOnRelease = suspended, false
+}
+
+// IsSuspended returns whether live input is ignored
+func (m *Manager) IsSuspended() bool {
+	return m.suspended
+}
+
+// clearPresses drops this tick's buttons, wheel and game actions
+func (m *Manager) clearPresses() {
+	m.mouse.LeftButton, m.mouse.RightButton = false, false
+	m.mouse.LeftPressed, m.mouse.RightPressed = false, false
+	m.wheelY = 0
+	m.actions = [ActionsCount]bool{}
+	m.captured = nil
+}
+
+// SetCoordinateMapper sets the mapping from window to logical screen coordinates
+func (m *Manager) SetCoordinateMapper(mapper CoordinateMapper) {
+	m.mapper = mapper
+}
+
+// IsAltPressed returns true if either Alt key is held
+func (m *Manager) IsAltPressed() bool {
+	return m.isKeyDown(ebiten.KeyAlt)
+}
+
+// InputChars returns the characters typed this tick, for text entry such as
+// the developer console. They are not recorded.
+func (m *Manager) InputChars() []rune {
+	return m.chars
+}
+
+// isKeyDown returns whether a key is held this tick. KeyAlt, KeyControl and
+// KeyShift match either side.
+func (m *Manager) isKeyDown(key ebiten.Key) bool {
+	return slices.Contains(m.keys, key)
+}
+
+// isKeyJustPressed returns whether a key went down this tick
+func (m *Manager) isKeyJustPressed(key ebiten.Key) bool {
+	return m.isKeyDown(key) && !slices.Contains(m.prevKeys, key)
+}
+
+// isAnyHeld returns whether any key or mouse button is held this tick
+func (m *Manager) isAnyHeld() bool {
+	return len(m.keys) > 0 || slices.Contains(m.buttons[:], true)
+}
+
+// isButtonDown returns whether a mouse button is held this tick
+func (m *Manager) isButtonDown(button ebiten.MouseButton) bool {
+	return button >= 0 && int(button) < len(m.buttons) && m.buttons[button]
+}
+
+// isButtonJustPressed returns whether a mouse button went down this tick
+func (m *Manager) isButtonJustPressed(button ebiten.MouseButton) bool {
+	return m.isButtonDown(button) && !m.prevButtons[button]
 }
 
 // GetMousePosition returns the current mouse position
@@ -90,16 +244,6 @@ func (m *Manager) IsMouseButtonJustPressed(button ebiten.MouseButton) bool {
 	}
 }
 
-// IsKeyPressed returns true if the specified key is currently pressed
-func (m *Manager) IsKeyPressed(key ebiten.Key) bool {
-	return m.keys[key]
-}
-
-// IsKeyJustPressed returns true if the specified key was just pressed this frame
-func (m *Manager) IsKeyJustPressed(key ebiten.Key) bool {
-	return m.keys[key] && !m.prevKeys[key]
-}
-
 // GetMouseState returns the current mouse state
 func (m *Manager) GetMouseState() MouseState {
 	return m.mouse
@@ -0,0 +1,108 @@
+package input
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Navigation directions for keyboard and gamepad menu focus
+const (
+	NavNone = iota
+	NavUp
+	NavDown
+	NavLeft
+	NavRight
+)
+
+// stickDeadZone is how far an analog stick must be pushed to count as a direction
+const stickDeadZone = 0.5
+
+// updateGamepads reads the standard-layout gamepads (called from Update)
+func (m *Manager) updateGamepads() {
+	m.gamepadIDs = ebiten.AppendGamepadIDs(m.gamepadIDs[:0])
+
+	m.prevStickDir = m.stickDir
+	m.stickDir = NavNone
+	for _, id := range m.gamepadIDs {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		if dir := stickDirection(id); dir != NavNone {
+			m.stickDir = dir
+			break
+		}
+	}
+}
+
+// stickDirection returns the direction the left stick of a gamepad points to
+func stickDirection(id ebiten.GamepadID) int {
+	x := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+
+	switch {
+	case y <= -stickDeadZone && -y >= math.Abs(x):
+		return NavUp
+	case y >= stickDeadZone && y >= math.Abs(x):
+		return NavDown
+	case x <= -stickDeadZone:
+		return NavLeft
+	case x >= stickDeadZone:
+		return NavRight
+	default:
+		return NavNone
+	}
+}
+
+// IsGamepadButtonJustPressed returns true if any standard gamepad just pressed the button
+func (m *Manager) IsGamepadButtonJustPressed(button ebiten.StandardGamepadButton) bool {
+	for _, id := range m.gamepadIDs {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) && inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGamepadButtonPressed returns true if any standard gamepad holds the button
+func (m *Manager) IsGamepadButtonPressed(button ebiten.StandardGamepadButton) bool {
+	for _, id := range m.gamepadIDs {
+		if ebiten.IsStandardGamepadLayoutAvailable(id) && ebiten.IsStandardGamepadButtonPressed(id, button) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNavigation returns the direction action started this tick (arrow keys,
+// D-pad or left stick by default), or NavNone
+func (m *Manager) GetNavigation() int {
+	switch {
+	case m.IsActionJustPressed(ActionUp):
+		return NavUp
+	case m.IsActionJustPressed(ActionDown):
+		return NavDown
+	case m.IsActionJustPressed(ActionLeft):
+		return NavLeft
+	case m.IsActionJustPressed(ActionRight):
+		return NavRight
+	default:
+		return NavNone
+	}
+}
+
+// IsConfirmJustPressed returns true if the confirm action started this tick
+func (m *Manager) IsConfirmJustPressed() bool {
+	return m.IsActionJustPressed(ActionConfirm)
+}
+
+// IsCancelJustPressed returns true if the back action started this tick
+func (m *Manager) IsCancelJustPressed() bool {
+	return m.IsActionJustPressed(ActionBack)
+}
+
+// HasMouseMoved returns true if the cursor moved since the last frame
+func (m *Manager) HasMouseMoved() bool {
+	return m.mouse.X != m.prevMouse.X || m.mouse.Y != m.prevMouse.Y
+}
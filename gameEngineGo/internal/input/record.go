@@ -0,0 +1,228 @@
+package input
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// recordingVersion is the version of the recording file format
+const recordingVersion = 1
+
+// RecordingHeader is the first line of a recording file
+type RecordingHeader struct {
+	Version int `json:"version"`
+	TPS     int `json:"tps"` // Ticks per second the session ran at
+}
+
+// Frame is the input state of one tick, written as one JSON line. State is
+// filled in by the game at the end of the tick and compared on replay.
+type Frame struct {
+	Tick        int64      `json:"tick"`
+	Mouse       MouseState `json:"mouse"`
+	Wheel       float64    `json:"wheel,omitempty"`
+	Actions     []Action   `json:"actions,omitempty"`      // Actions held this tick
+	Capture     *Binding   `json:"capture,omitempty"`      // Binding pressed for the rebinding screen
+	AutoAdvance bool       `json:"auto_advance,omitempty"` // Auto mode left a held line, replayed as it depends on voice timing
+	State       string     `json:"state,omitempty"`
+}
+
+// recorder writes frames to a recording file
+type recorder struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	frame   Frame // Current tick, written by EndTick
+}
+
+// playback feeds frames from a recording file
+type playback struct {
+	header     RecordingHeader
+	frames     []Frame
+	next       int
+	frame      *Frame // Current tick
+	divergence string // First state mismatch
+}
+
+// StartRecording records the input of every following tick to a file
+func (m *Manager) StartRecording(path string, tps int) error {
+	if m.playback != nil {
+		return fmt.Errorf("cannot record while replaying")
+	}
+	m.StopRecording()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create recording %s: %w", path, err)
+	}
+
+	rec := &recorder{file: file, writer: bufio.NewWriter(file)}
+	rec.encoder = json.NewEncoder(rec.writer)
+	if err := rec.encoder.Encode(RecordingHeader{Version: recordingVersion, TPS: tps}); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write recording header: %w", err)
+	}
+
+	m.recording = rec
+	m.tick = 0
+	log.Printf("Recording input to %s", path)
+	return nil
+}
+
+// StopRecording flushes and closes the recording file
+func (m *Manager) StopRecording() error {
+	if m.recording == nil {
+		return nil
+	}
+	rec := m.recording
+	m.recording = nil
+
+	err := rec.writer.Flush()
+	if closeErr := rec.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+
+	log.Printf("Input recording stopped after %d ticks", m.tick)
+	return nil
+}
+
+// IsRecording returns whether input is being recorded
+func (m *Manager) IsRecording() bool {
+	return m.recording != nil
+}
+
+// StartPlayback replaces live input with a recording file, tick by tick
+func (m *Manager) StartPlayback(path string) (RecordingHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return RecordingHeader{}, fmt.Errorf("failed to open recording %s: %w", path, err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	pb := &playback{}
+	if err := decoder.Decode(&pb.header); err != nil {
+		return RecordingHeader{}, fmt.Errorf("failed to read recording header: %w", err)
+	}
+	if pb.header.Version != recordingVersion {
+		return RecordingHeader{}, fmt.Errorf("unsupported recording version %d", pb.header.Version)
+	}
+
+	for {
+		var frame Frame
+		err := decoder.Decode(&frame)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return RecordingHeader{}, fmt.Errorf("failed to read recording frame %d: %w", len(pb.frames), err)
+		}
+		pb.frames = append(pb.frames, frame)
+	}
+
+	m.StopRecording()
+	m.playback = pb
+	m.tick = 0
+	log.Printf("Replaying %d ticks of input from %s", len(pb.frames), path)
+	return pb.header, nil
+}
+
+// IsPlayingBack returns whether input comes from a recording
+func (m *Manager) IsPlayingBack() bool {
+	return m.playback != nil
+}
+
+// IsPlaybackFinished returns whether every recorded tick has been replayed
+func (m *Manager) IsPlaybackFinished() bool {
+	return m.playback != nil && m.playback.next >= len(m.playback.frames) && m.playback.frame == nil
+}
+
+// GetTick returns the number of ticks since recording or replay started
+func (m *Manager) GetTick() int64 {
+	return m.tick
+}
+
+// EndTick finishes the current tick with a description of the game state.
+// Recording stores it; replay compares it with the recorded state and
+// returns an error at the first tick where they differ.
+func (m *Manager) EndTick(state string) error {
+	switch {
+	case m.recording != nil:
+		m.recording.frame.State = state
+		if err := m.recording.encoder.Encode(&m.recording.frame); err != nil {
+			m.StopRecording()
+			return fmt.Errorf("failed to write recording frame: %w", err)
+		}
+
+	case m.playback != nil && m.playback.frame != nil:
+		frame := m.playback.frame
+		m.playback.frame = nil
+		if frame.State != "" && frame.State != state && m.playback.divergence == "" {
+			m.playback.divergence = fmt.Sprintf("tick %d: recorded %q, got %q", frame.Tick, frame.State, state)
+			return fmt.Errorf("replay diverged at %s", m.playback.divergence)
+		}
+	}
+	return nil
+}
+
+// MarkAutoAdvance records that auto mode advanced a held line this tick
+func (m *Manager) MarkAutoAdvance() {
+	if m.recording != nil {
+		m.recording.frame.AutoAdvance = true
+	}
+}
+
+// IsAutoAdvanceReplayed returns whether the recording advanced a held line
+// by auto mode this tick
+func (m *Manager) IsAutoAdvanceReplayed() bool {
+	return m.playback != nil && m.playback.frame != nil && m.playback.frame.AutoAdvance
+}
+
+// recordFrame captures this tick's input for the recording (called from Update)
+func (m *Manager) recordFrame() {
+	frame := Frame{
+		Tick:    m.tick,
+		Mouse:   m.mouse,
+		Wheel:   m.wheelY,
+		Capture: m.captured,
+	}
+	for action := Action(0); action < GameActionsCount; action++ {
+		if m.actions[action] {
+			frame.Actions = append(frame.Actions, action)
+		}
+	}
+	m.recording.frame = frame
+}
+
+// readPlayback loads this tick's input from the recording (called from Update)
+func (m *Manager) readPlayback() {
+	pb := m.playback
+	m.actions = [ActionsCount]bool{}
+	m.wheelY = 0
+	m.captured = nil
+
+	if pb.next >= len(pb.frames) {
+		pb.frame = nil
+		return // Finished, input stays idle
+	}
+
+	frame := &pb.frames[pb.next]
+	pb.next++
+	pb.frame = frame
+
+	m.mouse = frame.Mouse
+	m.wheelY = frame.Wheel
+	m.captured = frame.Capture
+	for _, action := range frame.Actions {
+		if action >= 0 && action < GameActionsCount {
+			m.actions[action] = true
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package locale
+
+import (
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"school-days-engine/internal/filesystem"
+)
+
+// ScriptRoot holds one script tree per language (Script/RUSSIAN/00/...)
+const ScriptRoot = "Script"
+
+// OverrideRoot holds per-language replacements of other game files,
+// mirroring the game tree (Localized/RUSSIAN/Title/Title.png)
+const OverrideRoot = "Localized"
+
+// languageDirs maps settings language codes to the directory names used by
+// the game data. Other values are used as directory names directly.
+var languageDirs = map[string]string{
+	"en": "ENGLISH",
+	"ja": "JAPANESE",
+	"ru": "RUSSIAN",
+	"zh": "CHINESE",
+	"ko": "KOREAN",
+	"de": "GERMAN",
+	"fr": "FRENCH",
+	"es": "SPANISH",
+	"it": "ITALIAN",
+	"pl": "POLISH",
+	"pt": "PORTUGUESE",
+}
+
+// DirectoryName returns the data directory name of a language code
+func DirectoryName(language string) string {
+	if dir, ok := languageDirs[strings.ToLower(language)]; ok {
+		return dir
+	}
+	return strings.ToUpper(language)
+}
+
+// Code returns the settings code of a language directory name
+func Code(dir string) string {
+	for code, name := range languageDirs {
+		if strings.EqualFold(name, dir) {
+			return code
+		}
+	}
+	return strings.ToLower(dir)
+}
+
+// Discover returns the directory names of the script languages found in the
+// GPK archives and the loose files, sorted
+func Discover(fs *filesystem.Manager) []string {
+	found := make(map[string]string) // Upper-case name -> name as found
+
+	add := func(name string) {
+		if name != "" && !strings.Contains(name, ".") {
+			found[strings.ToUpper(name)] = name
+		}
+	}
+
+	for _, file := range fs.ListFiles() {
+		parts := strings.Split(strings.ReplaceAll(file.Name, "\\", "/"), "/")
+		if len(parts) > 2 && strings.EqualFold(parts[0], ScriptRoot) {
+			add(parts[1])
+		}
+	}
+	if names, err := fs.ListDirectory(ScriptRoot); err == nil {
+		for _, name := range names {
+			add(name)
+		}
+	}
+
+	languages := make([]string, 0, len(found))
+	for _, name := range found {
+		languages = append(languages, name)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Localizer maps language-neutral file names to the files of the chosen
+// language, falling back per file to a default language
+type Localizer struct {
+	fs        *filesystem.Manager
+	available []string // Script language directories
+	language  string   // Directory name of the chosen language
+	fallback  string   // Directory name of the fallback, "" for any
+}
+
+// NewLocalizer creates a localizer for a language code with a fallback
+// language code ("" falls back to any available language)
+func NewLocalizer(fs *filesystem.Manager, language, fallback string) *Localizer {
+	l := &Localizer{fs: fs, available: Discover(fs)}
+	l.SetFallback(fallback)
+	l.SetLanguage(language)
+	log.Printf("Script languages: %s", strings.Join(l.available, ", "))
+	return l
+}
+
+// SetLanguage chooses the language by settings code
+func (l *Localizer) SetLanguage(language string) {
+	l.language = DirectoryName(language)
+	if !l.hasLanguage(l.language) {
+		log.Printf("Warning: no scripts for language %s, using the fallback", language)
+	}
+}
+
+// SetFallback sets the language used for files missing a translation
+func (l *Localizer) SetFallback(language string) {
+	l.fallback = ""
+	if language != "" {
+		l.fallback = DirectoryName(language)
+	}
+}
+
+// GetLanguage returns the settings code of the chosen language
+func (l *Localizer) GetLanguage() string {
+	return Code(l.language)
+}
+
+// GetLanguages returns the settings codes of the available script languages
+func (l *Localizer) GetLanguages() []string {
+	codes := make([]string, len(l.available))
+	for i, dir := range l.available {
+		codes[i] = Code(dir)
+	}
+	return codes
+}
+
+// ScriptPath returns the file of a script in the chosen language. The name
+// is relative to a language tree ("00/00-00-A00.JRS") or a path in any
+// language's tree ("Script/RUSSIAN/00/00-00-A00.JRS"). Missing translations
+// fall back to the fallback language, or to any language that has the file.
+// Other existing files are used as they are.
+func (l *Localizer) ScriptPath(name string) string {
+	relative, inTree := scriptRelative(name)
+	if relative == "" || (!inTree && l.fs.Exists(name)) {
+		return name
+	}
+
+	candidates := []string{l.language}
+	if l.fallback != "" {
+		candidates = append(candidates, l.fallback)
+	} else {
+		candidates = append(candidates, l.available...)
+	}
+
+	for _, language := range candidates {
+		candidate := path.Join(ScriptRoot, language, relative)
+		if l.fs.Exists(candidate) {
+			if !strings.EqualFold(language, l.language) {
+				log.Printf("Warning: %s has no %s translation, using %s", relative, l.language, language)
+			}
+			return candidate
+		}
+	}
+	return name
+}
+
+// Resolve returns the per-language override of a game file if there is one
+// (e.g. a translated menu image), otherwise the file itself
+func (l *Localizer) Resolve(filename string) string {
+	override := path.Join(OverrideRoot, l.language, strings.ReplaceAll(filename, "\\", "/"))
+	if l.fs.Exists(override) {
+		return override
+	}
+	return filename
+}
+
+// scriptRelative strips the script root and language from a script path and
+// reports whether the path was in the script tree. Other relative names are
+// returned as they are.
+func scriptRelative(name string) (string, bool) {
+	name = strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "./")
+	parts := strings.SplitN(name, "/", 3)
+
+	if !strings.EqualFold(parts[0], ScriptRoot) {
+		if path.IsAbs(name) {
+			return "", false
+		}
+		return name, false
+	}
+	if len(parts) == 3 {
+		return parts[2], true
+	}
+	return "", true
+}
+
+// hasLanguage returns whether a language has a script tree
+func (l *Localizer) hasLanguage(language string) bool {
+	for _, available := range l.available {
+		if strings.EqualFold(available, language) {
+			return true
+		}
+	}
+	return false
+}
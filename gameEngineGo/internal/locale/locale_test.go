@@ -0,0 +1,114 @@
+package locale
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/filesystem"
+)
+
+// newTestLocalizer creates a localizer over a loose game tree holding files
+func newTestLocalizer(t *testing.T, language, fallback string, files ...string) *Localizer {
+	t.Helper()
+	root := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := filesystem.NewManager(root)
+	if err := fs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { fs.Close() })
+	return NewLocalizer(fs, language, fallback)
+}
+
+// localeTree is a game with English, Russian and Japanese scripts
+var localeTree = []string{
+	"Script/ENGLISH/00/00-00-A00.JRS",
+	"Script/RUSSIAN/00/00-00-A00.JRS",
+	"Script/RUSSIAN/00/00-00-B00.JRS",
+	"Script/JAPANESE/00/00-00-C00.JRS",
+	"Other/extra.jrs",
+	"Title/Title.png",
+	"Title/Logo.png",
+	"Localized/ENGLISH/Title/Title.png",
+}
+
+func TestScriptPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback string
+		script   string
+		want     string
+	}{
+		{"relative", "ru", "00/00-00-A00.JRS", "Script/ENGLISH/00/00-00-A00.JRS"},
+		{"other language tree", "ru", "Script/RUSSIAN/00/00-00-A00.JRS", "Script/ENGLISH/00/00-00-A00.JRS"},
+		{"backslashes", "ru", `Script\RUSSIAN\00\00-00-A00.JRS`, "Script/ENGLISH/00/00-00-A00.JRS"},
+		{"dot prefix", "ru", "./Script/JAPANESE/00/00-00-A00.JRS", "Script/ENGLISH/00/00-00-A00.JRS"},
+		{"fallback language", "ru", "Script/ENGLISH/00/00-00-B00.JRS", "Script/RUSSIAN/00/00-00-B00.JRS"},
+		{"missing from the fallback", "ru", "00/00-00-C00.JRS", "00/00-00-C00.JRS"},
+		{"any language", "", "00/00-00-C00.JRS", "Script/JAPANESE/00/00-00-C00.JRS"},
+		{"missing everywhere", "", "00/00-00-Z00.JRS", "00/00-00-Z00.JRS"},
+		{"file outside the tree", "ru", "Other/extra.jrs", "Other/extra.jrs"},
+		{"script root only", "ru", "Script", "Script"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newTestLocalizer(t, "en", test.fallback, localeTree...)
+			if got := l.ScriptPath(test.script); got != test.want {
+				t.Errorf("ScriptPath(%q) = %q, want %q", test.script, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	l := newTestLocalizer(t, "en", "ru", localeTree...)
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"Title/Title.png", "Localized/ENGLISH/Title/Title.png"},
+		{`Title\Title.png`, "Localized/ENGLISH/Title/Title.png"},
+		{"Title/Logo.png", "Title/Logo.png"},
+		{"Title/Missing.png", "Title/Missing.png"},
+	}
+	for _, test := range tests {
+		if got := l.Resolve(test.file); got != test.want {
+			t.Errorf("Resolve(%q) = %q, want %q", test.file, got, test.want)
+		}
+	}
+
+	// Overrides belong to one language only
+	l.SetLanguage("ru")
+	if got := l.Resolve("Title/Title.png"); got != "Title/Title.png" {
+		t.Errorf("Russian Resolve used %q", got)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	l := newTestLocalizer(t, "de", "", localeTree...)
+
+	if got := strings.Join(l.GetLanguages(), ","); got != "en,ja,ru" {
+		t.Errorf("languages %s, want en,ja,ru", got)
+	}
+	if got := l.GetLanguage(); got != "de" {
+		t.Errorf("language %s, want de even without scripts", got)
+	}
+
+	// Without German scripts every name falls back to an available tree
+	if got := l.ScriptPath("00/00-00-B00.JRS"); got != "Script/RUSSIAN/00/00-00-B00.JRS" {
+		t.Errorf("ScriptPath without the language = %q", got)
+	}
+}
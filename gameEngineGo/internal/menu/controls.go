@@ -0,0 +1,105 @@
+package menu
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+
+	"school-days-engine/internal/input"
+)
+
+// SetBindingsHandler sets the callback used by the controls menu to save changed bindings
+func (m *Manager) SetBindingsHandler(handler func()) {
+	m.bindingsHandler = handler
+}
+
+// showInputSettings displays the controls screen
+func (m *Manager) showInputSettings() {
+	log.Println("Showing control settings")
+	m.capturing = false
+	m.loadMenu("Settings/Input")
+}
+
+// handleInputSettingsClick handles controls screen interactions. Region 0 is
+// Back, region 1 restores the defaults and region 2+i rebinds action i.
+func (m *Manager) handleInputSettingsClick(regionIndex int) {
+	switch {
+	case regionIndex == 0:
+		m.prevState()
+	case regionIndex == 1:
+		m.input.ResetBindings()
+		m.bindingsChanged()
+	case regionIndex-2 < int(input.GameActionsCount):
+		m.rebinding = input.Action(regionIndex - 2)
+		m.capturing = true
+		log.Printf("Waiting for a new binding for %s", m.rebinding)
+	}
+}
+
+// captureBinding binds the first key or button pressed to the action being
+// rebound. It replaces the action's bindings on the same device, so
+// rebinding a key keeps the gamepad binding. Escape cancels.
+func (m *Manager) captureBinding() {
+	binding, ok := m.input.CaptureBinding()
+	if !ok {
+		return
+	}
+	m.capturing = false
+
+	if binding == (input.Binding{Device: input.DeviceKey, Code: int(ebiten.KeyEscape)}) {
+		log.Println("Rebinding cancelled")
+		return
+	}
+
+	bindings := []input.Binding{binding}
+	for _, existing := range m.input.GetBindings(m.rebinding) {
+		if existing.Device != binding.Device {
+			bindings = append(bindings, existing)
+		}
+	}
+	m.input.SetBindings(m.rebinding, bindings)
+	log.Printf("Bound %s to %s", m.rebinding, binding)
+
+	m.bindingsChanged()
+}
+
+// bindingsChanged notifies the game that the bindings should be saved
+func (m *Manager) bindingsChanged() {
+	if m.bindingsHandler != nil {
+		m.bindingsHandler()
+	}
+}
+
+// createInputSettingsRegions creates the fallback regions of the controls menu
+func (m *Manager) createInputSettingsRegions() {
+	m.regions = append(m.regions, &Region{Index: 0, X1: 0.125, Y1: 1.563, X2: 0.250, Y2: 1.719, State: MenuDefault}) // Back
+	m.regions = append(m.regions, &Region{Index: 1, X1: 0.625, Y1: 1.563, X2: 0.875, Y2: 1.719, State: MenuDefault}) // Defaults
+
+	for action := range int(input.GameActionsCount) {
+		y1 := 0.156 + float64(action)*0.094
+		y2 := y1 + 0.078
+		m.regions = append(m.regions, &Region{Index: 2 + action, X1: 0.125, Y1: y1, X2: 0.875, Y2: y2, State: MenuDefault})
+	}
+}
+
+// drawInputSettings prints the bindings of every action
+func (m *Manager) drawInputSettings(screen *ebiten.Image) {
+	x := m.screenWidth / 2
+	for action := input.Action(0); action < input.GameActionsCount; action++ {
+		bindings := m.input.GetBindings(action)
+		names := make([]string, len(bindings))
+		for i, binding := range bindings {
+			names[i] = binding.String()
+		}
+		line := fmt.Sprintf("%s: %s", action, strings.Join(names, ", "))
+		ebitenutil.DebugPrintAt(screen, line, x, 70+int(action)*20)
+	}
+
+	if m.capturing {
+		prompt := fmt.Sprintf("Press a key or button for %s (Escape cancels)", m.rebinding)
+		ebitenutil.DebugPrintAt(screen, prompt, x, 70+int(input.GameActionsCount)*20+20)
+	}
+}
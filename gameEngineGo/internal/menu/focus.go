@@ -0,0 +1,137 @@
+package menu
+
+import (
+	"math"
+
+	"school-days-engine/internal/input"
+)
+
+// focusCandidates returns the regions focus can move between: the dialog
+// buttons while a dialog is open, the menu regions otherwise
+func (m *Manager) focusCandidates() []*Region {
+	if !m.dlgActive {
+		return m.regions
+	}
+
+	candidates := make([]*Region, 0, len(m.dlgRegions))
+	for _, region := range m.dlgRegions {
+		if region != nil {
+			candidates = append(candidates, region)
+		}
+	}
+	return candidates
+}
+
+// updateFocus moves keyboard/gamepad focus. Moving the mouse hands control
+// back to the cursor.
+func (m *Manager) updateFocus() {
+	if m.input.HasMouseMoved() {
+		m.focused = nil
+	}
+
+	direction := m.input.GetNavigation()
+	if direction == input.NavNone {
+		return
+	}
+
+	candidates := m.focusCandidates()
+	if m.focused == nil || !containsRegion(candidates, m.focused) {
+		m.focused = firstFocusable(candidates)
+		return
+	}
+
+	if next := nearestRegion(candidates, m.focused, direction); next != nil {
+		m.focused = next
+	} else if next := wrapRegion(candidates, m.focused, direction); next != nil {
+		m.focused = next
+	}
+}
+
+// isRegionActive returns whether a region is hovered, by focus when the
+// keyboard or gamepad is in use, by the cursor otherwise
+func (m *Manager) isRegionActive(region *Region, normX, normY float64) bool {
+	if m.focused != nil {
+		return region == m.focused
+	}
+	return region.IsMouseOver(normX, normY)
+}
+
+// firstFocusable returns the top-left enabled region
+func firstFocusable(regions []*Region) *Region {
+	var first *Region
+	for _, region := range regions {
+		if region.State == MenuDisable {
+			continue
+		}
+		if first == nil || region.Y1 < first.Y1 || (region.Y1 == first.Y1 && region.X1 < first.X1) {
+			first = region
+		}
+	}
+	return first
+}
+
+// nearestRegion returns the closest enabled region in a direction from the
+// current one, measured between region centres. Offsets across the direction
+// of travel count double, so movement prefers staying in the same row/column.
+func nearestRegion(regions []*Region, from *Region, direction int) *Region {
+	return bestRegion(regions, from, direction, func(along float64) bool { return along > 0 })
+}
+
+// wrapRegion returns the region focus wraps to when none lies in a direction:
+// the farthest one the opposite way, again preferring the same row/column
+func wrapRegion(regions []*Region, from *Region, direction int) *Region {
+	return bestRegion(regions, from, direction, func(along float64) bool { return along < 0 })
+}
+
+// bestRegion returns the enabled region with the lowest score among those
+// whose offset along the direction of travel is accepted
+func bestRegion(regions []*Region, from *Region, direction int, accept func(along float64) bool) *Region {
+	fromX, fromY := from.center()
+
+	var best *Region
+	bestScore := math.Inf(1)
+	for _, region := range regions {
+		if region == from || region.State == MenuDisable {
+			continue
+		}
+
+		x, y := region.center()
+		dx, dy := x-fromX, y-fromY
+
+		var along, across float64
+		switch direction {
+		case input.NavUp:
+			along, across = -dy, dx
+		case input.NavDown:
+			along, across = dy, dx
+		case input.NavLeft:
+			along, across = -dx, dy
+		case input.NavRight:
+			along, across = dx, dy
+		}
+		if !accept(along) {
+			continue
+		}
+
+		score := along + 2*math.Abs(across)
+		if score < bestScore {
+			best, bestScore = region, score
+		}
+	}
+	return best
+}
+
+// containsRegion returns whether region is in regions
+func containsRegion(regions []*Region, region *Region) bool {
+	for _, r := range regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// center returns the centre of a region in normalized coordinates
+func (r *Region) center() (float64, float64) {
+	return (r.X1 + r.X2) / 2, (r.Y1 + r.Y2) / 2
+}
@@ -0,0 +1,110 @@
+package menu
+
+import (
+	"testing"
+
+	"school-days-engine/internal/input"
+)
+
+// focusGrid returns a 2x3 grid of regions named A-F by rows, with D disabled:
+//
+//	A B
+//	C D
+//	E F
+func focusGrid() map[string]*Region {
+	grid := make(map[string]*Region)
+	for i, name := range []string{"A", "B", "C", "D", "E", "F"} {
+		x, y := 0.1+0.4*float64(i%2), 0.1+0.2*float64(i/2)
+		grid[name] = &Region{Index: i, X1: x, Y1: y, X2: x + 0.2, Y2: y + 0.1, State: MenuDefault}
+	}
+	grid["D"].State = MenuDisable
+	return grid
+}
+
+// regionList returns the regions of a grid in index order
+func regionList(grid map[string]*Region) []*Region {
+	regions := make([]*Region, len(grid))
+	for _, region := range grid {
+		regions[region.Index] = region
+	}
+	return regions
+}
+
+// regionName returns the name of a region in a grid, or "" for nil
+func regionName(grid map[string]*Region, region *Region) string {
+	for name, r := range grid {
+		if r == region {
+			return name
+		}
+	}
+	return ""
+}
+
+func TestFocusNavigation(t *testing.T) {
+	tests := []struct {
+		from      string
+		direction int
+		want      string
+	}{
+		{"A", input.NavDown, "C"},
+		{"C", input.NavDown, "E"},
+		{"A", input.NavRight, "B"},
+		{"F", input.NavLeft, "E"},
+		{"F", input.NavUp, "B"}, // Past the disabled D
+		{"B", input.NavDown, "F"},
+		{"E", input.NavUp, "C"},
+
+		// Wrapping to the far end of the same column or row
+		{"E", input.NavDown, "A"},
+		{"A", input.NavUp, "E"},
+		{"B", input.NavUp, "F"},
+		{"B", input.NavRight, "A"},
+		{"A", input.NavLeft, "B"},
+		{"E", input.NavLeft, "F"},
+	}
+
+	grid := focusGrid()
+	regions := regionList(grid)
+	for _, test := range tests {
+		next := nearestRegion(regions, grid[test.from], test.direction)
+		if next == nil {
+			next = wrapRegion(regions, grid[test.from], test.direction)
+		}
+		if got := regionName(grid, next); got != test.want {
+			t.Errorf("from %s direction %d: focus %q, want %q", test.from, test.direction, got, test.want)
+		}
+	}
+}
+
+func TestFocusSingleRow(t *testing.T) {
+	left := &Region{X1: 0.1, Y1: 0.5, X2: 0.3, Y2: 0.6}
+	right := &Region{X1: 0.6, Y1: 0.5, X2: 0.8, Y2: 0.6}
+	regions := []*Region{left, right}
+
+	// Nothing above or below a single row, even by wrapping
+	for _, direction := range []int{input.NavUp, input.NavDown} {
+		if next := nearestRegion(regions, left, direction); next != nil {
+			t.Errorf("direction %d moved within the row", direction)
+		}
+		if next := wrapRegion(regions, left, direction); next != nil {
+			t.Errorf("direction %d wrapped within the row", direction)
+		}
+	}
+}
+
+func TestFirstFocusable(t *testing.T) {
+	grid := focusGrid()
+	regions := regionList(grid)
+	if got := regionName(grid, firstFocusable(regions)); got != "A" {
+		t.Errorf("first focus %q, want A", got)
+	}
+
+	grid["A"].State = MenuDisable
+	if got := regionName(grid, firstFocusable(regions)); got != "B" {
+		t.Errorf("first focus with A disabled %q, want B", got)
+	}
+
+	if firstFocusable(nil) != nil {
+		t.Error("focus found without regions")
+	}
+}
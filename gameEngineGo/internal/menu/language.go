@@ -0,0 +1,48 @@
+package menu
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// LanguageSettings is implemented by the game to show and change the language
+type LanguageSettings interface {
+	// GetLanguageName returns the name of the current language
+	GetLanguageName() string
+	// CycleLanguage switches to the next available language
+	CycleLanguage()
+}
+
+// SetLanguageSettings sets the game side of the language option
+func (m *Manager) SetLanguageSettings(settings LanguageSettings) {
+	m.languageSettings = settings
+}
+
+// SetPathResolver sets the function that maps menu files to their
+// translated versions
+func (m *Manager) SetPathResolver(resolve func(filename string) string) {
+	m.resolvePath = resolve
+}
+
+// localize returns the translated version of a menu file
+func (m *Manager) localize(filename string) string {
+	if m.resolvePath == nil {
+		return filename
+	}
+	return m.resolvePath(filename)
+}
+
+// cycleLanguage switches the language from the settings menu
+func (m *Manager) cycleLanguage() {
+	if m.languageSettings != nil {
+		m.languageSettings.CycleLanguage()
+	}
+}
+
+// drawSettings prints the current language on the settings screen
+func (m *Manager) drawSettings(screen *ebiten.Image) {
+	if m.languageSettings == nil {
+		return
+	}
+	ebitenutil.DebugPrintAt(screen, "Language: "+m.languageSettings.GetLanguageName(), m.screenWidth/2, 200)
+}
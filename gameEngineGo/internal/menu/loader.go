@@ -3,7 +3,7 @@ package menu
 import (
 	"log"
 
-	"school-days-engine/internal/graphics"
+	"school-days-engine/internal/render"
 )
 
 // loadMenu loads a menu layout and regions (matches C++ load_menu)
@@ -16,8 +16,8 @@ func (m *Manager) loadMenu(name string) {
 
 		// Fall back to sample regions
 		m.clearRegions()
-		m.graphics.LoadTexture(name+".png", graphics.LayerMenu)
-		m.graphics.LoadTexture(name+"_chip.png", graphics.LayerMenuOverlay)
+		m.graphics.LoadTexture(m.localize(name+".png"), render.LayerMenu)
+		m.graphics.LoadTexture(m.localize(name+"_chip.png"), render.LayerMenuOverlay)
 		m.createSampleRegions(name)
 	}
 }
@@ -30,11 +30,11 @@ func (m *Manager) loadMenuFromGLMap(name string) error {
 	m.clearRegions()
 
 	// Load menu graphics
-	m.graphics.LoadTexture(name+".png", graphics.LayerMenu)
-	m.graphics.LoadTexture(name+"_chip.png", graphics.LayerMenuOverlay)
+	m.graphics.LoadTexture(m.localize(name+".png"), render.LayerMenu)
+	m.graphics.LoadTexture(m.localize(name+"_chip.png"), render.LayerMenuOverlay)
 
 	// Try to load .glmap file for regions
-	glmapPath := "System/" + name + ".glmap"
+	glmapPath := m.localize("System/" + name + ".glmap")
 	if reader, err := m.filesystem.Open(glmapPath); err == nil {
 		defer reader.Close()
 
@@ -61,7 +61,7 @@ func (m *Manager) loadMenuFromGLMap(name string) error {
 	}
 
 	// Try to load _chip.glmap file for visual feedback
-	chipGlmapPath := "System/" + name + "_chip.glmap"
+	chipGlmapPath := m.localize("System/" + name + "_chip.glmap")
 	if reader, err := m.filesystem.Open(chipGlmapPath); err == nil {
 		defer reader.Close()
 
@@ -9,13 +9,13 @@ import (
 
 	"school-days-engine/internal/audio"
 	"school-days-engine/internal/filesystem"
-	"school-days-engine/internal/graphics"
 	"school-days-engine/internal/input"
+	"school-days-engine/internal/render"
 )
 
 // Manager handles menu system and user interface (matches C++ Menu class)
 type Manager struct {
-	graphics   *graphics.Renderer
+	graphics   render.Backend
 	audio      *audio.Manager
 	input      *input.Manager
 	filesystem *filesystem.Manager
@@ -29,14 +29,35 @@ type Manager struct {
 	chips      []*ChipRegion
 	dlgRegions [2]*Region
 	dlgChips   [2]*ChipRegion
+	focused    *Region // Keyboard/gamepad focus, nil while the mouse is used
 
 	screenWidth  int
 	screenHeight int
 	debugMode    bool
+
+	// Window mode, auto-advance and skip mode toggles provided by the game,
+	// and the callback run when the settings menu is left
+	fullscreenHandler     func()
+	autoModeHandler       func()
+	skipModeHandler       func()
+	settingsClosedHandler func()
+
+	// Sound options provided by the game
+	soundSettings SoundSettings
+
+	// Language option and translated menu files provided by the game
+	languageSettings LanguageSettings
+	resolvePath      func(filename string) string
+
+	// Rebinding screen: the action waiting for a new binding, and the
+	// callback that saves changed bindings
+	capturing       bool
+	rebinding       input.Action
+	bindingsHandler func()
 }
 
 // NewManager creates a new menu manager (matches C++ Menu constructor)
-func NewManager(gfx *graphics.Renderer, aud *audio.Manager, inp *input.Manager, fs *filesystem.Manager, screenW, screenH int) *Manager {
+func NewManager(gfx render.Backend, aud *audio.Manager, inp *input.Manager, fs *filesystem.Manager, screenW, screenH int) *Manager {
 	return &Manager{
 		graphics:     gfx,
 		audio:        aud,
@@ -49,7 +70,6 @@ func NewManager(gfx *graphics.Renderer, aud *audio.Manager, inp *input.Manager,
 		chips:        make([]*ChipRegion, 0),
 		screenWidth:  screenW,
 		screenHeight: screenH,
-		debugMode:    true, // Enable debug mode for development
 	}
 }
 
@@ -79,21 +99,7 @@ func (m *Manager) Update() error {
 func (m *Manager) Draw(screen *ebiten.Image) {
 	// Draw debug information
 	if m.debugMode {
-		debugText := "Menu State: "
-		switch m.state {
-		case MenuInit:
-			debugText += "INIT"
-		case MenuSplash:
-			debugText += "SPLASH"
-		case MenuTitle:
-			debugText += "TITLE"
-		case MenuLoad:
-			debugText += "LOAD"
-		case MenuSettings:
-			debugText += "SETTINGS"
-		default:
-			debugText += "UNKNOWN"
-		}
+		debugText := "Menu State: " + GetStateInfo(m.state).Name
 
 		ebitenutil.DebugPrintAt(screen, debugText, 10, 30)
 
@@ -108,6 +114,64 @@ func (m *Manager) Draw(screen *ebiten.Image) {
 			ebitenutil.DebugPrintAt(screen, regionText, 10, 70+i*20)
 		}
 	}
+
+	switch m.state {
+	case MenuSettings:
+		m.drawSettings(screen)
+	case MenuSettingsSound:
+		m.drawSoundSettings(screen)
+	case MenuSettingsInput:
+		m.drawInputSettings(screen)
+	}
+}
+
+// SetFullscreenHandler sets the callback used by the settings menu to toggle fullscreen
+func (m *Manager) SetFullscreenHandler(handler func()) {
+	m.fullscreenHandler = handler
+}
+
+// SetAutoModeHandler sets the callback used by the settings menu to toggle auto-advance
+func (m *Manager) SetAutoModeHandler(handler func()) {
+	m.autoModeHandler = handler
+}
+
+// SetSkipModeHandler sets the callback used by the settings menu to switch
+// between skipping read text and skipping all text
+func (m *Manager) SetSkipModeHandler(handler func()) {
+	m.skipModeHandler = handler
+}
+
+// SetSettingsClosedHandler sets the callback run when the player leaves the
+// settings menu, used to save the settings
+func (m *Manager) SetSettingsClosedHandler(handler func()) {
+	m.settingsClosedHandler = handler
+}
+
+// SetDebugMode shows the menu state and regions on screen
+func (m *Manager) SetDebugMode(enabled bool) {
+	m.debugMode = enabled
+}
+
+// EnterGame leaves the menus for a running scene
+func (m *Manager) EnterGame() {
+	log.Printf("Menu state changed: %d -> %d", m.state, MenuGame)
+	m.clearRegions()
+	m.graphics.UnloadTexture(render.LayerMenu)
+	m.graphics.UnloadTexture(render.LayerMenuOverlay)
+	m.dlgActive = false
+	m.state = MenuGame
+	m.inGame = true
+}
+
+// SetState jumps straight to a menu state, for the developer console
+func (m *Manager) SetState(state int) {
+	if state == MenuGame {
+		m.EnterGame()
+		return
+	}
+	m.dlgActive = false
+	m.inGame = false
+	m.changeToState(state)
 }
 
 // GetState returns the current menu state
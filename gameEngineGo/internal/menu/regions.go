@@ -48,6 +48,7 @@ func (r *Region) String() string {
 func (m *Manager) clearRegions() {
 	m.regions = m.regions[:0]
 	m.chips = m.chips[:0]
+	m.focused = nil
 }
 
 // processInput handles user input and region interaction (matches C++ region_check)
@@ -55,15 +56,25 @@ func (m *Manager) processInput() {
 	// Get normalized mouse coordinates
 	normX, normY := m.input.GetNormalizedMousePosition(m.screenWidth, m.screenHeight)
 
-	// Check right click for going back
-	if m.input.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+	// The rebinding screen is waiting for a new key or button
+	if m.capturing {
+		m.captureBinding()
+		return
+	}
+
+	// Back action (Escape, right click or gamepad B by default)
+	if m.input.IsCancelJustPressed() {
 		m.prevState()
 		return
 	}
 
+	// Keyboard and gamepad focus
+	m.updateFocus()
+	confirm := m.focused != nil && m.input.IsConfirmJustPressed()
+
 	// Handle dialog input separately if active
 	if m.dlgActive {
-		m.processDialogInput(normX, normY)
+		m.processDialogInput(normX, normY, confirm)
 		return
 	}
 
@@ -73,28 +84,24 @@ func (m *Manager) processInput() {
 			continue // Skip disabled regions
 		}
 
-		if region.IsMouseOver(normX, normY) {
-			// Mouse is over region
+		if m.isRegionActive(region, normX, normY) {
+			// Mouse is over region, or it has the focus
 			m.handleRegionMouseOver(region)
 
 			// Check for click
-			if m.input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			if m.input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || confirm {
 				m.handleRegionClick(region)
+				return
 			}
 		} else {
 			// Mouse is not over region - revert hover states
 			m.handleRegionMouseLeave(region)
 		}
 	}
-
-	// Handle keyboard shortcuts
-	if m.input.IsKeyJustPressed(ebiten.KeyEscape) {
-		m.prevState()
-	}
 }
 
 // processDialogInput handles input when a dialog is active
-func (m *Manager) processDialogInput(normX, normY float64) {
+func (m *Manager) processDialogInput(normX, normY float64, confirm bool) {
 	// Process dialog regions similar to main regions
 	for i := range m.dlgRegions {
 		region := m.dlgRegions[i]
@@ -102,10 +109,11 @@ func (m *Manager) processDialogInput(normX, normY float64) {
 			continue
 		}
 
-		if region.IsMouseOver(normX, normY) {
+		if m.isRegionActive(region, normX, normY) {
 			m.handleRegionMouseOver(region)
-			if m.input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			if m.input.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || confirm {
 				m.handleDialogRegionClick(i)
+				return
 			}
 		} else {
 			m.handleRegionMouseLeave(region)
@@ -162,6 +170,10 @@ func (m *Manager) onRegionClicked(regionIndex int) {
 		m.handleLoadMenuClick(regionIndex)
 	case MenuSettings:
 		m.handleSettingsMenuClick(regionIndex)
+	case MenuSettingsSound:
+		m.handleSoundSettingsClick(regionIndex)
+	case MenuSettingsInput:
+		m.handleInputSettingsClick(regionIndex)
 	}
 }
 
@@ -194,6 +206,22 @@ func (m *Manager) handleSettingsMenuClick(regionIndex int) {
 		m.changeToState(MenuSettingsSound)
 	case 1: // Back
 		m.prevState()
+	case 2: // Window mode
+		if m.fullscreenHandler != nil {
+			m.fullscreenHandler()
+		}
+	case 3: // Controls
+		m.changeToState(MenuSettingsInput)
+	case 4: // Auto mode
+		if m.autoModeHandler != nil {
+			m.autoModeHandler()
+		}
+	case 5: // Language
+		m.cycleLanguage()
+	case 6: // Skip mode
+		if m.skipModeHandler != nil {
+			m.skipModeHandler()
+		}
 	}
 }
 
@@ -238,6 +266,17 @@ func (m *Manager) createSampleRegions(menuName string) {
 		// Create regions for settings options using normalized coordinates
 		m.regions = append(m.regions, &Region{Index: 0, X1: 0.125, Y1: 0.625, X2: 0.500, Y2: 0.781, State: MenuDefault}) // Sound
 		m.regions = append(m.regions, &Region{Index: 1, X1: 0.125, Y1: 1.563, X2: 0.250, Y2: 1.719, State: MenuDefault}) // Back
+		m.regions = append(m.regions, &Region{Index: 2, X1: 0.125, Y1: 0.844, X2: 0.500, Y2: 1.000, State: MenuDefault}) // Window mode
+		m.regions = append(m.regions, &Region{Index: 3, X1: 0.125, Y1: 1.063, X2: 0.500, Y2: 1.219, State: MenuDefault}) // Controls
+		m.regions = append(m.regions, &Region{Index: 4, X1: 0.125, Y1: 1.281, X2: 0.500, Y2: 1.438, State: MenuDefault}) // Auto mode
+		m.regions = append(m.regions, &Region{Index: 5, X1: 0.563, Y1: 0.625, X2: 0.938, Y2: 0.781, State: MenuDefault}) // Language
+		m.regions = append(m.regions, &Region{Index: 6, X1: 0.563, Y1: 0.844, X2: 0.938, Y2: 1.000, State: MenuDefault}) // Skip mode
+
+	case "Settings/Sound":
+		m.createSoundSettingsRegions()
+
+	case "Settings/Input":
+		m.createInputSettingsRegions()
 	}
 
 	log.Printf("Created %d regions for menu %s", len(m.regions), menuName)
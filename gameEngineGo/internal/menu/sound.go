@@ -0,0 +1,83 @@
+package menu
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Options of the sound settings menu
+const (
+	SoundOptionDucking = iota
+	SoundOptionDuckAmount
+	SoundOptionDuckAttack
+	SoundOptionDuckRelease
+	SoundOptionPersona       // Character whose voice the next two options change
+	SoundOptionPersonaVolume // Voice volume of that character
+	SoundOptionPersonaMuted  // Voice of that character on or off
+	SoundOptionsCount
+)
+
+// SoundSettings is implemented by the game to read and change sound options
+type SoundSettings interface {
+	// AdjustSoundOption changes an option by a number of steps (negative
+	// lowers it, toggles flip on any step)
+	AdjustSoundOption(option, step int)
+	// DescribeSoundOption returns the option label and current value
+	DescribeSoundOption(option int) string
+}
+
+// SetSoundSettings sets the game side of the sound settings menu
+func (m *Manager) SetSoundSettings(settings SoundSettings) {
+	m.soundSettings = settings
+}
+
+// showSoundSettings displays the sound settings screen
+func (m *Manager) showSoundSettings() {
+	log.Println("Showing sound settings")
+	m.loadMenu("Settings/Sound")
+}
+
+// handleSoundSettingsClick handles sound settings interactions. Region 0 is
+// Back, then each option has a "-" and a "+" region.
+func (m *Manager) handleSoundSettingsClick(regionIndex int) {
+	if regionIndex == 0 {
+		m.prevState()
+		return
+	}
+
+	option := (regionIndex - 1) / 2
+	if option >= SoundOptionsCount || m.soundSettings == nil {
+		return
+	}
+
+	step := 1
+	if (regionIndex-1)%2 == 0 {
+		step = -1
+	}
+	m.soundSettings.AdjustSoundOption(option, step)
+}
+
+// createSoundSettingsRegions creates the fallback regions of the sound settings menu
+func (m *Manager) createSoundSettingsRegions() {
+	m.regions = append(m.regions, &Region{Index: 0, X1: 0.125, Y1: 1.563, X2: 0.250, Y2: 1.719, State: MenuDefault}) // Back
+
+	for option := range SoundOptionsCount {
+		y1 := 0.625 + float64(option)*0.156
+		y2 := y1 + 0.125
+		m.regions = append(m.regions, &Region{Index: 1 + option*2, X1: 0.563, Y1: y1, X2: 0.656, Y2: y2, State: MenuDefault}) // -
+		m.regions = append(m.regions, &Region{Index: 2 + option*2, X1: 0.781, Y1: y1, X2: 0.875, Y2: y2, State: MenuDefault}) // +
+	}
+}
+
+// drawSoundSettings prints the current option values
+func (m *Manager) drawSoundSettings(screen *ebiten.Image) {
+	if m.soundSettings == nil {
+		return
+	}
+
+	for option := range SoundOptionsCount {
+		ebitenutil.DebugPrintAt(screen, m.soundSettings.DescribeSoundOption(option), m.screenWidth/2, 200+option*20)
+	}
+}
@@ -24,8 +24,27 @@ const (
 	MenuHistory       = 13
 	MenuTitleDlg      = 14
 	MenuClose         = 15
+	MenuSettingsInput = 16
 )
 
+// menuStates lists every defined menu state
+var menuStates = []int{
+	MenuInit, MenuSplash, MenuTitle, MenuPreTitle, MenuExitDlg, MenuExit,
+	MenuSettings, MenuPreSettings, MenuSettingsSound, MenuLoad, MenuPreLoad,
+	MenuReplay, MenuPreReplay, MenuRouteMap, MenuNewGame, MenuGame, MenuSave,
+	MenuHistory, MenuTitleDlg, MenuClose, MenuSettingsInput,
+}
+
+// IsMenuState reports whether state is one of the defined menu states
+func IsMenuState(state int) bool {
+	for _, defined := range menuStates {
+		if state == defined {
+			return true
+		}
+	}
+	return false
+}
+
 // Region states using iota for better Go practices
 const (
 	MenuDefault = iota
@@ -54,8 +73,14 @@ func GetStateInfo(state int) StateInfo {
 		return StateInfo{"LOAD", "Load game"}
 	case MenuSettings:
 		return StateInfo{"SETTINGS", "Settings menu"}
+	case MenuSettingsSound:
+		return StateInfo{"SETTINGS_SOUND", "Sound settings"}
+	case MenuSettingsInput:
+		return StateInfo{"SETTINGS_INPUT", "Control settings"}
 	case MenuExitDlg:
 		return StateInfo{"EXIT_DLG", "Exit confirmation"}
+	case MenuGame:
+		return StateInfo{"GAME", "In game"}
 	default:
 		return StateInfo{"UNKNOWN", "Unknown state"}
 	}
@@ -110,9 +135,15 @@ func (m *Manager) nextMenuState(index int) {
 // prevState goes back to the previous menu state
 func (m *Manager) prevState() {
 	switch m.state {
-	case MenuLoad, MenuSettings, MenuSettingsSound:
+	case MenuLoad, MenuSettings:
+		if m.state == MenuSettings && m.settingsClosedHandler != nil {
+			m.settingsClosedHandler()
+		}
 		m.state = MenuTitle
 		m.showTitle()
+	case MenuSettingsSound, MenuSettingsInput:
+		m.state = MenuSettings
+		m.showSettings()
 	case MenuExitDlg:
 		m.state = MenuTitle
 		m.showTitle()
@@ -125,6 +156,10 @@ func (m *Manager) changeToState(newState int) {
 	m.state = newState
 
 	switch newState {
+	case MenuSplash:
+		m.showSplash()
+	case MenuTitle:
+		m.showTitle()
 	case MenuLoad:
 		m.showLoad()
 	case MenuSettings:
@@ -132,8 +167,9 @@ func (m *Manager) changeToState(newState int) {
 	case MenuExitDlg:
 		m.showExitDialog()
 	case MenuSettingsSound:
-		log.Println("Showing sound settings")
-		// TODO: Implement sound settings menu
+		m.showSoundSettings()
+	case MenuSettingsInput:
+		m.showInputSettings()
 	}
 }
 
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"image/color"
+	"strings"
+)
+
+// Layer constants based on the original C++ engine
+const (
+	LayerBG          = 0
+	LayerBGOverlay0  = 1
+	LayerBGOverlay1  = 2
+	LayerBGOverlay2  = 3
+	LayerTitleBase   = 4
+	LayerMenu        = 5
+	LayerMenuOverlay = 6
+	LayerSysBase     = 7
+	LayerDlg         = 8
+	LayerDlgOverlay  = 9
+	LayerOverlay     = 10
+	LayersCount      = 11
+)
+
+// Backend is the layered compositor driven by the script scene player and
+// the menus. graphics.Renderer implements it on top of Ebiten, and
+// SoftwareRenderer implements it on image.RGBA for headless use.
+type Backend interface {
+	LoadTexture(filename string, layer int) error
+	UnloadTexture(layer int)
+	SetLayerVisible(layer int, visible bool)
+	SetLayerAlpha(layer int, alpha float64)
+	SetLayerPosition(layer int, x, y int)
+	SetLayerScale(layer int, scaleX, scaleY float64)
+	SetFade(alpha float64, toWhite bool)
+	GetScreenSize() (int, int)
+}
+
+// FileSystemInterface defines the filesystem operations needed to load textures
+type FileSystemInterface interface {
+	ReadFile(filename string) ([]byte, error)
+	Exists(filename string) bool
+}
+
+// NormalizeTextureName lowercases a texture name and adds ".png" if it has no extension
+func NormalizeTextureName(filename string) string {
+	normalizedName := strings.ToLower(filename)
+	if !strings.Contains(normalizedName, ".") {
+		normalizedName += ".png"
+	}
+	return normalizedName
+}
+
+// PlaceholderColor returns the fill color used when a texture fails to load
+func PlaceholderColor(filename string) color.RGBA {
+	lowerName := strings.ToLower(filename)
+
+	switch {
+	case strings.Contains(lowerName, "bg") || strings.Contains(lowerName, "background"):
+		return color.RGBA{50, 50, 100, 255} // Dark blue
+	case strings.Contains(lowerName, "title"):
+		return color.RGBA{100, 50, 50, 255} // Dark red
+	case strings.Contains(lowerName, "menu"):
+		return color.RGBA{50, 100, 50, 255} // Dark green
+	case strings.Contains(lowerName, "chip"):
+		return color.RGBA{100, 100, 50, 255} // Dark yellow
+	default:
+		return color.RGBA{80, 80, 80, 255} // Gray
+	}
+}
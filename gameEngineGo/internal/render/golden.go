@@ -0,0 +1,104 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"school-days-engine/internal/script"
+)
+
+// RenderScriptAt plays a script on a software renderer up to position (ms)
+// and returns the composed frame. The timeline is stepped in fixed
+// increments so the result does not depend on wall-clock time.
+func RenderScriptAt(scene *script.Script, renderer *SoftwareRenderer, position int64) *image.RGBA {
+	const stepMs = 1000 / 60
+
+	engine := script.NewEngine()
+	engine.SetHandler(NewScenePlayer(renderer))
+	engine.LoadScript(scene)
+	engine.Start()
+
+	for engine.GetPosition()+stepMs < position {
+		engine.Step(stepMs)
+	}
+	engine.Step(position - engine.GetPosition())
+
+	return renderer.Frame()
+}
+
+// DiffImages returns the number of pixels whose channels differ by more than tolerance
+func DiffImages(a, b image.Image, tolerance uint8) (int, error) {
+	if a.Bounds().Size() != b.Bounds().Size() {
+		return 0, fmt.Errorf("image sizes differ: %v vs %v", a.Bounds().Size(), b.Bounds().Size())
+	}
+
+	limit := uint32(tolerance) * 0x101 // 8-bit tolerance in 16-bit color space
+	diff := 0
+	aMin, bMin := a.Bounds().Min, b.Bounds().Min
+	for y := 0; y < a.Bounds().Dy(); y++ {
+		for x := 0; x < a.Bounds().Dx(); x++ {
+			r1, g1, b1, a1 := a.At(aMin.X+x, aMin.Y+y).RGBA()
+			r2, g2, b2, a2 := b.At(bMin.X+x, bMin.Y+y).RGBA()
+			if absDiff(r1, r2) > limit || absDiff(g1, g2) > limit || absDiff(b1, b2) > limit || absDiff(a1, a2) > limit {
+				diff++
+			}
+		}
+	}
+	return diff, nil
+}
+
+// CompareGolden compares a frame with a golden PNG. With update set the frame
+// is written as the new golden; otherwise a missing golden is an error, so a
+// deleted or misnamed file cannot turn the comparison into a silent pass.
+func CompareGolden(frame image.Image, goldenPath string, tolerance uint8, update bool) error {
+	if update {
+		return writePNG(frame, goldenPath)
+	}
+
+	file, err := os.Open(goldenPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("golden %s does not exist (run with -update to create it)", goldenPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open golden %s: %w", goldenPath, err)
+	}
+	defer file.Close()
+
+	golden, err := png.Decode(file)
+	if err != nil {
+		return fmt.Errorf("failed to decode golden %s: %w", goldenPath, err)
+	}
+
+	diff, err := DiffImages(frame, golden, tolerance)
+	if err != nil {
+		return fmt.Errorf("frame does not match golden %s: %w", goldenPath, err)
+	}
+	if diff > 0 {
+		return fmt.Errorf("frame does not match golden %s: %d pixels differ", goldenPath, diff)
+	}
+	return nil
+}
+
+// writePNG writes an image to a PNG file
+func writePNG(img image.Image, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return nil
+}
+
+// absDiff returns |a - b| for color channels
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
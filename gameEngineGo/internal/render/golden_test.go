@@ -0,0 +1,57 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"school-days-engine/internal/script"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testdataFS serves fixture files from testdata by lower-case name
+type testdataFS struct{}
+
+func (testdataFS) ReadFile(filename string) ([]byte, error) {
+	return os.ReadFile(filepath.Join("testdata", strings.ToLower(filename)))
+}
+
+func (fs testdataFS) Exists(filename string) bool {
+	_, err := fs.ReadFile(filename)
+	return err == nil
+}
+
+func TestRenderScriptGolden(t *testing.T) {
+	scene, err := script.LoadJRS(testdataFS{}, "scene.jrs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// First BG alone, halfway through its fade in, second BG alone, and
+	// halfway through the white fade out
+	for _, position := range []int64{250, 750, 1250, 1750} {
+		renderer := NewSoftwareRenderer(64, 48, testdataFS{})
+		frame := RenderScriptAt(scene, renderer, position)
+
+		golden := filepath.Join("testdata", fmt.Sprintf("scene_%04d.png", position))
+		if err := CompareGolden(frame, golden, 2, *update); err != nil {
+			t.Errorf("at %d ms: %v", position, err)
+		}
+	}
+}
+
+func TestCompareGoldenMissing(t *testing.T) {
+	renderer := NewSoftwareRenderer(8, 8, testdataFS{})
+	golden := filepath.Join(t.TempDir(), "missing.png")
+
+	if err := CompareGolden(renderer.Frame(), golden, 0, false); err == nil {
+		t.Fatal("missing golden compared as a match")
+	}
+	if _, err := os.Stat(golden); !os.IsNotExist(err) {
+		t.Fatal("missing golden was written without -update")
+	}
+}
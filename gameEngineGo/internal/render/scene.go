@@ -0,0 +1,69 @@
+package render
+
+import (
+	"log"
+
+	"school-days-engine/internal/filesystem"
+	"school-days-engine/internal/script"
+)
+
+// ScenePlayer applies the visual JRS actions (backgrounds and fades) to a
+// Backend. It implements script.ActionHandler so it can run without audio.
+type ScenePlayer struct {
+	backend Backend
+}
+
+// NewScenePlayer creates a scene player drawing into backend
+func NewScenePlayer(backend Backend) *ScenePlayer {
+	return &ScenePlayer{backend: backend}
+}
+
+// StartAction handles an action reaching its start time (matches Qt runAction)
+func (p *ScenePlayer) StartAction(action *script.Action) {
+	switch action.Action {
+	case script.ActionCreateBG:
+		if err := p.backend.LoadTexture(filesystem.NormalizeName(action.File), LayerBG); err != nil {
+			log.Printf("Warning: failed to create BG %s: %v", action.File, err)
+		}
+
+	case script.ActionBlackFade, script.ActionWhiteFade:
+		p.backend.SetFade(FadeAlpha(action, action.Start), action.Action == script.ActionWhiteFade)
+	}
+}
+
+// UpdateAction handles a running action each frame
+func (p *ScenePlayer) UpdateAction(action *script.Action, position int64) {
+	switch action.Action {
+	case script.ActionBlackFade, script.ActionWhiteFade:
+		p.backend.SetFade(FadeAlpha(action, position), action.Action == script.ActionWhiteFade)
+	}
+}
+
+// EndAction handles an action reaching its end time (matches Qt action_stop).
+// A background without an end stays until the next one replaces it.
+func (p *ScenePlayer) EndAction(action *script.Action) {
+	switch action.Action {
+	case script.ActionCreateBG:
+		if action.HasEnd() {
+			p.backend.UnloadTexture(LayerBG)
+		}
+
+	case script.ActionBlackFade, script.ActionWhiteFade:
+		p.backend.SetFade(FadeAlpha(action, action.End), action.Action == script.ActionWhiteFade)
+	}
+}
+
+// FadeAlpha returns the fade overlay opacity of a fade action at a position.
+// "OUT" fades the scene out (overlay 0 -> 1), "IN" fades it in (overlay 1 -> 0).
+func FadeAlpha(action *script.Action, position int64) float64 {
+	progress := 1.0
+	if duration := action.Duration(); duration > 0 {
+		progress = float64(position-action.Start) / float64(duration)
+	}
+	progress = clamp01(progress)
+
+	if action.Direction == "IN" {
+		return 1.0 - progress
+	}
+	return progress
+}
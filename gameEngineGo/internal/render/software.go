@@ -0,0 +1,219 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // JPEG decoder
+	_ "image/png"  // PNG decoder
+	"log"
+)
+
+// softwareLayer is the state of a single layer of the software renderer
+type softwareLayer struct {
+	image   image.Image
+	visible bool
+	alpha   float64
+	x, y    int
+	scaleX  float64
+	scaleY  float64
+}
+
+// SoftwareRenderer composes layers into an image.RGBA on the CPU. It follows
+// the same drawing rules as graphics.Renderer but needs no GPU or window, so
+// it can run under go test and in headless tools.
+type SoftwareRenderer struct {
+	screenWidth  int
+	screenHeight int
+	filesystem   FileSystemInterface
+
+	layers [LayersCount]softwareLayer
+	cache  map[string]image.Image
+
+	fadeAlpha   float64
+	fadeToWhite bool
+}
+
+// NewSoftwareRenderer creates a software renderer with the given screen size
+func NewSoftwareRenderer(width, height int, filesystem FileSystemInterface) *SoftwareRenderer {
+	r := &SoftwareRenderer{
+		screenWidth:  width,
+		screenHeight: height,
+		filesystem:   filesystem,
+		cache:        make(map[string]image.Image),
+	}
+
+	for i := range r.layers {
+		r.layers[i] = softwareLayer{alpha: 1.0, scaleX: 1.0, scaleY: 1.0}
+	}
+	return r
+}
+
+// LoadTexture decodes a texture into a layer, using a placeholder if it fails
+func (r *SoftwareRenderer) LoadTexture(filename string, layer int) error {
+	if layer < 0 || layer >= LayersCount {
+		return fmt.Errorf("invalid layer index: %d", layer)
+	}
+
+	img, err := r.loadImage(filename)
+	if err != nil {
+		log.Printf("Warning: Failed to load texture %s: %v", filename, err)
+		img = solidImage(r.screenWidth, r.screenHeight, PlaceholderColor(filename))
+	}
+
+	r.layers[layer].image = img
+	r.layers[layer].visible = true
+	return nil
+}
+
+// UnloadTexture removes the texture from a layer
+func (r *SoftwareRenderer) UnloadTexture(layer int) {
+	if layer < 0 || layer >= LayersCount {
+		return
+	}
+	r.layers[layer].image = nil
+	r.layers[layer].visible = false
+}
+
+// SetLayerVisible sets the visibility of a layer
+func (r *SoftwareRenderer) SetLayerVisible(layer int, visible bool) {
+	if layer < 0 || layer >= LayersCount {
+		return
+	}
+	r.layers[layer].visible = visible
+}
+
+// SetLayerAlpha sets the alpha transparency of a layer
+func (r *SoftwareRenderer) SetLayerAlpha(layer int, alpha float64) {
+	if layer < 0 || layer >= LayersCount {
+		return
+	}
+	r.layers[layer].alpha = clamp01(alpha)
+}
+
+// SetLayerPosition sets the position of a layer
+func (r *SoftwareRenderer) SetLayerPosition(layer int, x, y int) {
+	if layer < 0 || layer >= LayersCount {
+		return
+	}
+	r.layers[layer].x = x
+	r.layers[layer].y = y
+}
+
+// SetLayerScale sets the scale of a layer
+func (r *SoftwareRenderer) SetLayerScale(layer int, scaleX, scaleY float64) {
+	if layer < 0 || layer >= LayersCount {
+		return
+	}
+	r.layers[layer].scaleX = scaleX
+	r.layers[layer].scaleY = scaleY
+}
+
+// SetFade sets the fade overlay
+func (r *SoftwareRenderer) SetFade(alpha float64, toWhite bool) {
+	r.fadeAlpha = clamp01(alpha)
+	r.fadeToWhite = toWhite
+}
+
+// GetScreenSize returns the screen dimensions
+func (r *SoftwareRenderer) GetScreenSize() (int, int) {
+	return r.screenWidth, r.screenHeight
+}
+
+// Frame composes all visible layers and the fade overlay into a new image
+func (r *SoftwareRenderer) Frame() *image.RGBA {
+	frame := solidImage(r.screenWidth, r.screenHeight, color.RGBA{0, 0, 0, 255})
+
+	// Draw layers in order from back to front
+	for i := range r.layers {
+		layer := &r.layers[i]
+		if layer.image == nil || !layer.visible {
+			continue
+		}
+		drawScaled(frame, layer.image, layer.x, layer.y, layer.scaleX, layer.scaleY, layer.alpha)
+	}
+
+	// Draw fade overlay if active
+	if r.fadeAlpha > 0 {
+		fadeColor := color.RGBA{0, 0, 0, 255}
+		if r.fadeToWhite {
+			fadeColor = color.RGBA{255, 255, 255, 255}
+		}
+		mask := image.NewUniform(color.Alpha{A: uint8(r.fadeAlpha * 255)})
+		draw.DrawMask(frame, frame.Bounds(), image.NewUniform(fadeColor), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	return frame
+}
+
+// loadImage decodes and caches a texture
+func (r *SoftwareRenderer) loadImage(filename string) (image.Image, error) {
+	normalizedName := NormalizeTextureName(filename)
+	if cached, exists := r.cache[normalizedName]; exists {
+		return cached, nil
+	}
+
+	data, err := r.filesystem.ReadFile(normalizedName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image (format: %s): %v", format, err)
+	}
+
+	r.cache[normalizedName] = img
+	return img, nil
+}
+
+// drawScaled draws src at (x, y) scaled with nearest-neighbour sampling and
+// a constant alpha, matching Ebiten's GeoM scale-then-translate order
+func drawScaled(dst *image.RGBA, src image.Image, x, y int, scaleX, scaleY, alpha float64) {
+	mask := image.NewUniform(color.Alpha{A: uint8(clamp01(alpha) * 255)})
+	srcBounds := src.Bounds()
+
+	if scaleX == 1.0 && scaleY == 1.0 {
+		dstRect := image.Rect(x, y, x+srcBounds.Dx(), y+srcBounds.Dy())
+		draw.DrawMask(dst, dstRect, src, srcBounds.Min, mask, image.Point{}, draw.Over)
+		return
+	}
+
+	if scaleX <= 0 || scaleY <= 0 {
+		return
+	}
+
+	width := int(float64(srcBounds.Dx()) * scaleX)
+	height := int(float64(srcBounds.Dy()) * scaleY)
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	for dy := 0; dy < height; dy++ {
+		sy := srcBounds.Min.Y + int(float64(dy)/scaleY)
+		for dx := 0; dx < width; dx++ {
+			sx := srcBounds.Min.X + int(float64(dx)/scaleX)
+			scaled.Set(dx, dy, src.At(sx, sy))
+		}
+	}
+
+	dstRect := image.Rect(x, y, x+width, y+height)
+	draw.DrawMask(dst, dstRect, scaled, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// solidImage creates an image filled with a single color
+func solidImage(width, height int, fill color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(fill), image.Point{}, draw.Src)
+	return img
+}
+
+// clamp01 clamps a value to the 0.0-1.0 range
+func clamp01(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
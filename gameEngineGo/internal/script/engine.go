@@ -43,6 +43,28 @@ type Engine struct {
 	events    []*Event
 	running   bool
 	startTime time.Time
+
+	// JRS timeline playback
+	active     *Script
+	states     []int // Per-action state (EventWait, EventRun, EventEnd)
+	position   int64 // Timeline position in ms
+	lastTick   time.Time
+	paused     bool
+	generation uint64 // Bumped whenever the timeline jumps or the script changes
+	handler    ActionHandler
+
+	// Wait points: the timeline holds at the end of actions accepted by
+	// waitFilter until Resume is called
+	waitFilter func(action *Action) bool
+	waiting    *Action
+
+	// Fixed-step clock for deterministic replays: each Update advances
+	// clock by fixedStep instead of reading the wall clock
+	fixedStep time.Duration
+	clock     time.Time
+
+	// Game flags, kept across scripts
+	flags *Flags
 }
 
 // NewEngine creates a new script engine
@@ -50,9 +72,15 @@ func NewEngine() *Engine {
 	return &Engine{
 		events:  make([]*Event, 0),
 		running: false,
+		flags:   NewFlags(),
 	}
 }
 
+// Flags returns the game flags
+func (e *Engine) Flags() *Flags {
+	return e.flags
+}
+
 // Init initializes the script engine
 func (e *Engine) Init() error {
 	log.Println("Script engine initialized")
@@ -62,7 +90,8 @@ func (e *Engine) Init() error {
 // Start starts the script engine
 func (e *Engine) Start() {
 	e.running = true
-	e.startTime = time.Now()
+	e.startTime = e.now()
+	e.lastTick = e.startTime
 	log.Println("Script engine started")
 }
 
@@ -79,8 +108,8 @@ func (e *Engine) AddEvent(eventType int, data ...string) {
 		State:     EventWait,
 		Data:      data,
 		Direction: true,
-		Duration:  time.Second * 2,                        // Default 2 seconds
-		StartTime: time.Now().Add(time.Millisecond * 100), // Start in 100ms
+		Duration:  time.Second * 2,                     // Default 2 seconds
+		StartTime: e.now().Add(time.Millisecond * 100), // Start in 100ms
 	}
 	event.EndTime = event.StartTime.Add(event.Duration)
 
@@ -99,7 +128,12 @@ func (e *Engine) Update() error {
 		return nil
 	}
 
-	currentTime := time.Now()
+	if e.fixedStep > 0 {
+		e.clock = e.clock.Add(e.fixedStep)
+	}
+	currentTime := e.now()
+
+	e.updateTimeline(currentTime)
 
 	for _, event := range e.events {
 		if event.State == EventEnd {
@@ -129,6 +163,23 @@ func (e *Engine) Update() error {
 	return nil
 }
 
+// SetFixedStep makes every Update advance the clock by step instead of the
+// elapsed wall-clock time, so that replays hit the same timeline positions.
+// A step of 0 returns to the wall clock.
+func (e *Engine) SetFixedStep(step time.Duration) {
+	e.fixedStep = step
+	e.clock = time.Time{}
+	e.lastTick = e.now()
+}
+
+// now returns the current time of the engine clock
+func (e *Engine) now() time.Time {
+	if e.fixedStep > 0 {
+		return e.clock
+	}
+	return time.Now()
+}
+
 // startEvent handles event start
 func (e *Engine) startEvent(event *Event) {
 	switch event.Type {
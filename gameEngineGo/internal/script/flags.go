@@ -0,0 +1,39 @@
+package script
+
+import (
+	"sort"
+	"strings"
+)
+
+// Flags holds the game flags that choices set and the route tables read
+// (e.g. the "flagN" entries of FEELINGSCRIPT.INI). Names are
+// case-insensitive; flags that were never set read as 0.
+type Flags struct {
+	values map[string]int
+}
+
+// NewFlags creates an empty flag store
+func NewFlags() *Flags {
+	return &Flags{values: make(map[string]int)}
+}
+
+// Set sets a flag
+func (f *Flags) Set(name string, value int) {
+	f.values[strings.ToLower(name)] = value
+}
+
+// Get returns a flag and whether it was set
+func (f *Flags) Get(name string) (int, bool) {
+	value, ok := f.values[strings.ToLower(name)]
+	return value, ok
+}
+
+// Names returns the names of the flags that were set, sorted
+func (f *Flags) Names() []string {
+	names := make([]string, 0, len(f.values))
+	for name := range f.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,208 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Action names used by JRS/ORS scripts (matches Qt QScript::Action)
+const (
+	ActionSkipFrame = "SkipFRAME"
+	ActionCreateBG  = "CreateBG"
+	ActionBlackFade = "BlackFade"
+	ActionWhiteFade = "WhiteFade"
+	ActionPlayMovie = "PlayMovie"
+	ActionPlaySe    = "PlaySe"
+	ActionPlayES    = "PlayES"
+	ActionPlayBgm   = "PlayBgm"
+	ActionPlayVoice = "PlayVoice"
+	ActionPrintText = "PrintText"
+	ActionSetSelect = "SetSELECT"
+	ActionNext      = "Next"
+	ActionEndBGM    = "EndBGM"
+	ActionEndRoll   = "EndRoll"
+	ActionMoveSom   = "MoveSom"
+)
+
+// KnownActions lists every action name understood by the engine
+var KnownActions = []string{
+	ActionSkipFrame, ActionCreateBG, ActionBlackFade, ActionWhiteFade,
+	ActionPlayMovie, ActionPlaySe, ActionPlayES, ActionPlayBgm,
+	ActionPlayVoice, ActionPrintText, ActionSetSelect, ActionNext,
+	ActionEndBGM, ActionEndRoll, ActionMoveSom,
+}
+
+// Action is a single timeline entry of a JRS script (matches Qt QScriptAction)
+type Action struct {
+	Action    string `json:"action"`
+	Start     int64  `json:"start"`          // Start time in ms from scene start
+	End       int64  `json:"end"`            // End time in ms, -1 if the action has none
+	File      string `json:"file,omitempty"` // Asset path without extension
+	Layer     int    `json:"layer"`          // Layer/channel index, -1 if not set
+	Persona   string `json:"persona,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Direction string `json:"dir,omitempty"` // "IN" or "OUT" for fades
+	Answer1   string `json:"answer1,omitempty"`
+	Answer2   string `json:"answer2,omitempty"`
+
+	Index int `json:"-"` // Position of the action in the source file
+}
+
+// UnmarshalJSON decodes an action, defaulting missing layer/end to -1 like Qt
+func (a *Action) UnmarshalJSON(data []byte) error {
+	type rawAction Action
+	raw := rawAction{Layer: -1, End: -1}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*a = Action(raw)
+	return nil
+}
+
+// HasEnd reports whether the action has an end time
+func (a *Action) HasEnd() bool {
+	return a.End >= 0
+}
+
+// Duration returns the action length in ms (0 for instant actions)
+func (a *Action) Duration() int64 {
+	if !a.HasEnd() || a.End < a.Start {
+		return 0
+	}
+	return a.End - a.Start
+}
+
+// IsKnownAction reports whether name is a recognised JRS action
+func IsKnownAction(name string) bool {
+	for _, known := range KnownActions {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Script holds a parsed JRS file
+type Script struct {
+	Name    string    // Script path as it was loaded
+	Actions []*Action // Actions sorted by start time
+}
+
+// FileSystemInterface defines the filesystem operations needed to load scripts
+type FileSystemInterface interface {
+	ReadFile(filename string) ([]byte, error)
+}
+
+// ParseJRS parses a JRS script (JSON array of actions) from a reader
+func ParseJRS(reader io.Reader) (*Script, error) {
+	var actions []*Action
+	if err := json.NewDecoder(reader).Decode(&actions); err != nil {
+		return nil, fmt.Errorf("failed to parse JRS: %w", err)
+	}
+
+	for i, action := range actions {
+		if action == nil {
+			return nil, fmt.Errorf("null action at index %d", i)
+		}
+		action.Index = i
+	}
+
+	// Keep file order for actions starting at the same time (matches qSort by start)
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Start < actions[j].Start
+	})
+
+	return &Script{Actions: actions}, nil
+}
+
+// LoadJRS loads and parses a JRS script through the filesystem
+func LoadJRS(fs FileSystemInterface, filename string) (*Script, error) {
+	data, err := fs.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script %s: %w", filename, err)
+	}
+
+	script, err := ParseJRS(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load script %s: %w", filename, err)
+	}
+	script.Name = filename
+
+	return script, nil
+}
+
+// BaseName returns the script file name without directory and extension
+func (s *Script) BaseName() string {
+	name := path.Base(strings.ReplaceAll(s.Name, "\\", "/"))
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// Length returns the time in ms at which the last action ends
+func (s *Script) Length() int64 {
+	var length int64
+	for _, action := range s.Actions {
+		if action.Start > length {
+			length = action.Start
+		}
+		if action.End > length {
+			length = action.End
+		}
+	}
+	return length
+}
+
+// PersistentActions returns the actions started at or before position whose
+// effect outlasts them and that nothing replaced since, in start order: the
+// last fade (its final opacity stays) and a background or BGM without an
+// end. Actions still running at position are not included; SeekTo restarts
+// those.
+func (s *Script) PersistentActions(position int64) []*Action {
+	last := make(map[string]*Action)
+	for _, action := range s.Actions {
+		if action.Start > position {
+			break // Actions are sorted by start time
+		}
+		if lane := stateLane(action); lane != "" {
+			last[lane] = action
+		}
+	}
+
+	var persistent []*Action
+	for _, action := range s.Actions {
+		lane := stateLane(action)
+		if lane == "" || last[lane] != action {
+			continue
+		}
+		if !action.HasEnd() || (lane == stateLaneFade && action.End <= position) {
+			persistent = append(persistent, action)
+		}
+	}
+	return persistent
+}
+
+// Lanes of the scene state left behind by actions
+const (
+	stateLaneBG   = "bg"
+	stateLaneBGM  = "bgm"
+	stateLaneFade = "fade"
+)
+
+// stateLane returns the part of the scene state an action sets, or "" for
+// actions that leave nothing behind
+func stateLane(action *Action) string {
+	switch action.Action {
+	case ActionCreateBG:
+		return stateLaneBG
+	case ActionPlayBgm, ActionEndBGM:
+		return stateLaneBGM
+	case ActionBlackFade, ActionWhiteFade:
+		return stateLaneFade
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,49 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+const stateTestScript = `[
+{"action":"CreateBG","start":0,"file":"EVENT00/TEST/BG-001"},
+{"action":"PlayBgm","start":0,"file":"BGM/SD_BGM01"},
+{"action":"BlackFade","start":0,"end":500,"dir":"IN"},
+{"action":"PlayVoice","start":600,"end":1600,"file":"VOICE/MAK0001"},
+{"action":"CreateBG","start":2000,"end":3000,"file":"EVENT00/TEST/BG-002"},
+{"action":"WhiteFade","start":2500,"end":3000,"dir":"OUT"},
+{"action":"CreateBG","start":3000,"file":"EVENT00/TEST/BG-003"},
+{"action":"PlayBgm","start":3500,"end":5000,"file":"BGM/SD_BGM02"}
+]`
+
+func TestPersistentActions(t *testing.T) {
+	parsed, err := ParseJRS(strings.NewReader(stateTestScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		position int64
+		want     []string // Files or fade directions, in start order
+	}{
+		{0, []string{"EVENT00/TEST/BG-001", "BGM/SD_BGM01"}},           // The fade is still running
+		{1000, []string{"EVENT00/TEST/BG-001", "BGM/SD_BGM01", "IN"}},  // Faded in
+		{2200, []string{"BGM/SD_BGM01", "IN"}},                         // BG-002 is running
+		{3000, []string{"BGM/SD_BGM01", "OUT", "EVENT00/TEST/BG-003"}}, // BG-002 ended, BG-003 replaces it
+		{4000, []string{"OUT", "EVENT00/TEST/BG-003"}},                 // SD_BGM02 is running
+		{6000, []string{"OUT", "EVENT00/TEST/BG-003"}},                 // SD_BGM02 ended and left nothing
+	}
+	for _, test := range tests {
+		var got []string
+		for _, action := range parsed.PersistentActions(test.position) {
+			if action.File != "" {
+				got = append(got, action.File)
+			} else {
+				got = append(got, action.Direction)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("at %d ms got %v, want %v", test.position, got, test.want)
+		}
+	}
+}
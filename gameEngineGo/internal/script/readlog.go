@@ -0,0 +1,117 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// readLogFile is the on-disk form of the read-text log
+type readLogFile struct {
+	Scripts map[string][]int `json:"scripts"` // Script base name -> PrintText action indices
+}
+
+// ReadLog is the global set of dialogue lines the player has already seen,
+// keyed by script base name and action index so that it survives language
+// changes (translations keep the action order)
+type ReadLog struct {
+	path  string
+	seen  map[string]map[int]bool
+	dirty bool
+}
+
+// NewReadLog creates a read-text log stored at path
+func NewReadLog(path string) *ReadLog {
+	return &ReadLog{
+		path: path,
+		seen: make(map[string]map[int]bool),
+	}
+}
+
+// Load reads the log from disk; a missing file is an empty log
+func (r *ReadLog) Load() error {
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read read-text log: %w", err)
+	}
+
+	var file readLogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse read-text log: %w", err)
+	}
+
+	r.seen = make(map[string]map[int]bool, len(file.Scripts))
+	for name, indices := range file.Scripts {
+		set := make(map[int]bool, len(indices))
+		for _, index := range indices {
+			set[index] = true
+		}
+		r.seen[name] = set
+	}
+	r.dirty = false
+
+	log.Printf("Loaded read-text log from %s (%d scripts)", r.path, len(r.seen))
+	return nil
+}
+
+// Save writes the log to disk if it changed since the last load or save
+func (r *ReadLog) Save() error {
+	if !r.dirty {
+		return nil
+	}
+
+	file := readLogFile{Scripts: make(map[string][]int, len(r.seen))}
+	for name, set := range r.seen {
+		indices := make([]int, 0, len(set))
+		for index := range set {
+			indices = append(indices, index)
+		}
+		sort.Ints(indices)
+		file.Scripts[name] = indices
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal read-text log: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create read-text log directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write read-text log: %w", err)
+	}
+
+	r.dirty = false
+	return nil
+}
+
+// MarkRead records that a text action of a script has been shown
+func (r *ReadLog) MarkRead(script *Script, action *Action) {
+	key := readLogKey(script)
+	set := r.seen[key]
+	if set == nil {
+		set = make(map[int]bool)
+		r.seen[key] = set
+	}
+	if !set[action.Index] {
+		set[action.Index] = true
+		r.dirty = true
+	}
+}
+
+// IsRead returns whether a text action of a script has been shown before
+func (r *ReadLog) IsRead(script *Script, action *Action) bool {
+	return r.seen[readLogKey(script)][action.Index]
+}
+
+// readLogKey returns the log key of a script
+func readLogKey(script *Script) string {
+	return strings.ToLower(script.BaseName())
+}
@@ -0,0 +1,66 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadLogRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "readtext.json")
+	scene := &Script{Name: "Script/Ru/00/00-00-A00.jrs"}
+	line := &Action{Action: ActionPrintText, Index: 3}
+
+	readLog := NewReadLog(path)
+	readLog.MarkRead(scene, line)
+	if err := readLog.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewReadLog(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsRead(scene, line) {
+		t.Error("line not read after reloading the readLog")
+	}
+	if loaded.IsRead(scene, &Action{Index: 4}) {
+		t.Error("unseen line read after reloading the readLog")
+	}
+
+	// Keyed by base name, so other languages share the readLog
+	if !loaded.IsRead(&Script{Name: "Script/En/00/00-00-a00.JRS"}, line) {
+		t.Error("line not read in another language tree")
+	}
+}
+
+func TestReadLogSaveOnlyWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "readtext.json")
+	readLog := NewReadLog(path)
+
+	if err := readLog.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("unchanged readLog was written")
+	}
+
+	scene := &Script{Name: "00-00-A00.jrs"}
+	readLog.MarkRead(scene, &Action{Index: 1})
+	readLog.Save()
+	os.Remove(path)
+
+	// Marking a line already read does not make the readLog dirty again
+	readLog.MarkRead(scene, &Action{Index: 1})
+	readLog.Save()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("readLog written without new lines")
+	}
+}
+
+func TestReadLogLoadMissing(t *testing.T) {
+	readLog := NewReadLog(filepath.Join(t.TempDir(), "missing.json"))
+	if err := readLog.Load(); err != nil {
+		t.Fatalf("missing readLog: %v", err)
+	}
+}
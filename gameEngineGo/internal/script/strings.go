@@ -0,0 +1,141 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Translatable fields of JRS actions. Personas are voice and character
+// codes, not text, and are only given as context.
+const (
+	FieldText    = "text"
+	FieldAnswer1 = "answer1"
+	FieldAnswer2 = "answer2"
+)
+
+// TextString is a translatable string of a script
+type TextString struct {
+	Index   int    // Action position in the source file
+	Field   string // FieldText, FieldAnswer1 or FieldAnswer2
+	Value   string
+	Persona string // Speaker of a PrintText line, for translator context
+	Start   int64  // Action start in ms
+}
+
+// Strings returns the PrintText texts and the SetSELECT answers of a
+// script in file order
+func (s *Script) Strings() []TextString {
+	actions := make([]*Action, len(s.Actions))
+	copy(actions, s.Actions)
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Index < actions[j].Index
+	})
+
+	var texts []TextString
+	for _, action := range actions {
+		switch action.Action {
+		case ActionPrintText:
+			texts = append(texts,
+				TextString{Index: action.Index, Field: FieldText, Value: action.Text, Persona: action.Persona, Start: action.Start})
+		case ActionSetSelect:
+			texts = append(texts,
+				TextString{Index: action.Index, Field: FieldAnswer1, Value: action.Answer1, Start: action.Start},
+				TextString{Index: action.Index, Field: FieldAnswer2, Value: action.Answer2, Start: action.Start})
+		}
+	}
+	return texts
+}
+
+// PatchStrings replaces string values in JRS data without re-encoding the
+// rest of the file, so timings, key order and formatting stay byte for byte.
+// replace is called for every string field with the action's file position
+// and returns the new value, or false to keep it. Returns the number of
+// values changed.
+func PatchStrings(data []byte, replace func(index int, field, value string) (string, bool)) ([]byte, int, error) {
+	type span struct {
+		start, end int
+		value      []byte
+	}
+	var spans []span
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := expectDelim(decoder, '['); err != nil {
+		return nil, 0, err
+	}
+
+	for index := 0; decoder.More(); index++ {
+		if err := expectDelim(decoder, '{'); err != nil {
+			return nil, 0, fmt.Errorf("action %d: %w", index, err)
+		}
+
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, 0, fmt.Errorf("action %d: %w", index, err)
+			}
+			field, _ := token.(string)
+
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				return nil, 0, fmt.Errorf("action %d field %s: %w", index, field, err)
+			}
+			end := int(decoder.InputOffset())
+
+			var value string
+			if len(raw) == 0 || raw[0] != '"' || json.Unmarshal(raw, &value) != nil {
+				continue // Not a string
+			}
+
+			replacement, ok := replace(index, field, value)
+			if !ok || replacement == value {
+				continue
+			}
+			encoded, err := encodeString(replacement)
+			if err != nil {
+				return nil, 0, err
+			}
+			spans = append(spans, span{start: end - len(raw), end: end, value: encoded})
+		}
+
+		if err := expectDelim(decoder, '}'); err != nil {
+			return nil, 0, fmt.Errorf("action %d: %w", index, err)
+		}
+	}
+
+	var patched bytes.Buffer
+	previous := 0
+	for _, s := range spans {
+		patched.Write(data[previous:s.start])
+		patched.Write(s.value)
+		previous = s.end
+	}
+	patched.Write(data[previous:])
+
+	return patched.Bytes(), len(spans), nil
+}
+
+// expectDelim reads a JSON delimiter token
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, got %v", delim, token)
+	}
+	return nil
+}
+
+// encodeString encodes a JSON string like Qt does, keeping non-ASCII text
+// and HTML characters unescaped
+func encodeString(value string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,103 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const stringsTestScript = `[
+{"action":"CreateBG","start":0,"file":"EVENT00/TEST/BG-001"},
+{"action":"PrintText","start":100,"end":900,"persona":"MAK","text":"Он сказал: \"Привет\"\nи ушёл"},
+{"action":"SetSELECT","start":1000,"answer1":"Да","answer2":"Нет"},
+{"action":"PrintText","start":2000,"end":2500,"text":"<i>мысли</i>"}
+]`
+
+func TestStrings(t *testing.T) {
+	parsed, err := ParseJRS(strings.NewReader(stringsTestScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []TextString{
+		{Index: 1, Field: FieldText, Value: "Он сказал: \"Привет\"\nи ушёл", Persona: "MAK", Start: 100},
+		{Index: 2, Field: FieldAnswer1, Value: "Да", Start: 1000},
+		{Index: 2, Field: FieldAnswer2, Value: "Нет", Start: 1000},
+		{Index: 3, Field: FieldText, Value: "<i>мысли</i>", Start: 2000},
+	}
+	got := parsed.Strings()
+	if len(got) != len(want) {
+		t.Fatalf("got %d strings, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("string %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPatchStrings(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace map[string]string // "<index>.<field>" to new value
+		want    string
+		count   int
+	}{
+		{
+			name:  "nothing replaced",
+			want:  stringsTestScript,
+			count: 0,
+		},
+		{
+			name:    "escaped quotes and newline",
+			replace: map[string]string{"1.text": "He said: \"Hi\"\nand left"},
+			want:    strings.Replace(stringsTestScript, `"Он сказал: \"Привет\"\nи ушёл"`, `"He said: \"Hi\"\nand left"`, 1),
+			count:   1,
+		},
+		{
+			name:    "unchanged value not counted",
+			replace: map[string]string{"2.answer1": "Да", "2.answer2": "No"},
+			want:    strings.Replace(stringsTestScript, `"Нет"`, `"No"`, 1),
+			count:   1,
+		},
+		{
+			name:    "html and backslashes kept readable",
+			replace: map[string]string{"3.text": `<i>thoughts\</i> & more`},
+			want:    strings.Replace(stringsTestScript, `"<i>мысли</i>"`, `"<i>thoughts\\</i> & more"`, 1),
+			count:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			patched, count, err := PatchStrings([]byte(stringsTestScript), func(index int, field, value string) (string, bool) {
+				replacement, ok := test.replace[fmt.Sprintf("%d.%s", index, field)]
+				return replacement, ok
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != test.count {
+				t.Errorf("patched %d strings, want %d", count, test.count)
+			}
+			if string(patched) != test.want {
+				t.Errorf("patched to\n%s\nwant\n%s", patched, test.want)
+			}
+
+			// The patched file still parses, with the new values
+			if _, err := ParseJRS(bytes.NewReader(patched)); err != nil {
+				t.Errorf("patched file does not parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestPatchStringsRejectsMalformed(t *testing.T) {
+	keep := func(int, string, string) (string, bool) { return "", false }
+	for _, data := range []string{`{"action":"PrintText"}`, `[{"action":"PrintText","text":"open]`, `[["text"]]`} {
+		if _, _, err := PatchStrings([]byte(data), keep); err == nil {
+			t.Errorf("PatchStrings(%s) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package script
+
+import (
+	"log"
+	"time"
+)
+
+// ActionHandler receives JRS actions as the timeline reaches them
+type ActionHandler interface {
+	StartAction(action *Action)
+	UpdateAction(action *Action, position int64)
+	EndAction(action *Action)
+}
+
+// SetHandler sets the receiver of timeline actions
+func (e *Engine) SetHandler(handler ActionHandler) {
+	e.handler = handler
+}
+
+// LoadScript makes a parsed JRS script the active timeline, starting at 0 ms
+func (e *Engine) LoadScript(script *Script) {
+	e.stopRunningActions()
+
+	e.active = script
+	e.states = make([]int, len(script.Actions))
+	e.position = 0
+	e.clearWait()
+	e.lastTick = e.now()
+	e.generation++
+
+	log.Printf("Loaded script %s with %d actions", script.Name, len(script.Actions))
+}
+
+// UnloadScript stops and removes the active timeline
+func (e *Engine) UnloadScript() {
+	e.stopRunningActions()
+
+	e.active = nil
+	e.states = nil
+	e.position = 0
+	e.clearWait()
+	e.generation++
+}
+
+// SeekTo moves the timeline to the given position in ms. Running actions are
+// ended and actions spanning the new position are started again.
+func (e *Engine) SeekTo(position int64) {
+	if e.active == nil {
+		return
+	}
+	if position < 0 {
+		position = 0
+	}
+
+	e.stopRunningActions()
+	e.setStatesAt(position)
+	e.lastTick = e.now()
+	e.generation++
+	e.clearWait()
+
+	log.Printf("Script seek to %d ms", position)
+}
+
+// setStatesAt moves the position and marks the actions before it as ended
+// and the ones after or spanning it as waiting
+func (e *Engine) setStatesAt(position int64) {
+	for i, action := range e.active.Actions {
+		switch {
+		case action.Start > position:
+			e.states[i] = EventWait
+		case action.HasEnd() && action.End > position:
+			e.states[i] = EventWait // Restarted on the next update
+		default:
+			e.states[i] = EventEnd
+		}
+	}
+	e.position = position
+}
+
+// ReplaceScript swaps the active timeline for an edited version of the same
+// script at the current position. Running actions that are unchanged in the
+// new version carry on without restarting, so voices, BGM and backgrounds
+// continue; changed ones are ended and their new versions start on the next
+// update. A line held at a wait point stays held, or is shown again and held
+// if it was edited.
+func (e *Engine) ReplaceScript(script *Script) {
+	if e.active == nil {
+		e.LoadScript(script)
+		return
+	}
+
+	previous, previousStates := e.active, e.states
+	held := e.waiting
+	e.active = script
+	e.states = make([]int, len(script.Actions))
+	e.waiting = nil
+	e.setStatesAt(e.position)
+
+	// Carry on running actions that are unchanged
+	running := make(map[Action]int)
+	for i, action := range previous.Actions {
+		if previousStates[i] == EventRun {
+			running[actionKey(action)]++
+		}
+	}
+	for i, action := range script.Actions {
+		if action.Start > e.position {
+			break
+		}
+		if key := actionKey(action); action.HasEnd() && action.End >= e.position && running[key] > 0 {
+			e.states[i] = EventRun
+			running[key]--
+		}
+	}
+	for i, action := range previous.Actions {
+		if key := actionKey(action); previousStates[i] == EventRun && running[key] > 0 {
+			running[key]--
+			e.fireEnd(action) // Changed or removed
+		}
+	}
+
+	if held != nil && e.waitFilter != nil {
+		for i, action := range script.Actions {
+			if action.Start > e.position {
+				break
+			}
+			if !action.HasEnd() || action.End != e.position || !e.waitFilter(action) {
+				continue
+			}
+			if actionKey(action) == actionKey(held) {
+				e.waiting = action
+				break
+			}
+			e.states[i] = EventWait // Edited line, shown again and held
+		}
+	}
+
+	e.lastTick = e.now()
+	e.generation++
+	log.Printf("Replaced script %s at %d ms", script.Name, e.position)
+}
+
+// actionKey returns an action without its position in the file, to match
+// the same action across versions of a script
+func actionKey(action *Action) Action {
+	key := *action
+	key.Index = 0
+	return key
+}
+
+// SetPaused pauses or resumes the timeline clock. The pause is independent of
+// wait points: resuming does not release a held line, and releasing a line
+// does not resume a paused timeline.
+func (e *Engine) SetPaused(paused bool) {
+	e.paused = paused
+	e.lastTick = e.now()
+}
+
+// IsPaused returns whether the timeline clock is paused by SetPaused
+func (e *Engine) IsPaused() bool {
+	return e.paused
+}
+
+// isStopped returns whether the clock stands still: paused, or held at a
+// wait point
+func (e *Engine) isStopped() bool {
+	return e.paused || e.waiting != nil
+}
+
+// SetWaitFilter sets which actions hold the timeline at their end (e.g. text
+// waiting for the player). A held timeline stands still until Resume.
+func (e *Engine) SetWaitFilter(filter func(action *Action) bool) {
+	e.waitFilter = filter
+}
+
+// IsWaiting returns whether the timeline is held at a wait point
+func (e *Engine) IsWaiting() bool {
+	return e.waiting != nil
+}
+
+// GetWaitingAction returns the action the timeline is held at, or nil
+func (e *Engine) GetWaitingAction() *Action {
+	return e.waiting
+}
+
+// Resume releases the timeline from a wait point
+func (e *Engine) Resume() {
+	if e.waiting == nil {
+		return
+	}
+
+	// The held line keeps running while the timeline waits; end it now so
+	// it cannot hold again
+	for i, action := range e.active.Actions {
+		if action == e.waiting && e.states[i] == EventRun {
+			e.states[i] = EventEnd
+			e.fireEnd(action)
+		}
+	}
+
+	e.clearWait()
+	log.Printf("Script resumed at %d ms", e.position)
+}
+
+// clearWait drops the current wait point. A pause set with SetPaused stays.
+func (e *Engine) clearWait() {
+	if e.waiting != nil {
+		e.waiting = nil
+		e.lastTick = e.now()
+	}
+}
+
+// holdAtWaitPoint moves the position back to the end of the first action
+// accepted by the wait filter that ended since the last tick, and holds there.
+// The held action is ended by Resume, not by the timeline.
+func (e *Engine) holdAtWaitPoint() {
+	if e.waitFilter == nil || e.waiting != nil {
+		return
+	}
+
+	var hold *Action
+	for i, action := range e.active.Actions {
+		if action.Start > e.position {
+			break
+		}
+		if e.states[i] == EventEnd || !action.HasEnd() || action.End > e.position {
+			continue
+		}
+		if (hold == nil || action.End < hold.End) && e.waitFilter(action) {
+			hold = action
+		}
+	}
+
+	if hold != nil {
+		e.position = max(hold.End, hold.Start)
+		e.waiting = hold
+	}
+}
+
+// GetPosition returns the timeline position in ms
+func (e *Engine) GetPosition() int64 {
+	return e.position
+}
+
+// GetActiveScript returns the active JRS script, or nil
+func (e *Engine) GetActiveScript() *Script {
+	return e.active
+}
+
+// Generation returns a counter that changes whenever the timeline jumps
+// (script change, seek or unload). Background work tied to a timeline
+// position can compare it to detect that its results are stale.
+func (e *Engine) Generation() uint64 {
+	return e.generation
+}
+
+// Upcoming returns actions that have not started yet and start within
+// window ms of the current position
+func (e *Engine) Upcoming(window int64) []*Action {
+	if e.active == nil {
+		return nil
+	}
+
+	var upcoming []*Action
+	limit := e.position + window
+	for i, action := range e.active.Actions {
+		if action.Start > limit {
+			break // Actions are sorted by start time
+		}
+		if e.states[i] == EventWait {
+			upcoming = append(upcoming, action)
+		}
+	}
+	return upcoming
+}
+
+// RunningActions returns the actions currently in progress
+func (e *Engine) RunningActions() []*Action {
+	if e.active == nil {
+		return nil
+	}
+
+	var running []*Action
+	for i, action := range e.active.Actions {
+		if e.states[i] == EventRun {
+			running = append(running, action)
+		}
+	}
+	return running
+}
+
+// updateTimeline advances the clock and fires action callbacks (matches Qt action_sheduler)
+func (e *Engine) updateTimeline(now time.Time) {
+	if e.active == nil {
+		return
+	}
+
+	if e.isStopped() {
+		e.lastTick = now
+	} else {
+		// Keep the sub-millisecond remainder for the next tick
+		elapsed := now.Sub(e.lastTick).Milliseconds()
+		e.position += elapsed
+		e.lastTick = e.lastTick.Add(time.Duration(elapsed) * time.Millisecond)
+	}
+
+	e.processTimeline()
+}
+
+// Step advances the timeline by a fixed amount instead of wall-clock time
+func (e *Engine) Step(deltaMs int64) {
+	if e.active == nil {
+		return
+	}
+
+	if !e.isStopped() {
+		e.position += deltaMs
+	}
+	e.lastTick = e.now()
+
+	e.processTimeline()
+}
+
+// StepUntil advances the timeline by up to deltaMs like Step, but stops at
+// the start of the first waiting action for which stop returns true. That
+// action is started and returned; nil means the full step was taken.
+func (e *Engine) StepUntil(deltaMs int64, stop func(action *Action) bool) *Action {
+	if e.active == nil || e.isStopped() {
+		return nil
+	}
+
+	target := e.position + deltaMs
+	var stopAt *Action
+	for i, action := range e.active.Actions {
+		if action.Start > target {
+			break // Actions are sorted by start time
+		}
+		if e.states[i] == EventWait && stop(action) {
+			stopAt = action
+			target = max(action.Start, e.position)
+			break
+		}
+	}
+
+	e.Step(target - e.position)
+	return stopAt
+}
+
+// processTimeline fires start, update and end callbacks for the current position
+func (e *Engine) processTimeline() {
+	e.holdAtWaitPoint()
+
+	// Stop finished actions first so replacements on the same layer win
+	// (matches checkStopActions). The held line runs until Resume.
+	for i, action := range e.active.Actions {
+		if e.states[i] == EventRun && action.End <= e.position && action != e.waiting {
+			e.states[i] = EventEnd
+			e.fireEnd(action)
+		}
+	}
+
+	for i, action := range e.active.Actions {
+		if action.Start > e.position {
+			break
+		}
+
+		switch e.states[i] {
+		case EventWait:
+			e.states[i] = EventRun
+			e.fireStart(action)
+			if !action.HasEnd() {
+				e.states[i] = EventEnd
+				e.fireEnd(action)
+			}
+		case EventRun:
+			if e.handler != nil {
+				e.handler.UpdateAction(action, e.position)
+			}
+		}
+	}
+}
+
+// stopRunningActions ends every running action of the active script
+func (e *Engine) stopRunningActions() {
+	if e.active == nil {
+		return
+	}
+
+	for i, action := range e.active.Actions {
+		if e.states[i] == EventRun {
+			e.states[i] = EventEnd
+			e.fireEnd(action)
+		}
+	}
+}
+
+// fireStart notifies the handler that an action started
+func (e *Engine) fireStart(action *Action) {
+	if e.handler != nil {
+		e.handler.StartAction(action)
+	}
+}
+
+// fireEnd notifies the handler that an action ended
+func (e *Engine) fireEnd(action *Action) {
+	if e.handler != nil {
+		e.handler.EndAction(action)
+	}
+}
@@ -0,0 +1,211 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+// loadTestScript parses a JRS fixture and makes it the engine's timeline
+func loadTestScript(t *testing.T, jrs string) *Engine {
+	t.Helper()
+	parsed, err := ParseJRS(strings.NewReader(jrs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewEngine()
+	engine.LoadScript(parsed)
+	engine.Start()
+	return engine
+}
+
+const waitTestScript = `[
+{"action":"PrintText","start":0,"end":1000,"text":"first"},
+{"action":"PrintText","start":1500,"end":2500,"text":"second"},
+{"action":"Next","start":3000}
+]`
+
+func TestWaitHoldsAtTextEnd(t *testing.T) {
+	engine := loadTestScript(t, waitTestScript)
+	engine.SetWaitFilter(func(action *Action) bool { return action.Action == ActionPrintText })
+
+	engine.Step(1200)
+	if !engine.IsWaiting() || engine.GetPosition() != 1000 {
+		t.Fatalf("got position %d waiting %v, want held at 1000", engine.GetPosition(), engine.IsWaiting())
+	}
+	engine.Step(500)
+	if engine.GetPosition() != 1000 {
+		t.Fatalf("held timeline moved to %d", engine.GetPosition())
+	}
+
+	engine.Resume()
+	engine.Step(500)
+	if engine.IsWaiting() || engine.GetPosition() != 1500 {
+		t.Fatalf("got position %d waiting %v after resume, want 1500", engine.GetPosition(), engine.IsWaiting())
+	}
+}
+
+func TestResumeKeepsPlayerPause(t *testing.T) {
+	engine := loadTestScript(t, waitTestScript)
+	engine.SetWaitFilter(func(action *Action) bool { return action.Action == ActionPrintText })
+
+	engine.Step(1200)
+	engine.SetPaused(true) // E.g. the menu opened while the line was held
+	engine.Resume()
+
+	if !engine.IsPaused() {
+		t.Fatal("releasing the line resumed a pause it did not set")
+	}
+	engine.Step(500)
+	if engine.GetPosition() != 1000 {
+		t.Fatalf("paused timeline moved to %d", engine.GetPosition())
+	}
+
+	engine.SetPaused(false)
+	engine.Step(500)
+	if engine.GetPosition() != 1500 {
+		t.Fatalf("got position %d after unpausing, want 1500", engine.GetPosition())
+	}
+}
+
+func TestUnpauseKeepsWait(t *testing.T) {
+	engine := loadTestScript(t, waitTestScript)
+	engine.SetWaitFilter(func(action *Action) bool { return action.Action == ActionPrintText })
+
+	engine.Step(1200)
+	engine.SetPaused(true)
+	engine.SetPaused(false)
+
+	engine.Step(500)
+	if !engine.IsWaiting() || engine.GetPosition() != 1000 {
+		t.Fatalf("got position %d waiting %v, want the line still held", engine.GetPosition(), engine.IsWaiting())
+	}
+}
+
+// eventLog records the actions a timeline starts and ends
+type eventLog struct {
+	events []string
+}
+
+func (l *eventLog) StartAction(action *Action) {
+	l.events = append(l.events, "start "+action.Action+" "+action.File+action.Text)
+}
+
+func (l *eventLog) UpdateAction(action *Action, position int64) {}
+
+func (l *eventLog) EndAction(action *Action) {
+	l.events = append(l.events, "end "+action.Action+" "+action.File+action.Text)
+}
+
+// take returns the recorded events and clears them
+func (l *eventLog) take() string {
+	events := strings.Join(l.events, "; ")
+	l.events = nil
+	return events
+}
+
+const reloadTestScript = `[
+{"action":"PlayBgm","start":0,"end":5000,"file":"BGM/SD_BGM01"},
+{"action":"CreateBG","start":0,"end":5000,"file":"EVENT00/TEST/BG-001"},
+{"action":"PlayVoice","start":500,"end":2500,"file":"VOICE/MAK0001"},
+{"action":"PrintText","start":500,"end":2000,"text":"first"},
+{"action":"PrintText","start":3000,"end":4000,"text":"second"}
+]`
+
+func TestReplaceScriptCarriesUnchangedActions(t *testing.T) {
+	engine := loadTestScript(t, reloadTestScript)
+	log := &eventLog{}
+	engine.SetHandler(log)
+	engine.Step(1000)
+	log.take()
+
+	// The BG file and a line before the current position changed; an action
+	// was inserted at the top, so every index moves
+	edited := strings.Replace(reloadTestScript, "BG-001", "BG-002", 1)
+	edited = strings.Replace(edited, `"text":"second"`, `"text":"second, edited"`, 1)
+	edited = strings.Replace(edited, "[", `[{"action":"SkipFRAME","start":0},`, 1)
+	parsed, err := ParseJRS(strings.NewReader(edited))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine.ReplaceScript(parsed)
+	if got, want := log.take(), "end CreateBG EVENT00/TEST/BG-001"; got != want {
+		t.Errorf("replacing fired %q, want %q", got, want)
+	}
+	if engine.GetPosition() != 1000 {
+		t.Errorf("position %d after replacing, want 1000", engine.GetPosition())
+	}
+
+	// Only the changed background starts again; BGM, voice and text carry on
+	engine.Step(0)
+	if got, want := log.take(), "start CreateBG EVENT00/TEST/BG-002"; got != want {
+		t.Errorf("next update fired %q, want %q", got, want)
+	}
+	engine.Step(1600)
+	if got, want := log.take(), "end PlayVoice VOICE/MAK0001; end PrintText first"; got != want {
+		t.Errorf("carried actions ended with %q, want %q", got, want)
+	}
+}
+
+func TestReplaceScriptKeepsWait(t *testing.T) {
+	tests := []struct {
+		name   string
+		edit   string
+		events string
+	}{
+		{"unchanged line stays held", "first", ""},
+		{"edited line is shown again", "first, edited", "end PrintText first; start PrintText first, edited"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine := loadTestScript(t, reloadTestScript)
+			engine.SetWaitFilter(func(action *Action) bool { return action.Action == ActionPrintText })
+			log := &eventLog{}
+			engine.SetHandler(log)
+			engine.Step(2100)
+			if !engine.IsWaiting() {
+				t.Fatal("not held at the first line")
+			}
+			log.take()
+
+			parsed, err := ParseJRS(strings.NewReader(strings.Replace(reloadTestScript, `"text":"first"`, `"text":"`+test.edit+`"`, 1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			engine.ReplaceScript(parsed)
+			engine.Step(100)
+			engine.Step(100)
+
+			if got := log.take(); got != test.events {
+				t.Errorf("fired %q, want %q", got, test.events)
+			}
+			if !engine.IsWaiting() || engine.GetPosition() != 2000 {
+				t.Errorf("position %d waiting %v, want held at 2000", engine.GetPosition(), engine.IsWaiting())
+			}
+		})
+	}
+}
+
+func TestHeldLineEndsOnResume(t *testing.T) {
+	for _, steps := range [][]int64{{1200}, {500, 700}} {
+		engine := loadTestScript(t, waitTestScript)
+		log := &eventLog{}
+		engine.SetHandler(log)
+		engine.SetWaitFilter(func(action *Action) bool { return action.Action == ActionPrintText })
+
+		// The line stays shown while held, whether it started in the same tick or not
+		for _, step := range steps {
+			engine.Step(step)
+		}
+		engine.Step(500)
+		if got := log.take(); got != "start PrintText first" {
+			t.Errorf("steps %v, holding: %s", steps, got)
+		}
+		engine.Resume()
+		engine.Step(100)
+		if got := log.take(); got != "end PrintText first" {
+			t.Errorf("steps %v, resuming: %s", steps, got)
+		}
+	}
+}
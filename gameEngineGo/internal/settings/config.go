@@ -3,98 +3,125 @@ package settings
 import (
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
 )
 
 // Config holds all engine configuration
 type Config struct {
+	Version int `json:"version"` // Settings file format, see ConfigVersion
+
 	ScreenWidth  int     `json:"screen_width"`
 	ScreenHeight int     `json:"screen_height"`
 	Fullscreen   bool    `json:"fullscreen"`
+	ScalingMode  string  `json:"scaling_mode"`  // "smooth" or "integer"
+	WindowWidth  int     `json:"window_width"`  // Last windowed size, 0 uses the screen size
+	WindowHeight int     `json:"window_height"` // Last windowed size, 0 uses the screen size
 	VSync        bool    `json:"vsync"`
 	BGMVolume    float64 `json:"bgm_volume"`
 	SFXVolume    float64 `json:"sfx_volume"`
 	VoiceVolume  float64 `json:"voice_volume"`
+	Muted        bool    `json:"muted"`
 	AssetsPath   string  `json:"assets_path"`
 	DebugMode    bool    `json:"debug_mode"`
-	Language     string  `json:"language"`
-	TextSpeed    int     `json:"text_speed"`
+	Language     string  `json:"language"`          // Script and menu language code (e.g. "en", "ru") or data directory name
+	FallbackLang string  `json:"fallback_language"` // Language for files without a translation, "" for any
+	TextSpeed    int     `json:"text_speed"`        // Typewriter reveal, 0 (slowest) to 5 (instant)
+	SkipMode     string  `json:"skip_mode"`         // "read" skips only seen text, "all" skips up to the next choice
+	AutoMode     bool    `json:"auto_mode"`
+	AutoSpeed    int     `json:"auto_speed"` // 1 (slowest) to 5, 3 is the default auto-advance delay
+
+	PreloadSeconds  int    `json:"preload_seconds"` // Script lookahead for asset preloading, 0 disables
+	ScreenshotDir   string `json:"screenshot_dir"`
+	LooseFilesFirst bool   `json:"loose_files_first"` // Loose files replace GPK entries (game.ini UseLocalFileFirst)
+
+	// BGM ducking while a voice line plays
+	DuckingEnabled   bool    `json:"ducking_enabled"`
+	DuckingAmount    float64 `json:"ducking_amount"`     // Fraction of BGM volume removed, 0.0 to 1.0
+	DuckingAttackMs  int     `json:"ducking_attack_ms"`  // Time to lower the BGM
+	DuckingReleaseMs int     `json:"ducking_release_ms"` // Time to restore the BGM
+
+	// Per-character voice settings keyed by JRS persona (e.g. "mak", "kot")
+	VoicePersonas map[string]PersonaVoice `json:"voice_personas"`
+
+	// Input bindings that differ from the defaults, keyed by action name
+	// (e.g. "skip": ["key:ControlLeft", "pad:RB"])
+	InputBindings map[string][]string `json:"input_bindings"`
+}
+
+// PersonaVoice holds the voice volume and mute state of one character
+type PersonaVoice struct {
+	Volume float64 `json:"volume"`
+	Muted  bool    `json:"muted"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
+		Version: ConfigVersion,
+
 		ScreenWidth:  800,
 		ScreenHeight: 600,
 		Fullscreen:   false,
+		ScalingMode:  "smooth",
 		VSync:        true,
 		BGMVolume:    0.8,
 		SFXVolume:    0.8,
 		VoiceVolume:  0.8,
+		Muted:        false,
 		AssetsPath:   "./assets",
 		DebugMode:    true,
 		Language:     "en",
 		TextSpeed:    3,
-	}
-}
+		SkipMode:     "read",
+		AutoMode:     false,
+		AutoSpeed:    3,
 
-// Manager handles configuration loading and saving
-type Manager struct {
-	config     *Config
-	configPath string
-}
+		PreloadSeconds: 5,
+		ScreenshotDir:  "./screenshots",
 
-// NewManager creates a new configuration manager
-func NewManager(configPath string) *Manager {
-	return &Manager{
-		config:     DefaultConfig(),
-		configPath: configPath,
-	}
-}
+		DuckingEnabled:   true,
+		DuckingAmount:    0.5,
+		DuckingAttackMs:  200,
+		DuckingReleaseMs: 600,
 
-// Load loads configuration from file
-func (m *Manager) Load() error {
-	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
-		log.Printf("Config file %s not found, using defaults", m.configPath)
-		return m.Save()
+		VoicePersonas: make(map[string]PersonaVoice),
+		InputBindings: make(map[string][]string),
 	}
+}
 
-	data, err := os.ReadFile(m.configPath)
+// clone returns a deep copy of the configuration
+func (c *Config) clone() *Config {
+	data, err := json.Marshal(c)
 	if err != nil {
-		return fmt.Errorf("failed to read config file: %v", err)
+		panic(fmt.Sprintf("settings: failed to copy config: %v", err))
 	}
-
-	if err := json.Unmarshal(data, m.config); err != nil {
-		return fmt.Errorf("failed to parse config file: %v", err)
+	copied := &Config{}
+	if err := json.Unmarshal(data, copied); err != nil {
+		panic(fmt.Sprintf("settings: failed to copy config: %v", err))
 	}
-
-	log.Printf("Loaded configuration from %s", m.configPath)
-	return nil
+	return copied
 }
 
-// Save saves configuration to file
-func (m *Manager) Save() error {
-	dir := filepath.Dir(m.configPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %v", err)
-	}
-
-	data, err := json.MarshalIndent(m.config, "", "  ")
+// fields returns the configuration as JSON values keyed by setting name
+func (c *Config) fields() map[string]json.RawMessage {
+	data, err := json.Marshal(c)
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %v", err)
+		panic(fmt.Sprintf("settings: failed to encode config: %v", err))
 	}
-
-	if err := os.WriteFile(m.configPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %v", err)
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		panic(fmt.Sprintf("settings: failed to encode config: %v", err))
 	}
-
-	log.Printf("Saved configuration to %s", m.configPath)
-	return nil
+	return fields
 }
 
-// GetConfig returns the current configuration
-func (m *Manager) GetConfig() *Config {
-	return m.config
+// applyLayer sets the fields present in a settings layer
+func (c *Config) applyLayer(layer map[string]json.RawMessage) error {
+	if len(layer) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(layer)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, c)
 }
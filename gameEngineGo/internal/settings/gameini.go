@@ -0,0 +1,117 @@
+package settings
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"school-days-engine/internal/filesystem"
+)
+
+// gameINIFile is the main INI file of the original game
+const gameINIFile = "game.ini"
+
+// LoadGameINI loads the original game.ini and the user settings file it
+// names ([ConfigFile], usually Settings.ini) through the filesystem, and
+// uses them as the layer under the user JSON file (matches C++ Game::init)
+func (m *Manager) LoadGameINI(fs *filesystem.Manager) error {
+	if !fs.Exists(gameINIFile) {
+		log.Printf("%s not found, skipping game INI settings", gameINIFile)
+		return nil
+	}
+
+	ini := NewINIManager(fs)
+	if err := ini.Load(gameINIFile); err != nil {
+		return err
+	}
+	if configFile := ini.GetString("ConfigFile"); configFile != "" && fs.Exists(configFile) {
+		if err := ini.Load(configFile); err != nil {
+			return err
+		}
+	}
+
+	base := DefaultConfig()
+	applyGameINI(base, ini)
+	if err := m.setBase(base); err != nil {
+		return fmt.Errorf("failed to apply game INI settings: %w", err)
+	}
+	m.gameINI = ini
+	return nil
+}
+
+// SaveGameINI writes the settings shared with the original game back into
+// its INI files, saved to the filesystem's override directory, so the
+// retail executable and the next start see them (matches C++ Settings::save).
+// Command-line overrides are not written.
+func (m *Manager) SaveGameINI() error {
+	if m.gameINI == nil {
+		return nil
+	}
+
+	config := m.base.clone()
+	if err := config.applyLayer(m.user); err != nil {
+		return fmt.Errorf("failed to apply user settings: %w", err)
+	}
+	storeGameINI(config, m.gameINI)
+	return m.gameINI.Save()
+}
+
+// applyGameINI copies the settings of the original INI files that the
+// engine understands into a configuration
+func applyGameINI(config *Config, ini *INIManager) {
+	// Window size of the last session, falling back to the game size
+	if width, height := ini.GetInt("WindowWidth"), ini.GetInt("WindowHeight"); width > 0 && height > 0 {
+		config.WindowWidth, config.WindowHeight = width, height
+	}
+
+	// Volumes are stored as 0 to 10 (matches C++ Menu sound settings)
+	iniVolume(ini, "BgmVolume", &config.BGMVolume)
+	iniVolume(ini, "SeVolume", &config.SFXVolume)
+	iniVolume(ini, "VoiceVolume", &config.VoiceVolume)
+
+	if ini.Has("Mute") {
+		config.Muted = ini.GetBool("Mute")
+	}
+	if ini.Has("AutoMode") {
+		config.AutoMode = ini.GetBool("AutoMode")
+	}
+	if ini.Has("UseLocalFileFirst") {
+		config.LooseFilesFirst = ini.GetBool("UseLocalFileFirst")
+	}
+	if ini.Has("SuperSkip") {
+		config.SkipMode = "read"
+		if ini.GetBool("SuperSkip") {
+			config.SkipMode = "all"
+		}
+	}
+}
+
+// storeGameINI copies the settings applyGameINI reads back into the INI files
+func storeGameINI(config *Config, ini *INIManager) {
+	if ini.Has("WindowWidth") || ini.Has("WindowHeight") {
+		ini.SetInt("WindowWidth", config.WindowWidth)
+		ini.SetInt("WindowHeight", config.WindowHeight)
+	}
+
+	ini.SetInt("BgmVolume", int(math.Round(config.BGMVolume*10)))
+	ini.SetInt("SeVolume", int(math.Round(config.SFXVolume*10)))
+	ini.SetInt("VoiceVolume", int(math.Round(config.VoiceVolume*10)))
+	ini.SetInt("Mute", boolINI(config.Muted))
+	ini.SetInt("AutoMode", boolINI(config.AutoMode))
+	ini.SetInt("SuperSkip", boolINI(config.SkipMode == "all"))
+}
+
+// boolINI returns a boolean in the INI files' 0/1 form
+func boolINI(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// iniVolume reads a 0 to 10 volume into a 0.0 to 1.0 setting
+func iniVolume(ini *INIManager, key string, volume *float64) {
+	if value := ini.GetInt(key); value >= 0 && value <= 10 {
+		*volume = float64(value) / 10.0
+	}
+}
@@ -1,9 +1,7 @@
 package settings
 
 import (
-	"bufio"
 	"fmt"
-	"io"
 	"log"
 	"strconv"
 	"strings"
@@ -11,17 +9,22 @@ import (
 	"school-days-engine/internal/filesystem"
 )
 
+// iniFile is a loaded INI file
+type iniFile struct {
+	name string
+	doc  *INIDocument
+}
+
 // INIManager handles INI-based configuration like the original C++ Settings class
 type INIManager struct {
-	settings map[string]string // All values stored as strings initially
-	fs       *filesystem.Manager
+	files []*iniFile // In load order, later files override earlier ones
+	fs    *filesystem.Manager
 }
 
 // NewINIManager creates a new INI-based settings manager (matches C++ Settings)
 func NewINIManager(fs *filesystem.Manager) *INIManager {
 	return &INIManager{
-		settings: make(map[string]string),
-		fs:       fs,
+		fs: fs,
 	}
 }
 
@@ -29,24 +32,39 @@ func NewINIManager(fs *filesystem.Manager) *INIManager {
 func (m *INIManager) Load(filename string) error {
 	log.Printf("Loading INI file: %s", filename)
 
-	reader, err := m.fs.Open(filename)
+	data, err := m.fs.ReadFile(filename)
 	if err != nil {
-		return fmt.Errorf("failed to open INI file %s: %v", filename, err)
+		return fmt.Errorf("failed to open INI file %s: %w", filename, err)
 	}
-	defer reader.Close()
 
-	return m.parseINI(reader)
+	m.files = append(m.files, &iniFile{name: filename, doc: ParseINIDocument(data)})
+	return nil
+}
+
+// GetDocument returns the document of a loaded INI file
+func (m *INIManager) GetDocument(filename string) *INIDocument {
+	for _, file := range m.files {
+		if strings.EqualFold(file.name, filename) {
+			return file.doc
+		}
+	}
+	return nil
 }
 
 // GetString returns a string value (matches C++ Settings::get_string)
 func (m *INIManager) GetString(key string) string {
-	if value, exists := m.settings[key]; exists {
-		// Remove quotes if present (C++ format uses quotes)
-		return strings.Trim(value, `"`)
+	if file := m.fileWithKey(key); file != nil {
+		value, _ := file.doc.GetString(key)
+		return value
 	}
 	return ""
 }
 
+// Has returns whether a key was set by a loaded file
+func (m *INIManager) Has(key string) bool {
+	return m.fileWithKey(key) != nil
+}
+
 // GetInt returns an integer value (matches C++ Settings::get_int)
 func (m *INIManager) GetInt(key string) int {
 	value := m.GetString(key)
@@ -79,89 +97,71 @@ func (m *INIManager) GetBool(key string) bool {
 	if value == "" {
 		return false // C++ returns false for missing values
 	}
-	
+
 	if intVal, err := strconv.Atoi(value); err == nil {
 		return intVal != 0 // C++ treats non-zero as true
 	}
 	return false
 }
 
-// SetInt sets an integer value (matches C++ Settings::set_int)
+// SetInt sets an integer value (matches C++ Settings::set_int). The value
+// goes to the file that defined the key, or the last loaded file for new
+// keys, and is written by Save.
 func (m *INIManager) SetInt(key string, value int) {
-	m.settings[key] = strconv.Itoa(value)
+	file := m.fileWithKey(key)
+	if file == nil && len(m.files) > 0 {
+		file = m.files[len(m.files)-1]
+	}
+	if file == nil {
+		log.Printf("Warning: no INI file loaded, cannot set %s", key)
+		return
+	}
+
+	if err := file.doc.SetInt(key, value); err != nil {
+		log.Printf("Warning: %v", err)
+		return
+	}
 	log.Printf("Settings: set %s = %d", key, value)
 }
 
-// parseINI parses an INI file from a reader (matches C++ Parser behavior)
-func (m *INIManager) parseINI(reader io.ReadCloser) error {
-	scanner := bufio.NewScanner(reader)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+// Save writes the changed INI files to the filesystem's override
+// directory, which is read before the game files on the next start
+func (m *INIManager) Save() error {
+	for _, file := range m.files {
+		if !file.doc.IsModified() {
 			continue
 		}
 
-		// Parse C++ format: [key]="value" or [key]=value
-		if err := m.parseLine(line); err != nil {
-			log.Printf("Warning: failed to parse line '%s': %v", line, err)
-			continue
+		path := m.fs.GetOverridePath(file.name)
+		if path == "" {
+			return fmt.Errorf("failed to save INI file %s: no override directory", file.name)
 		}
+		if err := writeFileAtomic(path, file.doc.Bytes()); err != nil {
+			return fmt.Errorf("failed to save INI file %s: %w", file.name, err)
+		}
+		file.doc.markSaved()
+		log.Printf("Saved INI file: %s", path)
 	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading INI file: %v", err)
-	}
-
-	return nil
-}
-
-// parseLine parses a single INI line in C++ format: [key]="value"
-func (m *INIManager) parseLine(line string) error {
-	// Check if line starts with [
-	if !strings.HasPrefix(line, "[") {
-		return fmt.Errorf("line does not start with [")
-	}
-
-	// Find the closing ]
-	closeBracketPos := strings.Index(line, "]")
-	if closeBracketPos == -1 {
-		return fmt.Errorf("missing closing bracket ]")
-	}
-
-	// Extract key
-	key := line[1:closeBracketPos]
-	if key == "" {
-		return fmt.Errorf("empty key")
-	}
-
-	// Check for = after ]
-	remaining := line[closeBracketPos+1:]
-	if !strings.HasPrefix(remaining, "=") {
-		return fmt.Errorf("missing = after key")
-	}
-
-	// Extract value (remove leading =)
-	value := remaining[1:]
-
-	// Handle quoted values (remove quotes if present)
-	if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") && len(value) >= 2 {
-		value = value[1 : len(value)-1]
-	}
-
-	// Store the key-value pair
-	m.settings[key] = value
-
 	return nil
 }
 
 // GetAllSettings returns all current settings for debugging
 func (m *INIManager) GetAllSettings() map[string]string {
 	result := make(map[string]string)
-	for k, v := range m.settings {
-		result[k] = v
+	for _, file := range m.files {
+		for _, key := range file.doc.Keys() {
+			result[key], _ = file.doc.GetString(key)
+		}
 	}
 	return result
 }
+
+// fileWithKey returns the last loaded file that defines a key
+func (m *INIManager) fileWithKey(key string) *iniFile {
+	for i := len(m.files) - 1; i >= 0; i-- {
+		if m.files[i].doc.Has(key) {
+			return m.files[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"school-days-engine/internal/filesystem"
+)
+
+// retailINI is written like the retail files: CRLF, comments, quoted and
+// bare values, padding, and no line ending after the last line
+const retailINI = "; School Days settings\r\n" +
+	"[ConfigFile]=\"Settings.ini\"\r\n" +
+	"\r\n" +
+	"  [BgmVolume]=\"8\"   ; 0-10\r\n" +
+	"[Mute]=0\r\n" +
+	"garbage line\r\n" +
+	"[Name]=\"Makoto\""
+
+func TestINIDocumentRoundTrip(t *testing.T) {
+	doc := ParseINIDocument([]byte(retailINI))
+	if got := string(doc.Bytes()); got != retailINI {
+		t.Fatalf("unchanged document written as\n%q\nwant\n%q", got, retailINI)
+	}
+	if doc.IsModified() {
+		t.Error("parsed document reports changes")
+	}
+
+	if got, _ := doc.GetInt("BgmVolume"); got != 8 {
+		t.Errorf("BgmVolume = %d, want 8", got)
+	}
+	if got, _ := doc.GetString("Name"); got != "Makoto" {
+		t.Errorf("Name = %q, want Makoto", got)
+	}
+	if want := []string{"ConfigFile", "BgmVolume", "Mute", "Name"}; !equalStrings(doc.Keys(), want) {
+		t.Errorf("keys %v, want %v", doc.Keys(), want)
+	}
+}
+
+func TestINIDocumentEdit(t *testing.T) {
+	doc := ParseINIDocument([]byte(retailINI))
+	doc.SetInt("BgmVolume", 3)
+	doc.SetBool("Mute", true)
+	doc.SetInt("AutoMode", 1)
+
+	// Edited lines keep their indent, quoting and comment; the new key is
+	// appended quoted with the document's CRLF ending
+	want := "; School Days settings\r\n" +
+		"[ConfigFile]=\"Settings.ini\"\r\n" +
+		"\r\n" +
+		"  [BgmVolume]=\"3\"   ; 0-10\r\n" +
+		"[Mute]=1\r\n" +
+		"garbage line\r\n" +
+		"[Name]=\"Makoto\"\r\n" +
+		"[AutoMode]=\"1\"\r\n"
+	if got := string(doc.Bytes()); got != want {
+		t.Fatalf("edited document written as\n%q\nwant\n%q", got, want)
+	}
+	if !doc.IsModified() {
+		t.Error("edited document reports no changes")
+	}
+
+	reparsed := ParseINIDocument(doc.Bytes())
+	if got := string(reparsed.Bytes()); got != want {
+		t.Errorf("saved document does not round-trip:\n%q", got)
+	}
+}
+
+func TestINIDocumentKeepsLF(t *testing.T) {
+	doc := ParseINIDocument([]byte("[A]=\"1\"\n"))
+	doc.SetInt("B", 2)
+	if got, want := string(doc.Bytes()), "[A]=\"1\"\n[B]=\"2\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestINIDocumentRejectsInvalid(t *testing.T) {
+	doc := NewINIDocument()
+	for _, key := range []string{"", "a]b", "a\r\nb"} {
+		if err := doc.SetString(key, "1"); err == nil {
+			t.Errorf("key %q accepted", key)
+		}
+	}
+	if err := doc.SetString("Name", `say "hi"`); err == nil {
+		t.Error("value with a quote accepted")
+	}
+}
+
+func TestSaveGameINIToOverrideDir(t *testing.T) {
+	root, configDir := t.TempDir(), t.TempDir()
+	gameINI := []byte("; game\r\n[ConfigFile]=\"Settings.ini\"\r\n")
+	settingsINI := []byte("; user\r\n[BgmVolume]=\"8\"\r\n[SuperSkip]=\"0\"\r\n")
+	os.WriteFile(filepath.Join(root, "game.ini"), gameINI, 0644)
+	os.WriteFile(filepath.Join(root, "Settings.ini"), settingsINI, 0644)
+
+	fs := filesystem.NewManager(root)
+	if err := fs.Init(); err != nil {
+		t.Fatal(err)
+	}
+	fs.SetOverrideDir(configDir)
+
+	manager := NewManager(filepath.Join(configDir, "settings.json"))
+	if err := manager.LoadGameINI(fs); err != nil {
+		t.Fatal(err)
+	}
+	if got := manager.GetConfig().BGMVolume; got != 0.8 {
+		t.Fatalf("BGM volume %v from Settings.ini, want 0.8", got)
+	}
+
+	manager.Update(func(config *Config) {
+		config.BGMVolume = 0.3
+		config.SkipMode = "all"
+	})
+	manager.SetOverride("voice_volume", "0.1") // Session only, never saved
+	if err := manager.SaveGameINI(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The game directory is untouched; the edited copy is in the config directory
+	if data, _ := os.ReadFile(filepath.Join(root, "Settings.ini")); string(data) != string(settingsINI) {
+		t.Errorf("game directory Settings.ini changed:\n%q", data)
+	}
+	saved, err := os.ReadFile(filepath.Join(configDir, "Settings.ini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := ParseINIDocument(saved)
+	if got, _ := doc.GetInt("BgmVolume"); got != 3 {
+		t.Errorf("saved BgmVolume = %d, want 3", got)
+	}
+	if got, _ := doc.GetInt("SuperSkip"); got != 1 {
+		t.Errorf("saved SuperSkip = %d, want 1", got)
+	}
+	if got, _ := doc.GetInt("VoiceVolume"); got != 8 {
+		t.Errorf("saved VoiceVolume = %q, want the default 8, not the override", got)
+	}
+	if string(saved[:len("; user\r\n")]) != "; user\r\n" {
+		t.Errorf("saved file lost its comment or CRLF: %q", saved)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "game.ini")); !os.IsNotExist(err) {
+		t.Error("unchanged game.ini was saved")
+	}
+
+	// The saved copy is read before the game directory's file
+	reloaded := NewManager(filepath.Join(configDir, "settings.json"))
+	if err := reloaded.LoadGameINI(fs); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetConfig().BGMVolume; got != 0.3 {
+		t.Errorf("BGM volume %v after reload, want the saved 0.3", got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGameINIUseLocalFileFirst(t *testing.T) {
+	tests := []struct {
+		ini  string
+		want bool
+	}{
+		{"[UseLocalFileFirst]=\"1\"\r\n", true},
+		{"[UseLocalFileFirst]=\"0\"\r\n", false},
+		{"[Name]=\"SCHOOLDAYS HQ\"\r\n", false}, // Default when missing
+	}
+	for _, test := range tests {
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, "game.ini"), []byte(test.ini), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fs := filesystem.NewManager(root)
+		if err := fs.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		manager := NewManager(filepath.Join(t.TempDir(), "settings.json"))
+		if err := manager.LoadGameINI(fs); err != nil {
+			t.Fatal(err)
+		}
+		if got := manager.GetConfig().LooseFilesFirst; got != test.want {
+			t.Errorf("%q: loose files first = %v, want %v", test.ini, got, test.want)
+		}
+	}
+}
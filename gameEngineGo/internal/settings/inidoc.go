@@ -0,0 +1,242 @@
+package settings
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// iniLine is one line of an INI document. Entries are re-rendered only when
+// changed, so untouched lines are written back byte for byte.
+type iniLine struct {
+	raw     string // Original text without the line ending
+	eol     string // "\r\n", "\n" or "" for the last line
+	entry   bool   // [key]=value line
+	key     string
+	value   string
+	quoted  bool   // Value written in double quotes
+	prefix  string // Whitespace before the entry
+	suffix  string // Text after the value (comments, padding)
+	changed bool
+}
+
+// render returns the text of the line without its ending
+func (l *iniLine) render() string {
+	if !l.entry || !l.changed {
+		return l.raw
+	}
+	value := l.value
+	if l.quoted {
+		value = `"` + value + `"`
+	}
+	return l.prefix + "[" + l.key + "]=" + value + l.suffix
+}
+
+// INIDocument is an INI file in the original game's [key]="value" format
+// that keeps key order, quoting style, comments and line endings, so that
+// edited files stay loadable by the retail executable
+type INIDocument struct {
+	lines   []*iniLine
+	keys    map[string]*iniLine
+	newline string // Line ending for new lines
+}
+
+// NewINIDocument creates an empty document with CRLF line endings like the retail files
+func NewINIDocument() *INIDocument {
+	return &INIDocument{
+		keys:    make(map[string]*iniLine),
+		newline: "\r\n",
+	}
+}
+
+// ParseINIDocument parses INI data (matches C++ Parser). Lines that are not
+// entries are kept as they are.
+func ParseINIDocument(data []byte) *INIDocument {
+	doc := NewINIDocument()
+	if index := bytes.IndexByte(data, '\n'); index >= 0 && (index == 0 || data[index-1] != '\r') {
+		doc.newline = "\n"
+	}
+
+	text := string(data)
+	for len(text) > 0 {
+		line := &iniLine{}
+		if index := strings.IndexByte(text, '\n'); index >= 0 {
+			line.raw, text = text[:index], text[index+1:]
+			line.eol = "\n"
+			if strings.HasSuffix(line.raw, "\r") {
+				line.raw = strings.TrimSuffix(line.raw, "\r")
+				line.eol = "\r\n"
+			}
+		} else {
+			line.raw, text = text, ""
+		}
+
+		parseINIEntry(line)
+		doc.lines = append(doc.lines, line)
+		if line.entry {
+			doc.keys[line.key] = line // Later entries win (matches C++ Settings::load)
+		}
+	}
+	return doc
+}
+
+// parseINIEntry fills in the entry fields of a line in [key]="value" or
+// [key]=value form
+func parseINIEntry(line *iniLine) {
+	trimmed := strings.TrimLeft(line.raw, " \t")
+	if !strings.HasPrefix(trimmed, "[") {
+		return // Blank line, comment or unknown text
+	}
+
+	closeBracket := strings.IndexByte(trimmed, ']')
+	if closeBracket <= 1 || !strings.HasPrefix(trimmed[closeBracket+1:], "=") {
+		return
+	}
+
+	line.entry = true
+	line.prefix = line.raw[:len(line.raw)-len(trimmed)]
+	line.key = trimmed[1:closeBracket]
+	rest := trimmed[closeBracket+2:]
+
+	if strings.HasPrefix(rest, `"`) {
+		if end := strings.IndexByte(rest[1:], '"'); end >= 0 {
+			line.quoted = true
+			line.value = rest[1 : end+1]
+			line.suffix = rest[end+2:]
+			return
+		}
+	}
+
+	value := strings.TrimRight(rest, " \t")
+	line.value = value
+	line.suffix = rest[len(value):]
+}
+
+// Bytes returns the document in file form
+func (d *INIDocument) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, line := range d.lines {
+		buf.WriteString(line.render())
+		buf.WriteString(line.eol)
+	}
+	return buf.Bytes()
+}
+
+// Keys returns the keys in file order
+func (d *INIDocument) Keys() []string {
+	keys := make([]string, 0, len(d.keys))
+	for _, line := range d.lines {
+		if line.entry && d.keys[line.key] == line {
+			keys = append(keys, line.key)
+		}
+	}
+	return keys
+}
+
+// Has returns whether the document has a key
+func (d *INIDocument) Has(key string) bool {
+	_, exists := d.keys[key]
+	return exists
+}
+
+// GetString returns the value of a key
+func (d *INIDocument) GetString(key string) (string, bool) {
+	line, exists := d.keys[key]
+	if !exists {
+		return "", false
+	}
+	return line.value, true
+}
+
+// GetInt returns the value of a key as an integer
+func (d *INIDocument) GetInt(key string) (int, bool) {
+	value, exists := d.GetString(key)
+	if !exists {
+		return 0, false
+	}
+	intVal, err := strconv.Atoi(strings.TrimSpace(value))
+	return intVal, err == nil
+}
+
+// GetFloat returns the value of a key as a float
+func (d *INIDocument) GetFloat(key string) (float64, bool) {
+	value, exists := d.GetString(key)
+	if !exists {
+		return 0, false
+	}
+	floatVal, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return floatVal, err == nil
+}
+
+// GetBool returns the value of a key as a boolean (non-zero is true)
+func (d *INIDocument) GetBool(key string) (bool, bool) {
+	intVal, ok := d.GetInt(key)
+	return intVal != 0, ok
+}
+
+// SetString sets the value of a key, keeping its position and quoting. New
+// keys are appended in quoted form.
+func (d *INIDocument) SetString(key, value string) error {
+	if key == "" || strings.ContainsAny(key, "[]\r\n") {
+		return fmt.Errorf("invalid INI key %q", key)
+	}
+	if strings.ContainsAny(value, "\"\r\n") {
+		return fmt.Errorf("invalid INI value for %s: %q", key, value)
+	}
+
+	if line, exists := d.keys[key]; exists {
+		if line.value != value {
+			line.value = value
+			line.changed = true
+		}
+		return nil
+	}
+
+	// The last line may have no ending; new lines go after it
+	if count := len(d.lines); count > 0 && d.lines[count-1].eol == "" {
+		d.lines[count-1].eol = d.newline
+	}
+	line := &iniLine{entry: true, key: key, value: value, quoted: true, eol: d.newline, changed: true}
+	d.lines = append(d.lines, line)
+	d.keys[key] = line
+	return nil
+}
+
+// SetInt sets an integer value
+func (d *INIDocument) SetInt(key string, value int) error {
+	return d.SetString(key, strconv.Itoa(value))
+}
+
+// SetFloat sets a float value in the retail format ("-1.000000")
+func (d *INIDocument) SetFloat(key string, value float64) error {
+	return d.SetString(key, strconv.FormatFloat(value, 'f', 6, 64))
+}
+
+// SetBool sets a boolean value as "1" or "0"
+func (d *INIDocument) SetBool(key string, value bool) error {
+	if value {
+		return d.SetString(key, "1")
+	}
+	return d.SetString(key, "0")
+}
+
+// IsModified returns whether any value changed since parsing
+func (d *INIDocument) IsModified() bool {
+	for _, line := range d.lines {
+		if line.changed {
+			return true
+		}
+	}
+	return false
+}
+
+// markSaved makes the current values the original text of their lines
+func (d *INIDocument) markSaved() {
+	for _, line := range d.lines {
+		if line.changed {
+			line.raw = line.render()
+			line.changed = false
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Manager is the layered settings service. The effective configuration is
+// built from the defaults, then the original game INI files, then the user
+// JSON file, then command-line overrides. Only the user layer is saved.
+type Manager struct {
+	config     *Config // Effective configuration
+	configPath string
+
+	base      *Config                    // Defaults and game INI
+	user      map[string]json.RawMessage // Settings chosen by the user (JSON file)
+	overrides map[string]json.RawMessage // Command-line overrides, never saved
+	version   int                        // Version of the loaded file
+
+	gameINI *INIManager // Original INI files of the base layer, nil if absent
+
+	subscribers []func(config *Config)
+}
+
+// NewManager creates a settings service saving the user layer to configPath
+func NewManager(configPath string) *Manager {
+	return &Manager{
+		config:     DefaultConfig(),
+		configPath: configPath,
+		base:       DefaultConfig(),
+		user:       make(map[string]json.RawMessage),
+		overrides:  make(map[string]json.RawMessage),
+		version:    ConfigVersion,
+	}
+}
+
+// Load loads the user layer from the JSON file
+func (m *Manager) Load() error {
+	if _, err := os.Stat(m.configPath); os.IsNotExist(err) {
+		log.Printf("Config file %s not found, using defaults", m.configPath)
+		return nil
+	}
+
+	data, err := os.ReadFile(m.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	user := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &user); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	version, err := migrate(user)
+	if err != nil {
+		return err
+	}
+	fixed, err := m.validateLayer(user)
+	if err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	previous := m.user
+	m.user = user
+	if err := m.rebuild(); err != nil {
+		m.user = previous
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	m.version = max(version, ConfigVersion)
+	log.Printf("Loaded configuration from %s", m.configPath)
+
+	if version < ConfigVersion {
+		// Keep the old file next to the upgraded one
+		backup := fmt.Sprintf("%s.v%d.bak", m.configPath, version)
+		if err := writeFileAtomic(backup, data); err != nil {
+			log.Printf("Warning: failed to back up settings before migration: %v", err)
+		}
+	}
+	if version < ConfigVersion || fixed {
+		return m.Save()
+	}
+	return nil
+}
+
+// validateLayer validates a settings layer on top of the base layer and
+// writes the corrected values back into it. It returns whether anything
+// was corrected.
+func (m *Manager) validateLayer(layer map[string]json.RawMessage) (bool, error) {
+	config := m.base.clone()
+	if err := config.applyLayer(layer); err != nil {
+		return false, err
+	}
+
+	fieldErrors := config.Validate()
+	if len(fieldErrors) == 0 {
+		return false, nil
+	}
+
+	fields := config.fields()
+	for _, fieldErr := range fieldErrors {
+		log.Printf("Warning: setting %v", fieldErr)
+		key, _, _ := strings.Cut(fieldErr.Field, ".")
+		layer[key] = fields[key]
+	}
+	return true, nil
+}
+
+// Save writes the user layer to the JSON file atomically. A file written
+// by a newer build keeps its version, so that build does not migrate the
+// settings it already understands a second time.
+func (m *Manager) Save() error {
+	m.user["version"] = json.RawMessage(fmt.Sprint(m.version))
+
+	data, err := json.MarshalIndent(m.user, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := writeFileAtomic(m.configPath, data); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	log.Printf("Saved configuration to %s", m.configPath)
+	return nil
+}
+
+// GetConfig returns the effective configuration. It must not be modified;
+// use Update to change settings.
+func (m *Manager) GetConfig() *Config {
+	return m.config
+}
+
+// Update changes settings chosen by the user: change is applied to the
+// configuration without command-line overrides, the changed settings are
+// stored in the user layer, subscribers are notified and the file is saved
+func (m *Manager) Update(change func(config *Config)) error {
+	before := m.base.clone()
+	if err := before.applyLayer(m.user); err != nil {
+		return fmt.Errorf("failed to apply user settings: %w", err)
+	}
+	after := before.clone()
+	change(after)
+
+	beforeFields := before.fields()
+	for key, value := range after.fields() {
+		if !bytes.Equal(beforeFields[key], value) {
+			m.user[key] = value
+		}
+	}
+
+	if _, err := m.validateLayer(m.user); err != nil {
+		return fmt.Errorf("failed to apply user settings: %w", err)
+	}
+	if err := m.rebuild(); err != nil {
+		return err
+	}
+	return m.Save()
+}
+
+// SetOverride overrides a setting for this session, by its JSON name (e.g.
+// "bgm_volume"). Values that are not valid JSON are taken as strings.
+// Out-of-range or invalid values are rejected rather than corrected.
+func (m *Manager) SetOverride(key, value string) error {
+	if _, ok := DefaultConfig().fields()[key]; !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	raw := json.RawMessage(value)
+	if !json.Valid(raw) {
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		raw = quoted
+	}
+
+	previous, existed := m.overrides[key]
+	m.overrides[key] = raw
+	err := m.checkOverride(key)
+	if err == nil {
+		err = m.rebuild()
+	}
+	if err != nil {
+		if existed {
+			m.overrides[key] = previous
+		} else {
+			delete(m.overrides, key)
+		}
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// checkOverride validates the configuration with the overrides applied on
+// top of the user layer and returns the first error of a setting
+func (m *Manager) checkOverride(key string) error {
+	config := m.base.clone()
+	if err := config.applyLayer(m.user); err != nil {
+		return err
+	}
+	if err := config.applyLayer(m.overrides); err != nil {
+		return err
+	}
+	for _, fieldErr := range config.Validate() {
+		if field, _, _ := strings.Cut(fieldErr.Field, "."); field == key {
+			return errors.New(fieldErr.Message)
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a function called with the effective configuration
+// now and after every change
+func (m *Manager) Subscribe(subscriber func(config *Config)) {
+	m.subscribers = append(m.subscribers, subscriber)
+	subscriber(m.config)
+}
+
+// setBase replaces the defaults and game INI layer
+func (m *Manager) setBase(base *Config) error {
+	previous := m.base
+	m.base = base
+	if err := m.rebuild(); err != nil {
+		m.base = previous
+		return err
+	}
+	return nil
+}
+
+// rebuild recomputes the effective configuration from the layers and
+// notifies subscribers
+func (m *Manager) rebuild() error {
+	config := m.base.clone()
+	if err := config.applyLayer(m.user); err != nil {
+		return err
+	}
+	if err := config.applyLayer(m.overrides); err != nil {
+		return err
+	}
+	for _, fieldErr := range config.Validate() {
+		log.Printf("Warning: setting %v", fieldErr)
+	}
+
+	// Keep the pointer handed out by GetConfig valid
+	*m.config = *config
+	for _, subscriber := range m.subscribers {
+		subscriber(m.config)
+	}
+	return nil
+}
+
+// GetOverrides returns the command-line overrides as "key=value" strings
+func (m *Manager) GetOverrides() []string {
+	overrides := make([]string, 0, len(m.overrides))
+	for key, value := range m.overrides {
+		overrides = append(overrides, key+"="+string(value))
+	}
+	sort.Strings(overrides)
+	return overrides
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path, so a crash never leaves a half-written file
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// ParseOverride splits a "key=value" command-line override
+func ParseOverride(spec string) (key, value string, err error) {
+	key, value, found := strings.Cut(spec, "=")
+	if !found || strings.TrimSpace(key) == "" {
+		return "", "", fmt.Errorf("override %q is not key=value", spec)
+	}
+	return strings.TrimSpace(key), value, nil
+}
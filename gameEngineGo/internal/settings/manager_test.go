@@ -0,0 +1,256 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadSettings writes a settings file and loads it into a new manager
+func loadSettings(t *testing.T, content string) (*Manager, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(path)
+	if err := manager.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return manager, path
+}
+
+// savedFields reads the fields of a saved settings file, compacted
+func savedFields(t *testing.T, path string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	fields := make(map[string]string, len(raw))
+	for key, value := range raw {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			t.Fatal(err)
+		}
+		fields[key] = compact.String()
+	}
+	return fields
+}
+
+func TestMigrateToV1(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		field  string
+		want   string
+		loaded int
+	}{
+		{
+			name:   "personas lowercased",
+			input:  `{"voice_personas": {"MAK": {"volume": 0.5, "muted": false}}}`,
+			field:  "voice_personas",
+			want:   `{"mak":{"volume":0.5,"muted":false}}`,
+			loaded: 0,
+		},
+		{
+			name:   "lowercase key wins",
+			input:  `{"voice_personas": {"KOT": {"volume": 0.1, "muted": true}, "kot": {"volume": 0.9, "muted": false}}}`,
+			field:  "voice_personas",
+			want:   `{"kot":{"volume":0.9,"muted":false}}`,
+			loaded: 0,
+		},
+		{
+			name:   "bindings trimmed",
+			input:  `{"version": 0, "input_bindings": {" Advance ": ["key:Enter"]}}`,
+			field:  "input_bindings",
+			want:   `{"advance":["key:Enter"]}`,
+			loaded: 0,
+		},
+		{
+			name:   "null field kept",
+			input:  `{"input_bindings": null}`,
+			field:  "input_bindings",
+			want:   `null`,
+			loaded: 0,
+		},
+		{
+			name:   "current version untouched",
+			input:  `{"version": 1, "voice_personas": {"MAK": {"volume": 0.5, "muted": false}}}`,
+			field:  "voice_personas",
+			want:   `{"MAK": {"volume": 0.5, "muted": false}}`,
+			loaded: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields := make(map[string]json.RawMessage)
+			if err := json.Unmarshal([]byte(test.input), &fields); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := migrate(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loaded != test.loaded {
+				t.Errorf("loaded version %d, want %d", loaded, test.loaded)
+			}
+			if got := string(fields["version"]); got != "1" {
+				t.Errorf("version = %s, want 1", got)
+			}
+			if got := string(fields[test.field]); got != test.want {
+				t.Errorf("%s = %s, want %s", test.field, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigratesAndBacksUp(t *testing.T) {
+	original := `{"bgm_volume": 0.25, "voice_personas": {"MAK": {"volume": 0.5, "muted": true}}}`
+	manager, path := loadSettings(t, original)
+
+	if voice, ok := manager.GetConfig().VoicePersonas["mak"]; !ok || voice.Volume != 0.5 || !voice.Muted {
+		t.Errorf("migrated persona = %+v, %v", voice, ok)
+	}
+	if got := savedFields(t, path)["version"]; got != "1" {
+		t.Errorf("saved version %s, want 1", got)
+	}
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+}
+
+func TestLoadCorrectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(config *Config) bool
+		field string
+		saved string
+	}{
+		{"volume above range", `{"bgm_volume": 1.5}`, func(c *Config) bool { return c.BGMVolume == 1 }, "bgm_volume", "1"},
+		{"volume below range", `{"voice_volume": -0.5}`, func(c *Config) bool { return c.VoiceVolume == 0 }, "voice_volume", "0"},
+		{"text speed", `{"text_speed": 9}`, func(c *Config) bool { return c.TextSpeed == 5 }, "text_speed", "5"},
+		{"auto speed", `{"auto_speed": 0}`, func(c *Config) bool { return c.AutoSpeed == 1 }, "auto_speed", "1"},
+		{"skip mode", `{"skip_mode": "never"}`, func(c *Config) bool { return c.SkipMode == "read" }, "skip_mode", `"read"`},
+		{"scaling mode", `{"scaling_mode": "nearest"}`, func(c *Config) bool { return c.ScalingMode == "smooth" }, "scaling_mode", `"smooth"`},
+		{"screen size", `{"screen_width": 0}`, func(c *Config) bool { return c.ScreenWidth == DefaultConfig().ScreenWidth }, "screen_width", ""},
+		{"empty language", `{"language": " "}`, func(c *Config) bool { return c.Language == DefaultConfig().Language }, "language", ""},
+		{
+			"persona volume",
+			`{"voice_personas": {"mak": {"volume": 2, "muted": false}}}`,
+			func(c *Config) bool { return c.VoicePersonas["mak"].Volume == 1 },
+			"voice_personas", `{"mak":{"volume":1,"muted":false}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager, path := loadSettings(t, strings.Replace(test.input, "{", `{"version": 1, `, 1))
+			if !test.check(manager.GetConfig()) {
+				t.Errorf("value not corrected: %+v", manager.GetConfig())
+			}
+			if test.saved == "" {
+				return
+			}
+			if got := savedFields(t, path)[test.field]; got != test.saved {
+				t.Errorf("saved %s = %s, want %s", test.field, got, test.saved)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsMalformedFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not json", `{"bgm_volume": `},
+		{"wrong type", `{"version": 1, "bgm_volume": "loud"}`},
+		{"bad version", `{"version": "one"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "settings.json")
+			if err := os.WriteFile(path, []byte(test.input), 0644); err != nil {
+				t.Fatal(err)
+			}
+			manager := NewManager(path)
+			if err := manager.Load(); err == nil {
+				t.Error("malformed file loaded")
+			}
+			if manager.GetConfig().BGMVolume != DefaultConfig().BGMVolume {
+				t.Error("malformed file changed the configuration")
+			}
+		})
+	}
+}
+
+func TestSaveKeepsNewerVersion(t *testing.T) {
+	manager, path := loadSettings(t, `{"version": 7, "bgm_volume": 0.5, "future_setting": "kept"}`)
+
+	if err := manager.Update(func(config *Config) { config.BGMVolume = 0.25 }); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := savedFields(t, path)
+	if got := fields["version"]; got != "7" {
+		t.Errorf("saved version %s, want 7", got)
+	}
+	if got := fields["future_setting"]; got != `"kept"` {
+		t.Errorf("unknown setting saved as %s", got)
+	}
+	if got := fields["bgm_volume"]; got != "0.25" {
+		t.Errorf("bgm_volume = %s, want 0.25", got)
+	}
+}
+
+func TestUpdateValidatesUserLayer(t *testing.T) {
+	manager := NewManager(filepath.Join(t.TempDir(), "settings.json"))
+	if err := manager.Update(func(config *Config) { config.TextSpeed = 12 }); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(manager.user["text_speed"]); got != "5" {
+		t.Errorf("user text_speed = %s, want 5", got)
+	}
+}
+
+func TestSetOverride(t *testing.T) {
+	tests := []struct {
+		key, value string
+		ok         bool
+	}{
+		{"bgm_volume", "0.5", true},
+		{"language", "ru", true}, // Bare strings are quoted
+		{"skip_mode", "all", true},
+		{"bgm_volume", "1.5", false},
+		{"text_speed", "-1", false},
+		{"skip_mode", "never", false},
+		{"bgm_volume", "loud", false},
+		{"no_such_setting", "1", false},
+	}
+
+	for _, test := range tests {
+		manager := NewManager(filepath.Join(t.TempDir(), "settings.json"))
+		// An invalid user value must not make a valid override fail
+		manager.user["text_speed"] = json.RawMessage("12")
+
+		err := manager.SetOverride(test.key, test.value)
+		if (err == nil) != test.ok {
+			t.Errorf("SetOverride(%s, %s) = %v, want ok %v", test.key, test.value, err, test.ok)
+		}
+		if !test.ok && len(manager.GetOverrides()) != 0 {
+			t.Errorf("SetOverride(%s, %s) kept the rejected value: %v", test.key, test.value, manager.GetOverrides())
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// ConfigVersion is the version of the settings file written by this build
+const ConfigVersion = 1
+
+// migration upgrades the raw settings of one file version to the next
+type migration func(fields map[string]json.RawMessage) error
+
+// migrations[n] upgrades version n to n+1. Files without a version field
+// are version 0.
+var migrations = []migration{
+	migrateToV1,
+}
+
+// migrate upgrades raw user settings to ConfigVersion. It returns the
+// version the file had.
+func migrate(fields map[string]json.RawMessage) (int, error) {
+	version := 0
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return 0, fmt.Errorf("invalid settings version: %w", err)
+		}
+	}
+
+	if version > ConfigVersion {
+		log.Printf("Warning: settings file version %d is newer than supported version %d", version, ConfigVersion)
+		return version, nil
+	}
+
+	for from := version; from < ConfigVersion; from++ {
+		if err := migrations[from](fields); err != nil {
+			return version, fmt.Errorf("failed to migrate settings from version %d: %w", from, err)
+		}
+		log.Printf("Migrated settings from version %d to %d", from, from+1)
+	}
+
+	fields["version"] = json.RawMessage(fmt.Sprint(ConfigVersion))
+	return version, nil
+}
+
+// migrateToV1 lowercases the keys of per-character voices and input
+// bindings. Unversioned files could hold "MAK" and "mak" side by side,
+// and which one applied depended on map order; the lowercase key wins.
+func migrateToV1(fields map[string]json.RawMessage) error {
+	for _, field := range []string{"voice_personas", "input_bindings"} {
+		raw, ok := fields[field]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+
+		lowered := make(map[string]json.RawMessage, len(entries))
+		for key, value := range entries {
+			lower := strings.ToLower(strings.TrimSpace(key))
+			if _, exists := lowered[lower]; exists && key != lower {
+				continue
+			}
+			lowered[lower] = value
+		}
+
+		data, err := json.Marshal(lowered)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		fields[field] = data
+	}
+	return nil
+}
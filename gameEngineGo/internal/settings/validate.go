@@ -0,0 +1,107 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a setting that was out of range or invalid, and what
+// was done about it
+type FieldError struct {
+	Field   string // JSON name of the setting
+	Message string
+	Fixed   string // Value used instead
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (using %s)", e.Field, e.Message, e.Fixed)
+}
+
+// validator collects field errors while checking a configuration
+type validator struct {
+	defaults *Config
+	errors   []FieldError
+}
+
+// Validate checks every setting, clamping values that are out of range and
+// replacing invalid ones with their defaults. It returns one error per
+// field that was changed.
+func (c *Config) Validate() []FieldError {
+	v := &validator{defaults: DefaultConfig()}
+
+	// Screen: the logical resolution must be usable, window sizes are optional
+	v.positive("screen_width", &c.ScreenWidth, v.defaults.ScreenWidth)
+	v.positive("screen_height", &c.ScreenHeight, v.defaults.ScreenHeight)
+	v.clampInt("window_width", &c.WindowWidth, 0, 1<<15)
+	v.clampInt("window_height", &c.WindowHeight, 0, 1<<15)
+	v.oneOf("scaling_mode", &c.ScalingMode, v.defaults.ScalingMode, "smooth", "integer")
+
+	// Audio
+	v.clampFloat("bgm_volume", &c.BGMVolume, 0.0, 1.0)
+	v.clampFloat("sfx_volume", &c.SFXVolume, 0.0, 1.0)
+	v.clampFloat("voice_volume", &c.VoiceVolume, 0.0, 1.0)
+	v.clampFloat("ducking_amount", &c.DuckingAmount, 0.0, 1.0)
+	v.clampInt("ducking_attack_ms", &c.DuckingAttackMs, 0, 10000)
+	v.clampInt("ducking_release_ms", &c.DuckingReleaseMs, 0, 10000)
+	for persona, voice := range c.VoicePersonas {
+		v.clampFloat("voice_personas."+persona+".volume", &voice.Volume, 0.0, 1.0)
+		c.VoicePersonas[persona] = voice
+	}
+
+	// Text and playback
+	if strings.TrimSpace(c.Language) == "" {
+		v.reject("language", "is empty", &c.Language, v.defaults.Language)
+	}
+	v.clampInt("text_speed", &c.TextSpeed, 0, 5)
+	v.clampInt("auto_speed", &c.AutoSpeed, 1, 5)
+	v.oneOf("skip_mode", &c.SkipMode, v.defaults.SkipMode, "read", "all")
+	v.clampInt("preload_seconds", &c.PreloadSeconds, 0, 60)
+
+	return v.errors
+}
+
+// positive rejects values below 1
+func (v *validator) positive(field string, value *int, fallback int) {
+	if *value < 1 {
+		*value = fallback
+		v.add(field, "must be positive", *value)
+	}
+}
+
+// clampInt limits an integer setting to [low, high]
+func (v *validator) clampInt(field string, value *int, low, high int) {
+	if *value < low || *value > high {
+		*value = max(low, min(high, *value))
+		v.add(field, fmt.Sprintf("out of range %d to %d", low, high), *value)
+	}
+}
+
+// clampFloat limits a float setting to [low, high]
+func (v *validator) clampFloat(field string, value *float64, low, high float64) {
+	if *value < low || *value > high {
+		*value = max(low, min(high, *value))
+		v.add(field, fmt.Sprintf("out of range %g to %g", low, high), *value)
+	}
+}
+
+// oneOf rejects strings that are not one of the allowed values
+func (v *validator) oneOf(field string, value *string, fallback string, allowed ...string) {
+	for _, candidate := range allowed {
+		if *value == candidate {
+			return
+		}
+	}
+	v.reject(field, fmt.Sprintf("%q is not one of %s", *value, strings.Join(allowed, ", ")), value, fallback)
+}
+
+// reject replaces a string setting with its fallback
+func (v *validator) reject(field, message string, value *string, fallback string) {
+	*value = fallback
+	v.add(field, message, fmt.Sprintf("%q", fallback))
+}
+
+// add records a field error
+func (v *validator) add(field, message string, fixed any) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: message, Fixed: fmt.Sprint(fixed)})
+}
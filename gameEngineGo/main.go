@@ -1,14 +1,241 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"school-days-engine/internal/engine"
 )
 
+// launchOptions holds the parsed command line
+type launchOptions struct {
+	root, config string
+	language     string
+	scene        string
+	at           int64
+	fullscreen   bool
+	windowed     bool
+	headless     bool
+	logLevel     int
+	debug        bool
+	hotReload    bool
+	capture      bool
+	captureFrom  int64
+	captureTo    int64
+	record       string
+	replay       string
+	overrides    []string
+}
+
 func main() {
+	options, err := parseLaunchFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+
+	// Messages carry their level as a fixed "Warning: "/"Error: " tag; the
+	// writer filters on it and adds the timestamp itself
+	log.SetFlags(0)
+	log.SetOutput(&levelWriter{out: os.Stderr, level: options.logLevel, now: time.Now})
+
 	game := engine.NewGame()
+	game.SetRootDir(options.root)
+	game.SetConfigPath(options.config)
+	game.SetStartScene(options.scene, options.at)
+	game.SetHeadless(options.headless)
+	game.SetDebugOverlay(options.debug)
+	game.SetHotReload(options.hotReload)
+	if options.capture {
+		if err := game.CaptureTimeRange(options.captureFrom, options.captureTo); err != nil {
+			fmt.Fprintf(os.Stderr, "-capture: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	game.SetRecording(options.record)
+	game.SetReplay(options.replay)
+
+	// Launch flags are setting overrides; explicit -set flags come last and win
+	if options.language != "" {
+		game.SetOverride("language=" + strconv.Quote(options.language))
+	}
+	if options.fullscreen || options.windowed {
+		game.SetOverride("fullscreen=" + strconv.FormatBool(options.fullscreen))
+	}
+	for _, spec := range options.overrides {
+		game.SetOverride(spec)
+	}
+
 	if err := game.Run(); err != nil {
-		log.Fatal(err)
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// parseLaunchFlags parses the command line. Errors and usage are written to
+// output; flag.ErrHelp is returned for -h.
+func parseLaunchFlags(args []string, output io.Writer) (*launchOptions, error) {
+	options := &launchOptions{}
+	flags := flag.NewFlagSet("school-days-engine", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	flags.StringVar(&options.root, "root", "./", "directory holding the game data (GPK archives and loose files)")
+	flags.StringVar(&options.config, "config", "./config/settings.json", "user settings file")
+	flags.StringVar(&options.language, "lang", "", "script and menu language for this run")
+	flags.StringVar(&options.scene, "scene", "", "start a JRS script directly, skipping the menus")
+	flags.Int64Var(&options.at, "at", 0, "timeline position in ms to start -scene at")
+	flags.BoolVar(&options.fullscreen, "fullscreen", false, "start in fullscreen for this run")
+	flags.BoolVar(&options.windowed, "windowed", false, "start in a window for this run")
+	flags.BoolVar(&options.headless, "headless", false, "run without a window or drawing (e.g. with -replay)")
+	logLevel := flags.String("log-level", "info", "lowest log level shown: info, warning or error")
+	flags.BoolVar(&options.debug, "debug", false, "show the debug overlay")
+	flags.BoolVar(&options.hotReload, "hot-reload", false, "reload edited loose scripts and textures while running")
+	capture := flags.String("capture", "", "dump the frames drawn while the timeline is in a range to PNGs, as <from>-<to> in ms")
+	flags.StringVar(&options.record, "record", "", "record input to a file for replay")
+	flags.StringVar(&options.replay, "replay", "", "replay input recorded with -record and check the game reaches the same states")
+	flags.Var((*settingFlags)(&options.overrides), "set", "override a setting for this run as key=value (repeatable)")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// usage reports an invalid combination like the flag package reports
+	// an invalid flag
+	usage := func(format string, args ...any) error {
+		err := fmt.Errorf(format, args...)
+		fmt.Fprintln(output, err)
+		flags.Usage()
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return nil, usage("unexpected argument %q", flags.Arg(0))
+	}
+	level, err := parseLogLevel(*logLevel)
+	if err != nil {
+		return nil, usage("-log-level: %v", err)
+	}
+	options.logLevel = level
+
+	atSet := false
+	flags.Visit(func(f *flag.Flag) {
+		atSet = atSet || f.Name == "at"
+	})
+	if atSet && options.scene == "" {
+		return nil, usage("-at needs -scene")
+	}
+	if options.at < 0 {
+		return nil, usage("-at must not be negative")
+	}
+	if options.fullscreen && options.windowed {
+		return nil, usage("-fullscreen and -windowed cannot be used together")
+	}
+	if *capture != "" {
+		if options.headless {
+			return nil, usage("-capture needs drawing and cannot be used with -headless")
+		}
+		options.captureFrom, options.captureTo, err = parseTimeRange(*capture)
+		if err != nil {
+			return nil, usage("-capture: %v", err)
+		}
+		options.capture = true
+	}
+	return options, nil
+}
+
+// settingFlags collects repeated -set flags
+type settingFlags []string
+
+func (f *settingFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *settingFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseTimeRange parses a "<from>-<to>" range of timeline positions in ms
+func parseTimeRange(spec string) (int64, int64, error) {
+	fromText, toText, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected <from>-<to> in ms, got %q", spec)
+	}
+	from, err := strconv.ParseInt(strings.TrimSpace(fromText), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start %q", fromText)
+	}
+	to, err := strconv.ParseInt(strings.TrimSpace(toText), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end %q", toText)
+	}
+	return from, to, nil
+}
+
+// Log levels, matched by the tag a message starts with ("Warning: ...",
+// "Error: ...")
+const (
+	levelInfo = iota
+	levelWarning
+	levelError
+)
+
+// Level tags of log messages
+var (
+	tagWarning = []byte("Warning: ")
+	tagError   = []byte("Error: ")
+)
+
+// parseLogLevel converts a -log-level value
+func parseLogLevel(name string) (int, error) {
+	switch strings.ToLower(name) {
+	case "info", "debug":
+		return levelInfo, nil
+	case "warning", "warn":
+		return levelWarning, nil
+	case "error":
+		return levelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (use info, warning or error)", name)
+	}
+}
+
+// levelWriter drops log messages below a level and prefixes the others with
+// the time. The logger must have no flags, so messages start at their tag.
+type levelWriter struct {
+	out   io.Writer
+	level int
+	now   func() time.Time
+}
+
+func (w *levelWriter) Write(message []byte) (int, error) {
+	if messageLevel(message) < w.level {
+		return len(message), nil
+	}
+	if _, err := fmt.Fprintf(w.out, "%s %s", w.now().Format("2006/01/02 15:04:05"), message); err != nil {
+		return 0, err
+	}
+	return len(message), nil
+}
+
+// messageLevel finds the level of a log message from the tag it starts with;
+// a tag elsewhere in the message (e.g. quoted script text) does not count
+func messageLevel(message []byte) int {
+	switch {
+	case bytes.HasPrefix(message, tagError):
+		return levelError
+	case bytes.HasPrefix(message, tagWarning):
+		return levelWarning
+	default:
+		return levelInfo
 	}
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		spec     string
+		from, to int64
+		ok       bool
+	}{
+		{"1000-5000", 1000, 5000, true},
+		{" 0 - 250 ", 0, 250, true},
+		{"1000", 0, 0, false},
+		{"a-5000", 0, 0, false},
+		{"1000-b", 0, 0, false},
+	}
+	for _, test := range tests {
+		from, to, err := parseTimeRange(test.spec)
+		if (err == nil) != test.ok {
+			t.Errorf("parseTimeRange(%q) error = %v, want ok %v", test.spec, err, test.ok)
+			continue
+		}
+		if test.ok && (from != test.from || to != test.to) {
+			t.Errorf("parseTimeRange(%q) = %d, %d, want %d, %d", test.spec, from, to, test.from, test.to)
+		}
+	}
+}
+
+func TestParseLaunchFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		ok    bool
+		check func(options *launchOptions) bool
+	}{
+		{"defaults", nil, true, func(o *launchOptions) bool {
+			return o.root == "./" && o.config == "./config/settings.json" && o.logLevel == levelInfo && !o.capture
+		}},
+		{"scene at", []string{"-scene", "Script/00/00-00-A00.jrs", "-at", "12000"}, true, func(o *launchOptions) bool {
+			return o.scene == "Script/00/00-00-A00.jrs" && o.at == 12000
+		}},
+		{"repeated set", []string{"-set", "bgm_volume=0.5", "-set", "muted=true"}, true, func(o *launchOptions) bool {
+			return len(o.overrides) == 2 && o.overrides[1] == "muted=true"
+		}},
+		{"log level", []string{"-log-level", "Warning"}, true, func(o *launchOptions) bool {
+			return o.logLevel == levelWarning
+		}},
+		{"capture", []string{"-capture", "1000-2000"}, true, func(o *launchOptions) bool {
+			return o.capture && o.captureFrom == 1000 && o.captureTo == 2000
+		}},
+		{"at without scene", []string{"-at", "5000"}, false, nil},
+		{"zero at without scene", []string{"-at", "0"}, false, nil},
+		{"negative at", []string{"-scene", "a.jrs", "-at", "-1"}, false, nil},
+		{"fullscreen and windowed", []string{"-fullscreen", "-windowed"}, false, nil},
+		{"unknown log level", []string{"-log-level", "verbose"}, false, nil},
+		{"bad capture", []string{"-capture", "1000"}, false, nil},
+		{"headless capture", []string{"-headless", "-capture", "1000-2000"}, false, nil},
+		{"unknown flag", []string{"-nope"}, false, nil},
+		{"stray argument", []string{"-debug", "extra"}, false, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var output bytes.Buffer
+			options, err := parseLaunchFlags(test.args, &output)
+			if (err == nil) != test.ok {
+				t.Fatalf("error = %v, want ok %v", err, test.ok)
+			}
+			if !test.ok {
+				if !strings.Contains(output.String(), "Usage") {
+					t.Errorf("no usage printed for a usage error:\n%s", output.String())
+				}
+				return
+			}
+			if !test.check(options) {
+				t.Errorf("unexpected options %+v", options)
+			}
+		})
+	}
+
+	if _, err := parseLaunchFlags([]string{"-h"}, io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("-h returned %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestLevelWriter(t *testing.T) {
+	now := func() time.Time { return time.Date(2026, 10, 18, 12, 30, 0, 0, time.UTC) }
+	messages := []string{
+		"Loaded configuration\n",
+		"Warning: failed to load BGM\n",
+		"Error: failed to initialize audio\n",
+		"Showing text \"Warning: wet floor\"\n", // Tag inside the message
+	}
+
+	tests := []struct {
+		level int
+		want  []string
+	}{
+		{levelInfo, messages},
+		{levelWarning, messages[1:3]},
+		{levelError, messages[2:3]},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		writer := &levelWriter{out: &out, level: test.level, now: now}
+		for _, message := range messages {
+			if n, err := writer.Write([]byte(message)); err != nil || n != len(message) {
+				t.Fatalf("Write = %d, %v", n, err)
+			}
+		}
+
+		var want strings.Builder
+		for _, message := range test.want {
+			want.WriteString("2026/10/18 12:30:00 " + message)
+		}
+		if out.String() != want.String() {
+			t.Errorf("level %d wrote\n%s\nwant\n%s", test.level, out.String(), want.String())
+		}
+	}
+}